@@ -0,0 +1,213 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+const (
+	streamBinaryVersion = 0x01
+	// streamMarker fills the byte of the header that is always empty for a
+	// single Resource, so a stream is never mistaken for one.
+	streamMarker = 0x01
+	// maxRecordLen bounds the length of a single record, so a corrupt
+	// length cannot cause an enormous allocation.
+	maxRecordLen = 1 << 16
+)
+
+// A BinaryEncoder writes Resources to a stream in a portable binary format.
+// The stream starts with a header like that of a single Resource: the
+// MagicBytes, a version byte, and a marker byte of 0x01. Each record is a
+// varint length, the output of the Resource's MarshalBinary method, and a
+// big-endian CRC-32 (IEEE) checksum of that output. The stream ends with a
+// trailer of a zero length, which no record has, and a varint count of the
+// records, so that a stream cut off between records is not mistaken for a
+// complete one. Streams of version 0x00 have no trailer.
+type BinaryEncoder struct {
+	w           io.Writer
+	buf         []byte
+	wroteHeader bool
+	count       uint64
+	closed      bool
+}
+
+// NewBinaryEncoder returns a new BinaryEncoder that writes to w. The header
+// is written along with the first Resource.
+func NewBinaryEncoder(w io.Writer) *BinaryEncoder {
+	return &BinaryEncoder{w: w}
+}
+
+// Encode writes a record for the Resource to the stream.
+func (enc *BinaryEncoder) Encode(r Resource) error {
+	if enc.closed {
+		return fmt.Errorf("encoding resource to closed stream")
+	}
+	payload, err := r.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("marshaling resource to binary: %w", err)
+	}
+
+	b := enc.header(enc.buf[:0])
+	var n [binary.MaxVarintLen64]byte
+	b = append(b, n[:binary.PutUvarint(n[:], uint64(len(payload)))]...)
+	b = append(b, payload...)
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(payload))
+	b = append(b, sum[:]...)
+	enc.buf = b
+
+	if _, err := enc.w.Write(b); err != nil {
+		return fmt.Errorf("writing resource to stream: %w", err)
+	}
+	enc.wroteHeader = true
+	enc.count++
+	return nil
+}
+
+// Close writes the trailer that ends the stream, after the header if no
+// Resource was encoded. It does not close the underlying io.Writer, and no
+// Resource may be encoded after it.
+func (enc *BinaryEncoder) Close() error {
+	if enc.closed {
+		return nil
+	}
+	b := append(enc.header(enc.buf[:0]), 0x00)
+	b = binary.AppendUvarint(b, enc.count)
+	enc.buf = b
+
+	if _, err := enc.w.Write(b); err != nil {
+		return fmt.Errorf("writing end of stream: %w", err)
+	}
+	enc.wroteHeader = true
+	enc.closed = true
+	return nil
+}
+
+// header appends the stream header to b, unless it was already written.
+func (enc *BinaryEncoder) header(b []byte) []byte {
+	if enc.wroteHeader {
+		return b
+	}
+	return append(b, MagicBytes[0], MagicBytes[1], streamBinaryVersion, streamMarker)
+}
+
+// A BinaryDecoder reads Resources from a stream written by a BinaryEncoder.
+type BinaryDecoder struct {
+	r          *bufio.Reader
+	buf        []byte
+	readHeader bool
+	version    byte
+	record     int
+	done       bool
+}
+
+// NewBinaryDecoder returns a new BinaryDecoder that reads from r.
+func NewBinaryDecoder(r io.Reader) *BinaryDecoder {
+	return &BinaryDecoder{r: bufio.NewReader(r)}
+}
+
+// Decode reads the next Resource from the stream. At the trailer that ends
+// the stream, or if the stream is empty, it returns io.EOF. A stream that
+// is truncated, even between records, or fails its checksum returns an
+// error for which CorruptError is true.
+func (dec *BinaryDecoder) Decode(r *Resource) error {
+	if dec.done {
+		return io.EOF
+	}
+	if !dec.readHeader {
+		var header [4]byte
+		switch _, err := io.ReadFull(dec.r, header[:]); err {
+		case nil:
+		case io.EOF:
+			return io.EOF
+		default:
+			return streamError{fmt.Errorf("reading stream header: %w", err), true}
+		}
+		switch {
+		case !bytes.Equal(header[0:2], MagicBytes[:]):
+			return fmt.Errorf("unexpected magic bytes")
+		case header[2] > streamBinaryVersion:
+			return fmt.Errorf("unexpected version number for binary stream format")
+		case header[3] != streamMarker:
+			return fmt.Errorf("not a binary stream")
+		default:
+		}
+		dec.readHeader = true
+		dec.version = header[2]
+	}
+
+	length, err := binary.ReadUvarint(dec.r)
+	switch {
+	case err == io.EOF && dec.version == 0x00:
+		return io.EOF
+	case err == io.EOF:
+		return streamError{fmt.Errorf("after record %d: missing end of stream", dec.record), true}
+	case err == nil && length == 0 && dec.version > 0x00:
+		return dec.trailer()
+	}
+	dec.record++
+	if err != nil {
+		return streamError{fmt.Errorf("record %d: reading length: %w", dec.record, err), true}
+	}
+	if length > maxRecordLen {
+		return streamError{fmt.Errorf("record %d: length %d too long", dec.record, length), true}
+	}
+
+	if uint64(cap(dec.buf)) < length+4 {
+		dec.buf = make([]byte, length+4)
+	}
+	b := dec.buf[:length+4]
+	if _, err := io.ReadFull(dec.r, b); err != nil {
+		return streamError{fmt.Errorf("record %d: reading payload: %w", dec.record, err), true}
+	}
+	payload := b[:length]
+	if sum := binary.BigEndian.Uint32(b[length:]); sum != crc32.ChecksumIEEE(payload) {
+		return streamError{fmt.Errorf("record %d: checksum mismatch", dec.record), true}
+	}
+	if err := r.UnmarshalBinary(payload); err != nil {
+		return fmt.Errorf("record %d: parsing resource from binary: %w", dec.record, err)
+	}
+	return nil
+}
+
+// trailer reads the record count that ends the stream, after its zero
+// length.
+func (dec *BinaryDecoder) trailer() error {
+	count, err := binary.ReadUvarint(dec.r)
+	if err != nil {
+		return streamError{fmt.Errorf("after record %d: reading end of stream: %w", dec.record, err), true}
+	}
+	if count != uint64(dec.record) {
+		return streamError{fmt.Errorf("after record %d: end of stream counts %d records", dec.record, count), true}
+	}
+	dec.done = true
+	return io.EOF
+}
+
+type streamError struct {
+	err     error
+	corrupt bool
+}
+
+func (e streamError) Error() string {
+	return fmt.Sprintf("binary stream error: %+v", e.err)
+}
+
+func (e streamError) Corrupt() bool {
+	return e.corrupt
+}
+
+func (e streamError) Unwrap() error {
+	return e.err
+}
+
+func (e streamError) Is(target error) bool {
+	return target == ErrCorrupt && e.corrupt
+}
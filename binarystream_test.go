@@ -0,0 +1,161 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/quick"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+func TestBinaryEncoderDecoder(t *testing.T) {
+	f := func(resources []faststatus.Resource) bool {
+		var buf bytes.Buffer
+		enc := faststatus.NewBinaryEncoder(&buf)
+		for _, r := range resources {
+			if err := enc.Encode(r); err != nil {
+				return false
+			}
+		}
+		if err := enc.Close(); err != nil {
+			return false
+		}
+		dec := faststatus.NewBinaryDecoder(&buf)
+		for _, want := range resources {
+			var got faststatus.Resource
+			if err := dec.Decode(&got); err != nil || !got.Equal(want) {
+				return false
+			}
+		}
+		return dec.Decode(new(faststatus.Resource)) == io.EOF
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBinaryEncoderHeader(t *testing.T) {
+	var buf bytes.Buffer
+	enc := faststatus.NewBinaryEncoder(&buf)
+	if buf.Len() != 0 {
+		t.Fatalf("wrote %d bytes before any resource, expected none", buf.Len())
+	}
+	for i := 0; i < 2; i++ {
+		if err := enc.Encode(faststatus.NewResource()); err != nil {
+			t.Fatalf("Encode() = %+v, expected no error", err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close() = %+v, expected no error", err)
+	}
+	b := buf.Bytes()
+	if !bytes.Equal(b[0:2], faststatus.MagicBytes[:]) || b[2] != 0x01 || b[3] != 0x01 {
+		t.Fatalf("header % x, expected magic bytes, version 1 and a stream marker", b[0:4])
+	}
+	// header, then two records of a length byte, 36 bytes and a checksum,
+	// then a zero length and a count of two
+	if want := 4 + 2*(1+36+4) + 2; len(b) != want {
+		t.Fatalf("encoded %d bytes, expected %d", len(b), want)
+	}
+	if trailer := b[len(b)-2:]; !bytes.Equal(trailer, []byte{0x00, 0x02}) {
+		t.Fatalf("trailer % x, expected 00 02", trailer)
+	}
+	if err := enc.Encode(faststatus.NewResource()); err == nil {
+		t.Fatalf("Encode() after Close() = <nil>, expected error")
+	}
+}
+
+func TestBinaryEncoderCloseEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := faststatus.NewBinaryEncoder(&buf).Close(); err != nil {
+		t.Fatalf("Close() = %+v, expected no error", err)
+	}
+	dec := faststatus.NewBinaryDecoder(&buf)
+	if err := dec.Decode(new(faststatus.Resource)); err != io.EOF {
+		t.Fatalf("Decode() = %+v, expected io.EOF", err)
+	}
+}
+
+func TestBinaryEncoderWriteError(t *testing.T) {
+	enc := faststatus.NewBinaryEncoder(errWriter{})
+	if err := enc.Encode(faststatus.NewResource()); err == nil {
+		t.Fatalf("Encode() = <nil>, expected error")
+	}
+}
+
+func TestBinaryDecoderBadData(t *testing.T) {
+	var buf bytes.Buffer
+	enc := faststatus.NewBinaryEncoder(&buf)
+	for i := 0; i < 2; i++ {
+		enc.Encode(faststatus.NewResource())
+	}
+	enc.Close()
+	stream := buf.Bytes()
+	modified := func(fn func(b []byte) []byte) []byte {
+		return fn(append([]byte{}, stream...))
+	}
+	// a record boundary, just before the trailer
+	boundary := len(stream) - 2
+
+	testCases := []struct {
+		name        string
+		input       []byte
+		wantCount   int
+		wantEOF     bool
+		wantCorrupt bool
+	}{
+		{"empty", nil, 0, true, false},
+		{"version 0 without trailer", modified(func(b []byte) []byte { b[2] = 0x00; return b[:boundary] }), 2, true, false},
+		{"truncated header", stream[:2], 0, false, true},
+		{"bad magic bytes", modified(func(b []byte) []byte { b[0] = 0x00; return b }), 0, false, false},
+		{"future version", modified(func(b []byte) []byte { b[2] = 0xff; return b }), 0, false, false},
+		{"single resource", func() []byte { b, _ := faststatus.NewResource().MarshalBinary(); return b }(), 0, false, false},
+		{"truncated length", modified(func(b []byte) []byte { b[45] = 0x80; return b[:46] }), 1, false, true},
+		{"truncated payload", stream[:len(stream)-20], 1, false, true},
+		{"truncated checksum", stream[:boundary-1], 1, false, true},
+		{"flipped bit", modified(func(b []byte) []byte { b[boundary-10] ^= 0x04; return b }), 1, false, true},
+		{"bad checksum", modified(func(b []byte) []byte { b[boundary-1]++; return b }), 1, false, true},
+		{"truncated at record boundary", stream[:boundary], 2, false, true},
+		{"truncated after first record", stream[:45], 1, false, true},
+		{"truncated trailer", stream[:boundary+1], 2, false, true},
+		{"wrong count", modified(func(b []byte) []byte { b[len(b)-1] = 0x03; return b }), 2, false, true},
+		{"enormous length", modified(func(b []byte) []byte {
+			return append(b[:45], 0xff, 0xff, 0xff, 0xff, 0x0f)
+		}), 1, false, true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dec := faststatus.NewBinaryDecoder(bytes.NewReader(tc.input))
+			var (
+				count int
+				err   error
+			)
+			for err == nil {
+				var r faststatus.Resource
+				if err = dec.Decode(&r); err == nil {
+					count++
+				}
+			}
+			if count != tc.wantCount {
+				t.Fatalf("decoded %d resources, expected %d", count, tc.wantCount)
+			}
+			if tc.wantEOF {
+				if err != io.EOF {
+					t.Fatalf("Decode() = %+v, expected io.EOF", err)
+				}
+				return
+			}
+			if err == io.EOF {
+				t.Fatalf("Decode() = io.EOF, expected error")
+			}
+			if got := faststatus.CorruptError(err); got != tc.wantCorrupt {
+				t.Fatalf("CorruptError(%+v) = %t, expected %t", err, got, tc.wantCorrupt)
+			}
+		})
+	}
+}
@@ -0,0 +1,518 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// CBOR (RFC 8949) major types and tags used by the CBOR encoding.
+const (
+	cborUint   = 0 << 5
+	cborNegInt = 1 << 5
+	cborBytes  = 2 << 5
+	cborText   = 3 << 5
+	cborArray  = 4 << 5
+	cborMap    = 5 << 5
+	cborTag    = 6 << 5
+	cborSimple = 7 << 5
+
+	cborNull    = cborSimple | 22
+	cborFloat16 = cborSimple | 25
+	cborFloat32 = cborSimple | 26
+	cborFloat64 = cborSimple | 27
+
+	cborTagEpoch    = 1
+	cborTagUUID     = 37
+	cborTagExtended = 1001
+)
+
+// Keys of a tag 1001 extended time (RFC 9581) map.
+const (
+	cborTimeSeconds = 1
+	cborTimeMillis  = -3
+	cborTimeMicros  = -6
+	cborTimeNanos   = -9
+)
+
+// MarshalCBOR encodes an ID as a CBOR byte string with tag 37, the tag for
+// a binary UUID.
+func (id ID) MarshalCBOR() ([]byte, error) {
+	return id.appendCBOR(make([]byte, 0, 19)), nil
+}
+
+func (id ID) appendCBOR(b []byte) []byte {
+	b = appendCBORHead(b, cborTag, cborTagUUID)
+	b = appendCBORHead(b, cborBytes, 16)
+	return append(b, id[:]...)
+}
+
+// UnmarshalCBOR decodes an ID from a CBOR byte string of 16 bytes, which
+// may have tag 37.
+func (id *ID) UnmarshalCBOR(b []byte) error {
+	r := cborReader{b}
+	tmp, err := r.id()
+	if err != nil {
+		return err
+	}
+	if len(r.b) > 0 {
+		return fmt.Errorf("unexpected data after CBOR ID")
+	}
+	*id = tmp
+	return nil
+}
+
+// MarshalCBOR encodes a Status as a CBOR unsigned integer. An invalid
+// Status (out of range, etc.) will result in an error.
+func (s Status) MarshalCBOR() ([]byte, error) {
+	if s > Unknown {
+		return nil, errOutOfRange
+	}
+	return appendCBORHead(nil, cborUint, uint64(s)), nil
+}
+
+// UnmarshalCBOR decodes a Status from a CBOR unsigned integer.
+func (s *Status) UnmarshalCBOR(b []byte) error {
+	r := cborReader{b}
+	tmp, err := r.status()
+	if err != nil {
+		return err
+	}
+	if len(r.b) > 0 {
+		return fmt.Errorf("unexpected data after CBOR Status")
+	}
+	*s = tmp
+	return nil
+}
+
+// MarshalCBOR encodes a Resource as a CBOR map with the keys "id", "status"
+// and "since", like the json structure, and "labels", "message" and
+// "until" when they are set. Since and Until are tag 1 epoch times when a
+// whole number of seconds, and otherwise tag 1001 extended times holding
+// the seconds and nanoseconds as integers, so no precision is lost. A
+// zero-value Since is null.
+func (r Resource) MarshalCBOR() ([]byte, error) {
+	if r.Status > Unknown {
+		return nil, fmt.Errorf("marshaling Status to CBOR: %w", errOutOfRange)
+	}
+	if err := checkMessage(r.Message); err != nil {
+		return nil, fmt.Errorf("marshaling Message to CBOR: %w", err)
+	}
+	if err := checkUntil(r.Since, r.Until); err != nil {
+		return nil, fmt.Errorf("marshaling Until to CBOR: %w", err)
+	}
+	n := uint64(3)
+	for _, set := range []bool{len(r.Labels) > 0, r.Message != "", !r.Until.IsZero()} {
+		if set {
+			n++
+		}
+	}
+
+	b := make([]byte, 0, 48)
+	b = appendCBORHead(b, cborMap, n)
+	b = appendCBORText(b, "id")
+	b = r.ID.appendCBOR(b)
+	b = appendCBORText(b, "status")
+	b = appendCBORHead(b, cborUint, uint64(r.Status))
+	b = appendCBORText(b, "since")
+	b = appendCBORTime(b, r.Since)
+	if len(r.Labels) > 0 {
+		b = appendCBORText(b, "labels")
+		b = appendCBORHead(b, cborMap, uint64(len(r.Labels)))
+		for _, k := range sortedKeys(r.Labels) {
+			b = appendCBORText(appendCBORText(b, k), r.Labels[k])
+		}
+	}
+	if r.Message != "" {
+		b = appendCBORText(appendCBORText(b, "message"), r.Message)
+	}
+	if !r.Until.IsZero() {
+		b = appendCBORTime(appendCBORText(b, "until"), r.Until)
+	}
+	return b, nil
+}
+
+// appendCBORTime appends t as a tag 1 epoch time, or as a tag 1001
+// extended time if it is not a whole number of seconds, or null for the
+// zero time.
+func appendCBORTime(b []byte, t time.Time) []byte {
+	switch {
+	case t.IsZero():
+		return append(b, cborNull)
+	case t.Nanosecond() == 0:
+		b = appendCBORHead(b, cborTag, cborTagEpoch)
+		return appendCBORInt(b, t.Unix())
+	default:
+		b = appendCBORHead(b, cborTag, cborTagExtended)
+		b = appendCBORHead(b, cborMap, 2)
+		b = appendCBORInt(appendCBORInt(b, cborTimeSeconds), t.Unix())
+		return appendCBORInt(appendCBORInt(b, cborTimeNanos), int64(t.Nanosecond()))
+	}
+}
+
+// UnmarshalCBOR decodes a Resource from a CBOR map matching the output of
+// the `MarshalCBOR` method. Unknown keys are skipped, and missing keys are
+// left as the zero value.
+func (r *Resource) UnmarshalCBOR(b []byte) error {
+	cr := cborReader{b}
+	major, n, err := cr.head()
+	if err != nil {
+		return err
+	}
+	if major != cborMap {
+		return fmt.Errorf("resource must be a CBOR map")
+	}
+
+	tmp := Resource{}
+	for i := uint64(0); i < n; i++ {
+		key, err := cr.text()
+		if err != nil {
+			return fmt.Errorf("parsing CBOR map key: %w", err)
+		}
+		switch key {
+		case "id":
+			if tmp.ID, err = cr.id(); err != nil {
+				return fmt.Errorf("parsing ID from CBOR: %w", err)
+			}
+		case "status":
+			if tmp.Status, err = cr.status(); err != nil {
+				return fmt.Errorf("parsing Status from CBOR: %w", err)
+			}
+		case "since":
+			if tmp.Since, err = cr.time(); err != nil {
+				return fmt.Errorf("parsing Since from CBOR: %w", err)
+			}
+		case "labels":
+			if tmp.Labels, err = cr.labels(); err != nil {
+				return fmt.Errorf("parsing Labels from CBOR: %w", err)
+			}
+		case "message":
+			if tmp.Message, err = cr.text(); err != nil {
+				return fmt.Errorf("parsing Message from CBOR: %w", err)
+			}
+			if err := checkMessage(tmp.Message); err != nil {
+				return fmt.Errorf("parsing Message from CBOR: %w", err)
+			}
+		case "until":
+			if tmp.Until, err = cr.time(); err != nil {
+				return fmt.Errorf("parsing Until from CBOR: %w", err)
+			}
+		default:
+			if err := cr.skip(0); err != nil {
+				return fmt.Errorf("skipping CBOR value for %q: %w", key, err)
+			}
+		}
+	}
+	if len(cr.b) > 0 {
+		return fmt.Errorf("unexpected data after CBOR Resource")
+	}
+	if err := checkUntil(tmp.Since, tmp.Until); err != nil {
+		return fmt.Errorf("parsing Until from CBOR: %w", err)
+	}
+
+	*r = tmp
+	return nil
+}
+
+func appendCBORHead(b []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(b, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(b, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, major|27), n)
+	}
+}
+
+func appendCBORInt(b []byte, n int64) []byte {
+	if n < 0 {
+		return appendCBORHead(b, cborNegInt, uint64(-1-n))
+	}
+	return appendCBORHead(b, cborUint, uint64(n))
+}
+
+func appendCBORText(b []byte, s string) []byte {
+	return append(appendCBORHead(b, cborText, uint64(len(s))), s...)
+}
+
+// maxCBORDepth bounds how deeply nested an unknown value may be.
+const maxCBORDepth = 16
+
+// cborReader reads the definite-length subset of CBOR needed for the
+// faststatus types.
+type cborReader struct {
+	b []byte
+}
+
+// head reads the initial byte and argument of the next item. For floats
+// and simple values the argument is the raw value that follows.
+func (r *cborReader) head() (major byte, n uint64, err error) {
+	if len(r.b) == 0 {
+		return 0, 0, fmt.Errorf("unexpected end of CBOR data")
+	}
+	major, info := r.b[0]&0xe0, r.b[0]&0x1f
+	r.b = r.b[1:]
+	var size int
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info <= 27:
+		size = 1 << (info - 24)
+	default:
+		return 0, 0, fmt.Errorf("unsupported CBOR additional information %d", info)
+	}
+	if len(r.b) < size {
+		return 0, 0, fmt.Errorf("unexpected end of CBOR data")
+	}
+	for _, c := range r.b[:size] {
+		n = n<<8 | uint64(c)
+	}
+	r.b = r.b[size:]
+	return major, n, nil
+}
+
+// bytes returns the next n bytes of content.
+func (r *cborReader) bytes(n uint64) ([]byte, error) {
+	if uint64(len(r.b)) < n {
+		return nil, fmt.Errorf("unexpected end of CBOR data")
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b, nil
+}
+
+func (r *cborReader) text() (string, error) {
+	major, n, err := r.head()
+	if err != nil {
+		return "", err
+	}
+	if major != cborText {
+		return "", fmt.Errorf("expected CBOR text string")
+	}
+	b, err := r.bytes(n)
+	return string(b), err
+}
+
+func (r *cborReader) id() (ID, error) {
+	major, n, err := r.head()
+	if err != nil {
+		return ID{}, err
+	}
+	if major == cborTag {
+		if n != cborTagUUID {
+			return ID{}, fmt.Errorf("unexpected CBOR tag %d for ID", n)
+		}
+		if major, n, err = r.head(); err != nil {
+			return ID{}, err
+		}
+	}
+	if major != cborBytes || n != 16 {
+		return ID{}, fmt.Errorf("ID must be a CBOR byte string of 16 bytes")
+	}
+	b, err := r.bytes(n)
+	if err != nil {
+		return ID{}, err
+	}
+	var id ID
+	copy(id[:], b)
+	return id, nil
+}
+
+func (r *cborReader) status() (Status, error) {
+	major, n, err := r.head()
+	if err != nil {
+		return 0, err
+	}
+	if major != cborUint {
+		return 0, fmt.Errorf("Status must be a CBOR unsigned integer")
+	}
+	if n > uint64(Unknown) {
+		return 0, errOutOfRange
+	}
+	return Status(n), nil
+}
+
+func (r *cborReader) time() (time.Time, error) {
+	if len(r.b) > 0 && r.b[0] == cborNull {
+		r.b = r.b[1:]
+		return time.Time{}, nil
+	}
+	major, n, err := r.head()
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch {
+	case major == cborTag && n == cborTagExtended:
+		return r.extendedTime()
+	case major != cborTag || n != cborTagEpoch:
+		return time.Time{}, fmt.Errorf("time must have CBOR tag 1 or 1001")
+	}
+	initial := r.b
+	major, n, err = r.head()
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch {
+	case major == cborUint && n <= math.MaxInt64:
+		return time.Unix(int64(n), 0), nil
+	case major == cborNegInt && n < math.MaxInt64:
+		return time.Unix(-1-int64(n), 0), nil
+	case major != cborSimple:
+	case initial[0] == cborFloat64:
+		return floatTime(math.Float64frombits(n))
+	case initial[0] == cborFloat32:
+		return floatTime(float64(math.Float32frombits(uint32(n))))
+	default:
+	}
+	return time.Time{}, fmt.Errorf("epoch time must be a CBOR integer or float")
+}
+
+// extendedTime reads the map of a tag 1001 extended time with integer
+// seconds and at most one of milliseconds, microseconds or nanoseconds.
+// Other elective (positive) keys are skipped, but other critical
+// (negative) keys cannot be ignored, so are an error.
+func (r *cborReader) extendedTime() (time.Time, error) {
+	major, n, err := r.head()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if major != cborMap {
+		return time.Time{}, fmt.Errorf("extended time must be a CBOR map")
+	}
+	var (
+		sec, nsec    int64
+		hasSec, frac bool
+	)
+	for i := uint64(0); i < n; i++ {
+		key, err := r.int()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("parsing extended time key: %w", err)
+		}
+		var scale int64
+		switch key {
+		case cborTimeSeconds:
+			if sec, err = r.int(); err != nil {
+				return time.Time{}, fmt.Errorf("parsing extended time seconds: %w", err)
+			}
+			hasSec = true
+			continue
+		case cborTimeMillis:
+			scale = 1e3
+		case cborTimeMicros:
+			scale = 1e6
+		case cborTimeNanos:
+			scale = 1e9
+		default:
+			if key < 0 {
+				return time.Time{}, fmt.Errorf("unsupported critical key %d in extended time", key)
+			}
+			if err := r.skip(1); err != nil {
+				return time.Time{}, err
+			}
+			continue
+		}
+		v, err := r.int()
+		switch {
+		case err != nil:
+			return time.Time{}, fmt.Errorf("parsing extended time fraction: %w", err)
+		case frac || v < 0 || v >= scale:
+			return time.Time{}, fmt.Errorf("extended time fraction out of range")
+		}
+		nsec, frac = v*(1e9/scale), true
+	}
+	if !hasSec {
+		return time.Time{}, fmt.Errorf("extended time must have integer seconds")
+	}
+	return time.Unix(sec, nsec), nil
+}
+
+// int reads an integer that fits in an int64.
+func (r *cborReader) int() (int64, error) {
+	major, n, err := r.head()
+	switch {
+	case err != nil:
+		return 0, err
+	case major == cborUint && n <= math.MaxInt64:
+		return int64(n), nil
+	case major == cborNegInt && n <= math.MaxInt64:
+		return -1 - int64(n), nil
+	default:
+		return 0, fmt.Errorf("expected a CBOR integer that fits in 64 bits")
+	}
+}
+
+// labels reads a map of text to text, which is nil when empty.
+func (r *cborReader) labels() (map[string]string, error) {
+	major, n, err := r.head()
+	if err != nil {
+		return nil, err
+	}
+	if major != cborMap {
+		return nil, fmt.Errorf("labels must be a CBOR map")
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string)
+	for i := uint64(0); i < n; i++ {
+		k, err := r.text()
+		if err != nil {
+			return nil, err
+		}
+		if labels[k], err = r.text(); err != nil {
+			return nil, err
+		}
+	}
+	return labels, nil
+}
+
+// floatTime converts seconds since the Unix epoch, as other encoders may
+// write them, to a time, to the nearest microsecond, which is all a float64
+// can hold for current times.
+func floatTime(sec float64) (time.Time, error) {
+	if math.IsNaN(sec) || math.IsInf(sec, 0) || math.Abs(sec) > 1<<53 {
+		return time.Time{}, fmt.Errorf("epoch time out of range")
+	}
+	whole, frac := math.Modf(sec)
+	usec := math.Round(frac * 1e6)
+	return time.Unix(int64(whole), int64(usec)*int64(time.Microsecond)), nil
+}
+
+// skip reads past the next item, however it is nested.
+func (r *cborReader) skip(depth int) error {
+	if depth > maxCBORDepth {
+		return fmt.Errorf("CBOR data nested too deeply")
+	}
+	major, n, err := r.head()
+	if err != nil {
+		return err
+	}
+	switch major {
+	case cborBytes, cborText:
+		_, err = r.bytes(n)
+		return err
+	case cborArray, cborMap:
+		if major == cborMap {
+			n *= 2
+		}
+		for i := uint64(0); i < n; i++ {
+			if err := r.skip(depth + 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case cborTag:
+		return r.skip(depth + 1)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus_test
+
+import (
+	"bytes"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+var cborTestID = faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+
+// cborTestResource is the head of a CBOR Resource up to the Since value.
+var cborTestResource = append(append([]byte{
+	0xa3,
+	0x62, 'i', 'd', 0xd8, 0x25, 0x50},
+	cborTestID[:]...),
+	0x66, 's', 't', 'a', 't', 'u', 's', 0x01,
+	0x65, 's', 'i', 'n', 'c', 'e',
+)
+
+func cborResource(tail ...byte) []byte {
+	return append(append([]byte{}, cborTestResource...), tail...)
+}
+
+func TestIDMarshalCBOR(t *testing.T) {
+	got, err := cborTestID.MarshalCBOR()
+	if err != nil {
+		t.Fatalf("ID.MarshalCBOR() = %+v, expected no error", err)
+	}
+	want := append([]byte{0xd8, 0x25, 0x50}, cborTestID[:]...)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("ID.MarshalCBOR() = %x, expected %x", got, want)
+	}
+}
+
+func TestIDUnmarshalCBOR(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []byte
+		want      faststatus.ID
+		wantError bool
+	}{
+		{"tagged", append([]byte{0xd8, 0x25, 0x50}, cborTestID[:]...), cborTestID, false},
+		{"untagged", append([]byte{0x50}, cborTestID[:]...), cborTestID, false},
+		{"wrong tag", append([]byte{0xd8, 0x26, 0x50}, cborTestID[:]...), faststatus.ID{}, true},
+		{"too short", append([]byte{0x4f}, cborTestID[:15]...), faststatus.ID{}, true},
+		{"truncated", append([]byte{0x50}, cborTestID[:15]...), faststatus.ID{}, true},
+		{"text", append([]byte{0x70}, cborTestID[:]...), faststatus.ID{}, true},
+		{"trailing data", append(append([]byte{0x50}, cborTestID[:]...), 0x00), faststatus.ID{}, true},
+		{"empty", []byte{}, faststatus.ID{}, true},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got faststatus.ID
+			err := (&got).UnmarshalCBOR(tc.input)
+			if (err != nil) != tc.wantError {
+				t.Fatalf("ID.UnmarshalCBOR(%x) = %+v, expected error: %t", tc.input, err, tc.wantError)
+			}
+			if got != tc.want {
+				t.Fatalf("ID.UnmarshalCBOR(%x) => %x, expected %x", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestStatusMarshalUnmarshalCBOR(t *testing.T) {
+	f := func(s faststatus.Status) bool {
+		b, err := s.MarshalCBOR()
+		if err != nil || len(b) != 1 {
+			t.Logf("Status(%d).MarshalCBOR() = %x, %+v", s, b, err)
+			return false
+		}
+		var got faststatus.Status
+		if err := (&got).UnmarshalCBOR(b); err != nil {
+			t.Logf("Status.UnmarshalCBOR(%x) = %+v", b, err)
+			return false
+		}
+		return got == s
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStatusCBOROutOfRange(t *testing.T) {
+	if _, err := faststatus.Status(4).MarshalCBOR(); !faststatus.IsOutOfRange(err) {
+		t.Fatalf("Status(4).MarshalCBOR() = %+v, expected out of range error", err)
+	}
+	var s faststatus.Status
+	if err := (&s).UnmarshalCBOR([]byte{0x04}); !faststatus.IsOutOfRange(err) {
+		t.Fatalf("Status.UnmarshalCBOR(04) = %+v, expected out of range error", err)
+	}
+}
+
+func TestResourceMarshalCBOR(t *testing.T) {
+	tests := []struct {
+		name  string
+		since time.Time
+		want  []byte
+	}{
+		{"zero time", time.Time{}, cborResource(0xf6)},
+		{"whole seconds", time.Unix(1363896240, 0), cborResource(0xc1, 0x1a, 0x51, 0x4b, 0x67, 0xb0)},
+		{"fractional seconds", time.Unix(1363896240, 5e8), cborResource(0xd9, 0x03, 0xe9, 0xa2, 0x01, 0x1a, 0x51, 0x4b, 0x67, 0xb0, 0x28, 0x1a, 0x1d, 0xcd, 0x65, 0x00)},
+		{"before epoch", time.Unix(-1, 0), cborResource(0xc1, 0x20)},
+		{"fractional before epoch", time.Unix(-1, 1), cborResource(0xd9, 0x03, 0xe9, 0xa2, 0x01, 0x20, 0x28, 0x01)},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			r := faststatus.Resource{ID: cborTestID, Status: faststatus.Busy, Since: tc.since}
+			got, err := r.MarshalCBOR()
+			if err != nil {
+				t.Fatalf("%+v.MarshalCBOR() = %+v, expected no error", r, err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("%+v.MarshalCBOR() = %x, expected %x", r, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResourceUnmarshalCBOR(t *testing.T) {
+	busy := func(since time.Time) faststatus.Resource {
+		return faststatus.Resource{ID: cborTestID, Status: faststatus.Busy, Since: since}
+	}
+	tests := []struct {
+		name      string
+		input     []byte
+		want      faststatus.Resource
+		wantError bool
+	}{
+		{"zero time", cborResource(0xf6), busy(time.Time{}), false},
+		{"whole seconds", cborResource(0xc1, 0x1a, 0x51, 0x4b, 0x67, 0xb0), busy(time.Unix(1363896240, 0)), false},
+		{"float64 seconds", cborResource(0xc1, 0xfb, 0x41, 0xd4, 0x52, 0xd9, 0xec, 0x20, 0x00, 0x00), busy(time.Unix(1363896240, 5e8)), false},
+		{"float32 seconds", cborResource(0xc1, 0xfa, 0x3f, 0xc0, 0x00, 0x00), busy(time.Unix(1, 5e8)), false},
+		{"negative seconds", cborResource(0xc1, 0x20), busy(time.Unix(-1, 0)), false},
+		{"extended nanoseconds", cborResource(0xd9, 0x03, 0xe9, 0xa2, 0x01, 0x1a, 0x51, 0x4b, 0x67, 0xb0, 0x28, 0x1a, 0x1d, 0xcd, 0x65, 0x01), busy(time.Unix(1363896240, 500000001)), false},
+		{"extended milliseconds", cborResource(0xd9, 0x03, 0xe9, 0xa2, 0x22, 0x19, 0x01, 0xf4, 0x01, 0x01), busy(time.Unix(1, 5e8)), false},
+		{"extended elective key", cborResource(0xd9, 0x03, 0xe9, 0xa2, 0x01, 0x01, 0x0a, 0x61, 'x'), busy(time.Unix(1, 0)), false},
+		{"extended critical key", cborResource(0xd9, 0x03, 0xe9, 0xa2, 0x01, 0x01, 0x2a, 0x01), faststatus.Resource{}, true},
+		{"extended without seconds", cborResource(0xd9, 0x03, 0xe9, 0xa1, 0x28, 0x01), faststatus.Resource{}, true},
+		{"extended fraction too big", cborResource(0xd9, 0x03, 0xe9, 0xa2, 0x01, 0x01, 0x22, 0x19, 0x03, 0xe8), faststatus.Resource{}, true},
+		{"extended two fractions", cborResource(0xd9, 0x03, 0xe9, 0xa3, 0x01, 0x01, 0x22, 0x01, 0x28, 0x01), faststatus.Resource{}, true},
+		{"extended not a map", cborResource(0xd9, 0x03, 0xe9, 0x01), faststatus.Resource{}, true},
+		{"unknown keys",
+			append([]byte{0xa5,
+				0x64, 'n', 'o', 't', 'e', 0x82, 0x01, 0xa1, 0x61, 'x', 0x43, 1, 2, 3,
+				0x63, 'f', 'o', 'o', 0xc0, 0x61, 'z'},
+				cborResource(0xf6)[1:]...),
+			busy(time.Time{}),
+			false,
+		},
+		{"missing keys", []byte{0xa0}, faststatus.Resource{}, false},
+		{"not a map", []byte{0x80}, faststatus.Resource{}, true},
+		{"untagged time", cborResource(0x1a, 0x51, 0x4b, 0x67, 0xb0), faststatus.Resource{}, true},
+		{"string time", cborResource(0xc1, 0x61, '1'), faststatus.Resource{}, true},
+		{"infinite time", cborResource(0xc1, 0xfa, 0x7f, 0x80, 0x00, 0x00), faststatus.Resource{}, true},
+		{"truncated", cborResource(0xc1, 0x1a, 0x51), faststatus.Resource{}, true},
+		{"trailing data", cborResource(0xf6, 0x00), faststatus.Resource{}, true},
+		{"indefinite length", cborResource(0xc1, 0x5f), faststatus.Resource{}, true},
+		{"status out of range",
+			bytes.Replace(cborResource(0xf6), []byte{'s', 0x01}, []byte{'s', 0x04}, 1),
+			faststatus.Resource{},
+			true,
+		},
+		{"deeply nested unknown",
+			append([]byte{0xa1, 0x61, 'x'}, bytes.Repeat([]byte{0x81}, 64)...),
+			faststatus.Resource{},
+			true,
+		},
+		{"empty", []byte{}, faststatus.Resource{}, true},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got faststatus.Resource
+			err := (&got).UnmarshalCBOR(tc.input)
+			if (err != nil) != tc.wantError {
+				t.Fatalf("Resource.UnmarshalCBOR(%x) = %+v, expected error: %t", tc.input, err, tc.wantError)
+			}
+			if !got.Equal(tc.want) {
+				t.Fatalf("Resource.UnmarshalCBOR(%x) => %+v, expected %+v", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResourceMarshalUnmarshalCBORQuick(t *testing.T) {
+	f := func(r faststatus.Resource) bool {
+		b, err := r.MarshalCBOR()
+		if err != nil {
+			t.Logf("%+v.MarshalCBOR() = %+v", r, err)
+			return false
+		}
+		var got faststatus.Resource
+		if err := (&got).UnmarshalCBOR(b); err != nil {
+			t.Logf("Resource.UnmarshalCBOR(%x) = %+v", b, err)
+			return false
+		}
+		if !got.Equal(r) {
+			t.Logf("UnmarshalCBOR(MarshalCBOR(%+v)) = %+v", r, got)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestResourceCBORNanoseconds(t *testing.T) {
+	for _, since := range []time.Time{
+		time.Date(2017, 3, 4, 5, 6, 7, 123456789, time.UTC),
+		time.Date(2017, 3, 4, 5, 6, 7, 1, time.UTC),
+		time.Date(2262, 4, 11, 23, 47, 16, 854775807, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 59, 999999999, time.UTC),
+	} {
+		r := faststatus.Resource{ID: cborTestID, Since: since, Until: since.Add(time.Nanosecond)}
+		b, err := r.MarshalCBOR()
+		if err != nil {
+			t.Fatalf("%+v.MarshalCBOR() = %+v, expected no error", r, err)
+		}
+		var got faststatus.Resource
+		if err := (&got).UnmarshalCBOR(b); err != nil || !got.Equal(r) {
+			t.Fatalf("UnmarshalCBOR(%x) = %+v, %+v, expected %+v", b, got, err, r)
+		}
+	}
+}
@@ -3,10 +3,33 @@
 
 package faststatus
 
-import "github.com/pkg/errors"
+import "errors"
 
-// ConflictError checks to see if the error (or its Cause) is a result of conflict
-// data. An error value may be a conflict data error if it implements this interface:
+// Sentinel errors, for use with errors.Is. Errors from this package and
+// its subpackages match them through any number of wraps, as do the
+// predicates below.
+var (
+	// ErrConflict is matched by data that conflicts with what is already
+	// stored, like an older version of a Resource.
+	ErrConflict = errors.New("conflict")
+	// ErrCorrupt is matched by truncated or corrupted data.
+	ErrCorrupt = errors.New("corrupt data")
+	// ErrNotFound is matched when asking for something that does not
+	// exist, like a Resource that was never saved.
+	ErrNotFound = errors.New("not found")
+	// ErrOutOfRange is matched by a Status that is out of range.
+	ErrOutOfRange = errors.New("Status not in valid range")
+	// ErrSkew is matched by a time too far ahead of the clock of the
+	// receiver.
+	ErrSkew = errors.New("time too far ahead of the clock")
+	// ErrZeroValue is matched by zero-value data where non-zero data is
+	// required, like a Resource without an ID.
+	ErrZeroValue = errors.New("zero value")
+)
+
+// ConflictError checks to see if the error (or any error it wraps) is a
+// result of conflict data: either it matches ErrConflict, or it implements
+// this interface:
 //
 //    type conflicter interface {
 //      Conflict() bool
@@ -17,11 +40,58 @@ func ConflictError(e error) bool {
 	type conflicter interface {
 		Conflict() bool
 	}
-	if e, ok := e.(conflicter); ok {
-		return e.Conflict()
+	var c conflicter
+	return errors.Is(e, ErrConflict) || errors.As(e, &c) && c.Conflict()
+}
+
+// CorruptError checks to see if the error (or any error it wraps) is a
+// result of truncated or corrupted data: either it matches ErrCorrupt, or
+// it implements this interface:
+//
+//    type corrupter interface {
+//      Corrupt() bool
+//    }
+//
+// Otherwise it is not considered corrupt data.
+func CorruptError(e error) bool {
+	type corrupter interface {
+		Corrupt() bool
 	}
-	if e, ok := errors.Cause(e).(conflicter); ok {
-		return e.Conflict()
+	var c corrupter
+	return errors.Is(e, ErrCorrupt) || errors.As(e, &c) && c.Corrupt()
+}
+
+// SkewError checks to see if the error (or any error it wraps) is a result
+// of a time too far ahead of the clock of the receiver, like a Since in the
+// year 2099: either it matches ErrSkew, or it implements this interface:
+//
+//    type skewer interface {
+//      Skew() bool
+//    }
+//
+// Otherwise it is not considered a skew error.
+func SkewError(e error) bool {
+	type skewer interface {
+		Skew() bool
+	}
+	var s skewer
+	return errors.Is(e, ErrSkew) || errors.As(e, &s) && s.Skew()
+}
+
+// NotFoundError checks to see if the error (or any error it wraps) is a
+// result of asking for something that does not exist, like a Resource that
+// was never saved: either it matches ErrNotFound, or it implements this
+// interface:
+//
+//    type notFounder interface {
+//      NotFound() bool
+//    }
+//
+// Otherwise it is not considered a not-found error.
+func NotFoundError(e error) bool {
+	type notFounder interface {
+		NotFound() bool
 	}
-	return false
+	var n notFounder
+	return errors.Is(e, ErrNotFound) || errors.As(e, &n) && n.NotFound()
 }
@@ -4,8 +4,11 @@
 package faststatus_test
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/lazyengineering/faststatus"
 )
@@ -54,3 +57,210 @@ func (e conflictError) Error() string {
 func (e conflictError) Conflict() bool {
 	return bool(e)
 }
+
+func TestCorruptError(t *testing.T) {
+	testCases := []struct {
+		name        string
+		err         error
+		wantCorrupt bool
+	}{
+		{"nil",
+			nil,
+			false,
+		},
+		{"new string",
+			errors.New("an error"),
+			false,
+		},
+		{"false corrupt error",
+			corruptError(false),
+			false,
+		},
+		{"true corrupt error",
+			corruptError(true),
+			true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := faststatus.CorruptError(tc.err)
+			if got != tc.wantCorrupt {
+				t.Fatalf("CorruptError(%+v) = %v, expected %v", tc.err, got, tc.wantCorrupt)
+			}
+		})
+	}
+}
+
+type corruptError bool
+
+func (e corruptError) Error() string {
+	return "corrupt error"
+}
+
+func (e corruptError) Corrupt() bool {
+	return bool(e)
+}
+
+func TestSkewError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		wantSkew bool
+	}{
+		{"nil",
+			nil,
+			false,
+		},
+		{"new string",
+			errors.New("an error"),
+			false,
+		},
+		{"false skew error",
+			skewError(false),
+			false,
+		},
+		{"true skew error",
+			skewError(true),
+			true,
+		},
+		{"conflict error",
+			conflictError(true),
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := faststatus.SkewError(tc.err)
+			if got != tc.wantSkew {
+				t.Fatalf("SkewError(%+v) = %v, expected %v", tc.err, got, tc.wantSkew)
+			}
+		})
+	}
+}
+
+type skewError bool
+
+func (e skewError) Error() string {
+	return "skew error"
+}
+
+func (e skewError) Skew() bool {
+	return bool(e)
+}
+
+func TestNotFoundError(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		wantNotFound bool
+	}{
+		{"nil",
+			nil,
+			false,
+		},
+		{"new string",
+			errors.New("an error"),
+			false,
+		},
+		{"false not-found error",
+			notFoundError(false),
+			false,
+		},
+		{"true not-found error",
+			notFoundError(true),
+			true,
+		},
+		{"conflict error",
+			conflictError(true),
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := faststatus.NotFoundError(tc.err)
+			if got != tc.wantNotFound {
+				t.Fatalf("NotFoundError(%+v) = %v, expected %v", tc.err, got, tc.wantNotFound)
+			}
+		})
+	}
+}
+
+type notFoundError bool
+
+func (e notFoundError) Error() string {
+	return "not-found error"
+}
+
+func (e notFoundError) NotFound() bool {
+	return bool(e)
+}
+
+func TestPredicatesThroughWraps(t *testing.T) {
+	wrap := func(err error) error {
+		return fmt.Errorf("outer: %w", fmt.Errorf("inner: %w", err))
+	}
+	testCases := []struct {
+		name      string
+		err       error
+		predicate func(error) bool
+		sentinel  error
+	}{
+		{"conflict", conflictError(true), faststatus.ConflictError, faststatus.ErrConflict},
+		{"corrupt", corruptError(true), faststatus.CorruptError, faststatus.ErrCorrupt},
+		{"skew", skewError(true), faststatus.SkewError, faststatus.ErrSkew},
+		{"not found", notFoundError(true), faststatus.NotFoundError, faststatus.ErrNotFound},
+		{"conflict sentinel", faststatus.ErrConflict, faststatus.ConflictError, faststatus.ErrConflict},
+		{"corrupt sentinel", faststatus.ErrCorrupt, faststatus.CorruptError, faststatus.ErrCorrupt},
+		{"skew sentinel", faststatus.ErrSkew, faststatus.SkewError, faststatus.ErrSkew},
+		{"not found sentinel", faststatus.ErrNotFound, faststatus.NotFoundError, faststatus.ErrNotFound},
+		{"out of range sentinel", faststatus.ErrOutOfRange, faststatus.IsOutOfRange, faststatus.ErrOutOfRange},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := wrap(tc.err)
+			if !tc.predicate(err) {
+				t.Fatalf("predicate(%+v) = false, expected true", err)
+			}
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("errors.Is(%+v, %+v) = false, expected true", err, tc.err)
+			}
+			if tc.err == tc.sentinel {
+				return
+			}
+			if errors.Is(err, tc.sentinel) {
+				t.Fatalf("errors.Is(%+v, %+v) = true for a type without an Is method, expected false", err, tc.sentinel)
+			}
+		})
+	}
+}
+
+func TestErrorsMatchSentinels(t *testing.T) {
+	_, outOfRange := (faststatus.Unknown + 1).MarshalText()
+	_, skew := faststatus.SkewPolicy{Max: time.Minute}.Check(faststatus.Resource{Since: time.Now().Add(time.Hour)}, time.Now())
+	corrupt := faststatus.NewBinaryDecoder(bytes.NewReader([]byte{0x01})).Decode(new(faststatus.Resource))
+	testCases := []struct {
+		name     string
+		err      error
+		sentinel error
+	}{
+		{"out of range", outOfRange, faststatus.ErrOutOfRange},
+		{"skew", skew, faststatus.ErrSkew},
+		{"corrupt", corrupt, faststatus.ErrCorrupt},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", tc.err)
+			if !errors.Is(err, tc.sentinel) {
+				t.Fatalf("errors.Is(%+v, %+v) = false, expected true", err, tc.sentinel)
+			}
+		})
+	}
+}
@@ -53,9 +53,34 @@ func (r Resource) Generate(rgen *rand.Rand, size int) reflect.Value {
 	return reflect.ValueOf(rr)
 }
 
+// Generate is used in testing to generate random valid Group values
+func (g Group) Generate(rgen *rand.Rand, size int) reflect.Value {
+	gg := Group{}
+
+	gg.ID, _ = NewID()
+	name := make([]byte, rgen.Intn(size+1))
+	for i := range name {
+		name[i] = groupNameChars[rgen.Intn(len(groupNameChars))]
+	}
+	gg.Name = string(name)
+	for i := rgen.Intn(size + 1); i > 0; i-- {
+		member, _ := NewID()
+		gg.Members = append(gg.Members, member)
+	}
+
+	return reflect.ValueOf(gg)
+}
+
+const groupNameChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 -_"
+
 // Generate is used in testing to generate only valid Status values
 func (s Status) Generate(rand *rand.Rand, size int) reflect.Value {
 	return reflect.ValueOf(Status(rand.Int() % int(Occupied)))
 }
 
 const BinaryVersion = binaryVersion
+
+// SetNow replaces the source of physical time of a Clock, for testing.
+func (c *Clock) SetNow(now func() time.Time) {
+	c.now = now
+}
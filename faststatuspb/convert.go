@@ -0,0 +1,122 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+// Package faststatuspb holds the Protocol Buffers definition of the
+// faststatus model, and conversions to and from the faststatus types.
+package faststatuspb
+
+//go:generate protoc -I.. --go_out=.. --go_opt=paths=source_relative --go-grpc_out=.. --go-grpc_opt=paths=source_relative ../faststatuspb/faststatus.proto ../faststatuspb/service.proto
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+// ToProto converts a Resource to its Protocol Buffers message. A zero-value
+// Since or Until is left unset.
+func ToProto(r faststatus.Resource) *Resource {
+	p := &Resource{
+		Id:      IDToProto(r.ID),
+		Status:  Status(r.Status),
+		Labels:  copyLabels(r.Labels),
+		Message: r.Message,
+	}
+	if !r.Since.IsZero() {
+		p.Since = timestamppb.New(r.Since)
+	}
+	if !r.Until.IsZero() {
+		p.Until = timestamppb.New(r.Until)
+	}
+	return p
+}
+
+// FromProto converts a Protocol Buffers message to a Resource. An unset
+// Since or Until, like the zero time, is the zero value, just as with
+// `UnmarshalJSON`, and otherwise is in UTC. The Resource is not validated;
+// an invalid Message or Until is reported by its Validate method, along
+// with every other invalid field.
+func FromProto(p *Resource) (faststatus.Resource, error) {
+	id, err := IDFromProto(p.GetId())
+	if err != nil {
+		return faststatus.Resource{}, fmt.Errorf("converting ID from proto: %w", err)
+	}
+
+	status := p.GetStatus()
+	if status < Status_STATUS_FREE || status > Status_STATUS_UNKNOWN {
+		return faststatus.Resource{}, fmt.Errorf("converting Status from proto: status %d not in valid range", status)
+	}
+
+	since, err := timeFromProto(p.GetSince())
+	if err != nil {
+		return faststatus.Resource{}, fmt.Errorf("converting Since from proto: %w", err)
+	}
+
+	until, err := timeFromProto(p.GetUntil())
+	if err != nil {
+		return faststatus.Resource{}, fmt.Errorf("converting Until from proto: %w", err)
+	}
+
+	return faststatus.Resource{
+		ID:      id,
+		Status:  faststatus.Status(status),
+		Since:   since,
+		Labels:  copyLabels(p.GetLabels()),
+		Message: p.GetMessage(),
+		Until:   until,
+	}, nil
+}
+
+// timeFromProto converts a timestamp, with unset and the zero time both
+// the zero-value time.
+func timeFromProto(ts *timestamppb.Timestamp) (time.Time, error) {
+	if ts == nil {
+		return time.Time{}, nil
+	}
+	if err := ts.CheckValid(); err != nil {
+		return time.Time{}, err
+	}
+	t := ts.AsTime()
+	if t.IsZero() {
+		return time.Time{}, nil
+	}
+	return t, nil
+}
+
+// copyLabels copies labels so that neither side aliases the other. Empty
+// labels are nil.
+func copyLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	c := make(map[string]string, len(labels))
+	for k, v := range labels {
+		c[k] = v
+	}
+	return c
+}
+
+// IDToProto converts an ID to its Protocol Buffers message. The zero-value
+// ID has empty bytes.
+func IDToProto(id faststatus.ID) *ID {
+	if id == (faststatus.ID{}) {
+		return &ID{}
+	}
+	return &ID{Uuid: append([]byte{}, id[:]...)}
+}
+
+// IDFromProto converts a Protocol Buffers message to an ID. A nil message
+// or empty bytes is the zero-value ID.
+func IDFromProto(p *ID) (faststatus.ID, error) {
+	var id faststatus.ID
+	if len(p.GetUuid()) == 0 {
+		return id, nil
+	}
+	if err := (&id).UnmarshalBinary(p.GetUuid()); err != nil {
+		return faststatus.ID{}, err
+	}
+	return id, nil
+}
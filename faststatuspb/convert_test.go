@@ -0,0 +1,181 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatuspb_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/faststatuspb"
+)
+
+func TestToFromProto(t *testing.T) {
+	locations := []*time.Location{time.UTC, time.FixedZone("PDT", -7*60*60), time.FixedZone("IST", 11*30*60)}
+	gen := func(args []reflect.Value, rgen *rand.Rand) {
+		r := faststatus.Resource{Status: faststatus.Status(rgen.Intn(int(faststatus.Unknown) + 1))}
+		rgen.Read(r.ID[:])
+		if rgen.Intn(4) > 0 {
+			r.Since = time.Unix(rgen.Int63n(1<<33), rgen.Int63n(int64(time.Second))).In(locations[rgen.Intn(len(locations))])
+		}
+		if rgen.Intn(2) > 0 {
+			r.Message = fmt.Sprintf("back in %d minutes", rgen.Intn(60))
+			r.Until = r.Since.Add(time.Duration(rgen.Int63n(int64(24*time.Hour))) + 1)
+		}
+		for i := rgen.Intn(4); i > 0; i-- {
+			if r.Labels == nil {
+				r.Labels = make(map[string]string)
+			}
+			r.Labels[fmt.Sprintf("key%d", rgen.Intn(10))] = fmt.Sprintf("value%d", rgen.Intn(10))
+		}
+		args[0] = reflect.ValueOf(r)
+	}
+	f := func(r faststatus.Resource) bool {
+		b, err := proto.Marshal(faststatuspb.ToProto(r))
+		if err != nil {
+			return false
+		}
+		p := new(faststatuspb.Resource)
+		if err := proto.Unmarshal(b, p); err != nil {
+			return false
+		}
+		got, err := faststatuspb.FromProto(p)
+		return err == nil && got.Equal(r)
+	}
+	if err := quick.Check(f, &quick.Config{Values: gen}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProtoLabelsAreCopied(t *testing.T) {
+	r := faststatus.Resource{Labels: map[string]string{"floor": "3"}}
+	p := faststatuspb.ToProto(r)
+	p.Labels["floor"] = "4"
+	if r.Labels["floor"] != "3" {
+		t.Fatalf("changing ToProto() Labels changed the Resource Labels to %v", r.Labels)
+	}
+	got, _ := faststatuspb.FromProto(p)
+	p.Labels["floor"] = "5"
+	if got.Labels["floor"] != "4" {
+		t.Fatalf("changing proto Labels changed the FromProto() Labels to %v", got.Labels)
+	}
+}
+
+func TestToProtoZeroValues(t *testing.T) {
+	p := faststatuspb.ToProto(faststatus.Resource{})
+	if p.GetSince() != nil {
+		t.Fatalf("ToProto() Since = %v, expected unset", p.GetSince())
+	}
+	if len(p.GetId().GetUuid()) != 0 {
+		t.Fatalf("ToProto() ID = %x, expected empty", p.GetId().GetUuid())
+	}
+	got, err := faststatuspb.FromProto(p)
+	if err != nil {
+		t.Fatalf("FromProto() = %+v, expected no error", err)
+	}
+	if !reflect.DeepEqual(got, faststatus.Resource{}) {
+		t.Fatalf("FromProto() = %+v, expected the zero value", got)
+	}
+}
+
+func TestFromProto(t *testing.T) {
+	id := faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	since := time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+	testCases := []struct {
+		name         string
+		input        *faststatuspb.Resource
+		wantError    bool
+		wantResource faststatus.Resource
+	}{
+		{"nil", nil, false, faststatus.Resource{}},
+		{"happy path",
+			&faststatuspb.Resource{
+				Id:     &faststatuspb.ID{Uuid: id[:]},
+				Status: faststatuspb.Status_STATUS_UNKNOWN,
+				Since:  timestamppb.New(since),
+			},
+			false,
+			faststatus.Resource{ID: id, Status: faststatus.Unknown, Since: since},
+		},
+		{"zero time",
+			&faststatuspb.Resource{
+				Id:    &faststatuspb.ID{Uuid: id[:]},
+				Since: timestamppb.New(time.Time{}),
+			},
+			false,
+			faststatus.Resource{ID: id},
+		},
+		{"short ID",
+			&faststatuspb.Resource{Id: &faststatuspb.ID{Uuid: id[:8]}},
+			true,
+			faststatus.Resource{},
+		},
+		{"status out of range",
+			&faststatuspb.Resource{Status: faststatuspb.Status_STATUS_UNKNOWN + 1},
+			true,
+			faststatus.Resource{},
+		},
+		{"negative status",
+			&faststatuspb.Resource{Status: -1},
+			true,
+			faststatus.Resource{},
+		},
+		{"message and until",
+			&faststatuspb.Resource{
+				Id:      &faststatuspb.ID{Uuid: id[:]},
+				Status:  faststatuspb.Status_STATUS_BUSY,
+				Since:   timestamppb.New(since),
+				Message: "in a 1:1",
+				Until:   timestamppb.New(since.Add(time.Hour)),
+			},
+			false,
+			faststatus.Resource{ID: id, Status: faststatus.Busy, Since: since, Message: "in a 1:1", Until: since.Add(time.Hour)},
+		},
+		{"until before since",
+			&faststatuspb.Resource{Since: timestamppb.New(since), Until: timestamppb.New(since.Add(-time.Hour))},
+			false,
+			faststatus.Resource{Since: since, Until: since.Add(-time.Hour)},
+		},
+		{"invalid until",
+			&faststatuspb.Resource{Until: &timestamppb.Timestamp{Nanos: -1}},
+			true,
+			faststatus.Resource{},
+		},
+		{"control character in message",
+			&faststatuspb.Resource{Message: "back\nsoon"},
+			false,
+			faststatus.Resource{Message: "back\nsoon"},
+		},
+		{"long message",
+			&faststatuspb.Resource{Message: strings.Repeat("x", faststatus.MaxMessageLen+1)},
+			false,
+			faststatus.Resource{Message: strings.Repeat("x", faststatus.MaxMessageLen+1)},
+		},
+		{"invalid timestamp",
+			&faststatuspb.Resource{Since: &timestamppb.Timestamp{Nanos: -1}},
+			true,
+			faststatus.Resource{},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := faststatuspb.FromProto(tc.input)
+			if (err != nil) != tc.wantError {
+				t.Fatalf("FromProto(%v) = %+v, expected error? %t", tc.input, err, tc.wantError)
+			}
+			if !reflect.DeepEqual(got, tc.wantResource) {
+				t.Fatalf("FromProto(%v) = %+v, expected %+v", tc.input, got, tc.wantResource)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: faststatuspb/faststatus.proto
+
+package faststatuspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Status represents how busy a given resource is.
+type Status int32
+
+const (
+	Status_STATUS_FREE     Status = 0
+	Status_STATUS_BUSY     Status = 1
+	Status_STATUS_OCCUPIED Status = 2
+	Status_STATUS_UNKNOWN  Status = 3
+)
+
+// Enum value maps for Status.
+var (
+	Status_name = map[int32]string{
+		0: "STATUS_FREE",
+		1: "STATUS_BUSY",
+		2: "STATUS_OCCUPIED",
+		3: "STATUS_UNKNOWN",
+	}
+	Status_value = map[string]int32{
+		"STATUS_FREE":     0,
+		"STATUS_BUSY":     1,
+		"STATUS_OCCUPIED": 2,
+		"STATUS_UNKNOWN":  3,
+	}
+)
+
+func (x Status) Enum() *Status {
+	p := new(Status)
+	*p = x
+	return p
+}
+
+func (x Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_faststatuspb_faststatus_proto_enumTypes[0].Descriptor()
+}
+
+func (Status) Type() protoreflect.EnumType {
+	return &file_faststatuspb_faststatus_proto_enumTypes[0]
+}
+
+func (x Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Status.Descriptor instead.
+func (Status) EnumDescriptor() ([]byte, []int) {
+	return file_faststatuspb_faststatus_proto_rawDescGZIP(), []int{0}
+}
+
+// ID is a UUID identifying a Resource.
+type ID struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The 16 bytes of the UUID. Empty is the zero-value ID.
+	Uuid          []byte `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ID) Reset() {
+	*x = ID{}
+	mi := &file_faststatuspb_faststatus_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ID) ProtoMessage() {}
+
+func (x *ID) ProtoReflect() protoreflect.Message {
+	mi := &file_faststatuspb_faststatus_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ID.ProtoReflect.Descriptor instead.
+func (*ID) Descriptor() ([]byte, []int) {
+	return file_faststatuspb_faststatus_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ID) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+// Resource represents any resource (a person, a bathroom, a server, etc.)
+// that needs to communicate how busy it is.
+type Resource struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     *ID                    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status Status                 `protobuf:"varint,2,opt,name=status,proto3,enum=faststatus.Status" json:"status,omitempty"`
+	// Unset for a Resource that has never been updated.
+	Since *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	// Context about the resource, for selecting resources by label.
+	Labels map[string]string `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// An optional note about the status, like "in a 1:1".
+	Message string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	// When the status is expected to change. Unset if not known.
+	Until         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=until,proto3" json:"until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Resource) Reset() {
+	*x = Resource{}
+	mi := &file_faststatuspb_faststatus_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Resource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resource) ProtoMessage() {}
+
+func (x *Resource) ProtoReflect() protoreflect.Message {
+	mi := &file_faststatuspb_faststatus_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resource.ProtoReflect.Descriptor instead.
+func (*Resource) Descriptor() ([]byte, []int) {
+	return file_faststatuspb_faststatus_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Resource) GetId() *ID {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *Resource) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_FREE
+}
+
+func (x *Resource) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *Resource) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Resource) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Resource) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+var File_faststatuspb_faststatus_proto protoreflect.FileDescriptor
+
+const file_faststatuspb_faststatus_proto_rawDesc = "" +
+	"\n" +
+	"\x1dfaststatuspb/faststatus.proto\x12\n" +
+	"faststatus\x1a\x1fgoogle/protobuf/timestamp.proto\"\x18\n" +
+	"\x02ID\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\fR\x04uuid\"\xc9\x02\n" +
+	"\bResource\x12\x1e\n" +
+	"\x02id\x18\x01 \x01(\v2\x0e.faststatus.IDR\x02id\x12*\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x12.faststatus.StatusR\x06status\x120\n" +
+	"\x05since\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x128\n" +
+	"\x06labels\x18\x04 \x03(\v2 .faststatus.Resource.LabelsEntryR\x06labels\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x120\n" +
+	"\x05until\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*S\n" +
+	"\x06Status\x12\x0f\n" +
+	"\vSTATUS_FREE\x10\x00\x12\x0f\n" +
+	"\vSTATUS_BUSY\x10\x01\x12\x13\n" +
+	"\x0fSTATUS_OCCUPIED\x10\x02\x12\x12\n" +
+	"\x0eSTATUS_UNKNOWN\x10\x03B4Z2github.com/lazyengineering/faststatus/faststatuspbb\x06proto3"
+
+var (
+	file_faststatuspb_faststatus_proto_rawDescOnce sync.Once
+	file_faststatuspb_faststatus_proto_rawDescData []byte
+)
+
+func file_faststatuspb_faststatus_proto_rawDescGZIP() []byte {
+	file_faststatuspb_faststatus_proto_rawDescOnce.Do(func() {
+		file_faststatuspb_faststatus_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_faststatuspb_faststatus_proto_rawDesc), len(file_faststatuspb_faststatus_proto_rawDesc)))
+	})
+	return file_faststatuspb_faststatus_proto_rawDescData
+}
+
+var file_faststatuspb_faststatus_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_faststatuspb_faststatus_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_faststatuspb_faststatus_proto_goTypes = []any{
+	(Status)(0),                   // 0: faststatus.Status
+	(*ID)(nil),                    // 1: faststatus.ID
+	(*Resource)(nil),              // 2: faststatus.Resource
+	nil,                           // 3: faststatus.Resource.LabelsEntry
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+}
+var file_faststatuspb_faststatus_proto_depIdxs = []int32{
+	1, // 0: faststatus.Resource.id:type_name -> faststatus.ID
+	0, // 1: faststatus.Resource.status:type_name -> faststatus.Status
+	4, // 2: faststatus.Resource.since:type_name -> google.protobuf.Timestamp
+	3, // 3: faststatus.Resource.labels:type_name -> faststatus.Resource.LabelsEntry
+	4, // 4: faststatus.Resource.until:type_name -> google.protobuf.Timestamp
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_faststatuspb_faststatus_proto_init() }
+func file_faststatuspb_faststatus_proto_init() {
+	if File_faststatuspb_faststatus_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_faststatuspb_faststatus_proto_rawDesc), len(file_faststatuspb_faststatus_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_faststatuspb_faststatus_proto_goTypes,
+		DependencyIndexes: file_faststatuspb_faststatus_proto_depIdxs,
+		EnumInfos:         file_faststatuspb_faststatus_proto_enumTypes,
+		MessageInfos:      file_faststatuspb_faststatus_proto_msgTypes,
+	}.Build()
+	File_faststatuspb_faststatus_proto = out.File
+	file_faststatuspb_faststatus_proto_goTypes = nil
+	file_faststatuspb_faststatus_proto_depIdxs = nil
+}
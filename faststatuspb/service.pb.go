@@ -0,0 +1,449 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: faststatuspb/service.proto
+
+package faststatuspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *ID                    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	mi := &file_faststatuspb_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_faststatuspb_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_faststatuspb_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetRequest) GetId() *ID {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+type SaveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Resource      *Resource              `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveRequest) Reset() {
+	*x = SaveRequest{}
+	mi := &file_faststatuspb_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveRequest) ProtoMessage() {}
+
+func (x *SaveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_faststatuspb_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveRequest.ProtoReflect.Descriptor instead.
+func (*SaveRequest) Descriptor() ([]byte, []int) {
+	return file_faststatuspb_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SaveRequest) GetResource() *Resource {
+	if x != nil {
+		return x.Resource
+	}
+	return nil
+}
+
+type BatchGetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []*ID                  `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetRequest) Reset() {
+	*x = BatchGetRequest{}
+	mi := &file_faststatuspb_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetRequest) ProtoMessage() {}
+
+func (x *BatchGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_faststatuspb_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetRequest) Descriptor() ([]byte, []int) {
+	return file_faststatuspb_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BatchGetRequest) GetIds() []*ID {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type BatchGetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Resources     []*Resource            `protobuf:"bytes,1,rep,name=resources,proto3" json:"resources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetResponse) Reset() {
+	*x = BatchGetResponse{}
+	mi := &file_faststatuspb_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetResponse) ProtoMessage() {}
+
+func (x *BatchGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_faststatuspb_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetResponse) Descriptor() ([]byte, []int) {
+	return file_faststatuspb_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BatchGetResponse) GetResources() []*Resource {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+type ListRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// A label selector, like "floor=3,type!=printer". Empty lists every
+	// Resource.
+	Selector      string `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	mi := &file_faststatuspb_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_faststatuspb_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_faststatuspb_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListRequest) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+type ListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Resources     []*Resource            `protobuf:"bytes,1,rep,name=resources,proto3" json:"resources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListResponse) Reset() {
+	*x = ListResponse{}
+	mi := &file_faststatuspb_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponse) ProtoMessage() {}
+
+func (x *ListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_faststatuspb_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return file_faststatuspb_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListResponse) GetResources() []*Resource {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The Resources to watch. Empty watches every Resource.
+	Ids []*ID `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	// A label selector, like "floor=3,type!=printer", which the Resources
+	// must also match. Empty matches every Resource.
+	Selector      string `protobuf:"bytes,2,opt,name=selector,proto3" json:"selector,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_faststatuspb_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_faststatuspb_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_faststatuspb_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WatchRequest) GetIds() []*ID {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+var File_faststatuspb_service_proto protoreflect.FileDescriptor
+
+const file_faststatuspb_service_proto_rawDesc = "" +
+	"\n" +
+	"\x1afaststatuspb/service.proto\x12\n" +
+	"faststatus\x1a\x1dfaststatuspb/faststatus.proto\",\n" +
+	"\n" +
+	"GetRequest\x12\x1e\n" +
+	"\x02id\x18\x01 \x01(\v2\x0e.faststatus.IDR\x02id\"?\n" +
+	"\vSaveRequest\x120\n" +
+	"\bresource\x18\x01 \x01(\v2\x14.faststatus.ResourceR\bresource\"3\n" +
+	"\x0fBatchGetRequest\x12 \n" +
+	"\x03ids\x18\x01 \x03(\v2\x0e.faststatus.IDR\x03ids\"F\n" +
+	"\x10BatchGetResponse\x122\n" +
+	"\tresources\x18\x01 \x03(\v2\x14.faststatus.ResourceR\tresources\")\n" +
+	"\vListRequest\x12\x1a\n" +
+	"\bselector\x18\x01 \x01(\tR\bselector\"B\n" +
+	"\fListResponse\x122\n" +
+	"\tresources\x18\x01 \x03(\v2\x14.faststatus.ResourceR\tresources\"L\n" +
+	"\fWatchRequest\x12 \n" +
+	"\x03ids\x18\x01 \x03(\v2\x0e.faststatus.IDR\x03ids\x12\x1a\n" +
+	"\bselector\x18\x02 \x01(\tR\bselector2\xb5\x02\n" +
+	"\n" +
+	"FastStatus\x123\n" +
+	"\x03Get\x12\x16.faststatus.GetRequest\x1a\x14.faststatus.Resource\x125\n" +
+	"\x04Save\x12\x17.faststatus.SaveRequest\x1a\x14.faststatus.Resource\x12E\n" +
+	"\bBatchGet\x12\x1b.faststatus.BatchGetRequest\x1a\x1c.faststatus.BatchGetResponse\x129\n" +
+	"\x04List\x12\x17.faststatus.ListRequest\x1a\x18.faststatus.ListResponse\x129\n" +
+	"\x05Watch\x12\x18.faststatus.WatchRequest\x1a\x14.faststatus.Resource0\x01B4Z2github.com/lazyengineering/faststatus/faststatuspbb\x06proto3"
+
+var (
+	file_faststatuspb_service_proto_rawDescOnce sync.Once
+	file_faststatuspb_service_proto_rawDescData []byte
+)
+
+func file_faststatuspb_service_proto_rawDescGZIP() []byte {
+	file_faststatuspb_service_proto_rawDescOnce.Do(func() {
+		file_faststatuspb_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_faststatuspb_service_proto_rawDesc), len(file_faststatuspb_service_proto_rawDesc)))
+	})
+	return file_faststatuspb_service_proto_rawDescData
+}
+
+var file_faststatuspb_service_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_faststatuspb_service_proto_goTypes = []any{
+	(*GetRequest)(nil),       // 0: faststatus.GetRequest
+	(*SaveRequest)(nil),      // 1: faststatus.SaveRequest
+	(*BatchGetRequest)(nil),  // 2: faststatus.BatchGetRequest
+	(*BatchGetResponse)(nil), // 3: faststatus.BatchGetResponse
+	(*ListRequest)(nil),      // 4: faststatus.ListRequest
+	(*ListResponse)(nil),     // 5: faststatus.ListResponse
+	(*WatchRequest)(nil),     // 6: faststatus.WatchRequest
+	(*ID)(nil),               // 7: faststatus.ID
+	(*Resource)(nil),         // 8: faststatus.Resource
+}
+var file_faststatuspb_service_proto_depIdxs = []int32{
+	7,  // 0: faststatus.GetRequest.id:type_name -> faststatus.ID
+	8,  // 1: faststatus.SaveRequest.resource:type_name -> faststatus.Resource
+	7,  // 2: faststatus.BatchGetRequest.ids:type_name -> faststatus.ID
+	8,  // 3: faststatus.BatchGetResponse.resources:type_name -> faststatus.Resource
+	8,  // 4: faststatus.ListResponse.resources:type_name -> faststatus.Resource
+	7,  // 5: faststatus.WatchRequest.ids:type_name -> faststatus.ID
+	0,  // 6: faststatus.FastStatus.Get:input_type -> faststatus.GetRequest
+	1,  // 7: faststatus.FastStatus.Save:input_type -> faststatus.SaveRequest
+	2,  // 8: faststatus.FastStatus.BatchGet:input_type -> faststatus.BatchGetRequest
+	4,  // 9: faststatus.FastStatus.List:input_type -> faststatus.ListRequest
+	6,  // 10: faststatus.FastStatus.Watch:input_type -> faststatus.WatchRequest
+	8,  // 11: faststatus.FastStatus.Get:output_type -> faststatus.Resource
+	8,  // 12: faststatus.FastStatus.Save:output_type -> faststatus.Resource
+	3,  // 13: faststatus.FastStatus.BatchGet:output_type -> faststatus.BatchGetResponse
+	5,  // 14: faststatus.FastStatus.List:output_type -> faststatus.ListResponse
+	8,  // 15: faststatus.FastStatus.Watch:output_type -> faststatus.Resource
+	11, // [11:16] is the sub-list for method output_type
+	6,  // [6:11] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_faststatuspb_service_proto_init() }
+func file_faststatuspb_service_proto_init() {
+	if File_faststatuspb_service_proto != nil {
+		return
+	}
+	file_faststatuspb_faststatus_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_faststatuspb_service_proto_rawDesc), len(file_faststatuspb_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_faststatuspb_service_proto_goTypes,
+		DependencyIndexes: file_faststatuspb_service_proto_depIdxs,
+		MessageInfos:      file_faststatuspb_service_proto_msgTypes,
+	}.Build()
+	File_faststatuspb_service_proto = out.File
+	file_faststatuspb_service_proto_goTypes = nil
+	file_faststatuspb_service_proto_depIdxs = nil
+}
@@ -0,0 +1,300 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: faststatuspb/service.proto
+
+package faststatuspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FastStatus_Get_FullMethodName      = "/faststatus.FastStatus/Get"
+	FastStatus_Save_FullMethodName     = "/faststatus.FastStatus/Save"
+	FastStatus_BatchGet_FullMethodName = "/faststatus.FastStatus/BatchGet"
+	FastStatus_List_FullMethodName     = "/faststatus.FastStatus/List"
+	FastStatus_Watch_FullMethodName    = "/faststatus.FastStatus/Watch"
+)
+
+// FastStatusClient is the client API for FastStatus service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FastStatus reads, writes and watches Resources.
+type FastStatusClient interface {
+	// Get returns the most recent version of a Resource. A Resource that has
+	// never been saved is returned with the unknown Status.
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Resource, error)
+	// Save stores a Resource, unless a more recent version is already stored.
+	Save(ctx context.Context, in *SaveRequest, opts ...grpc.CallOption) (*Resource, error)
+	// BatchGet returns the most recent version of each of several Resources,
+	// in the order requested.
+	BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error)
+	// List returns the most recent version of every Resource matching a
+	// label selector.
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	// Watch streams each Resource as it becomes the most recent version.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Resource], error)
+}
+
+type fastStatusClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFastStatusClient(cc grpc.ClientConnInterface) FastStatusClient {
+	return &fastStatusClient{cc}
+}
+
+func (c *fastStatusClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Resource, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Resource)
+	err := c.cc.Invoke(ctx, FastStatus_Get_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fastStatusClient) Save(ctx context.Context, in *SaveRequest, opts ...grpc.CallOption) (*Resource, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Resource)
+	err := c.cc.Invoke(ctx, FastStatus_Save_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fastStatusClient) BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetResponse)
+	err := c.cc.Invoke(ctx, FastStatus_BatchGet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fastStatusClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, FastStatus_List_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fastStatusClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Resource], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FastStatus_ServiceDesc.Streams[0], FastStatus_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, Resource]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FastStatus_WatchClient = grpc.ServerStreamingClient[Resource]
+
+// FastStatusServer is the server API for FastStatus service.
+// All implementations must embed UnimplementedFastStatusServer
+// for forward compatibility.
+//
+// FastStatus reads, writes and watches Resources.
+type FastStatusServer interface {
+	// Get returns the most recent version of a Resource. A Resource that has
+	// never been saved is returned with the unknown Status.
+	Get(context.Context, *GetRequest) (*Resource, error)
+	// Save stores a Resource, unless a more recent version is already stored.
+	Save(context.Context, *SaveRequest) (*Resource, error)
+	// BatchGet returns the most recent version of each of several Resources,
+	// in the order requested.
+	BatchGet(context.Context, *BatchGetRequest) (*BatchGetResponse, error)
+	// List returns the most recent version of every Resource matching a
+	// label selector.
+	List(context.Context, *ListRequest) (*ListResponse, error)
+	// Watch streams each Resource as it becomes the most recent version.
+	Watch(*WatchRequest, grpc.ServerStreamingServer[Resource]) error
+	mustEmbedUnimplementedFastStatusServer()
+}
+
+// UnimplementedFastStatusServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFastStatusServer struct{}
+
+func (UnimplementedFastStatusServer) Get(context.Context, *GetRequest) (*Resource, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedFastStatusServer) Save(context.Context, *SaveRequest) (*Resource, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Save not implemented")
+}
+func (UnimplementedFastStatusServer) BatchGet(context.Context, *BatchGetRequest) (*BatchGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGet not implemented")
+}
+func (UnimplementedFastStatusServer) List(context.Context, *ListRequest) (*ListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedFastStatusServer) Watch(*WatchRequest, grpc.ServerStreamingServer[Resource]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedFastStatusServer) mustEmbedUnimplementedFastStatusServer() {}
+func (UnimplementedFastStatusServer) testEmbeddedByValue()                    {}
+
+// UnsafeFastStatusServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FastStatusServer will
+// result in compilation errors.
+type UnsafeFastStatusServer interface {
+	mustEmbedUnimplementedFastStatusServer()
+}
+
+func RegisterFastStatusServer(s grpc.ServiceRegistrar, srv FastStatusServer) {
+	// If the following call pancis, it indicates UnimplementedFastStatusServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FastStatus_ServiceDesc, srv)
+}
+
+func _FastStatus_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FastStatusServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FastStatus_Get_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FastStatusServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FastStatus_Save_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FastStatusServer).Save(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FastStatus_Save_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FastStatusServer).Save(ctx, req.(*SaveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FastStatus_BatchGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FastStatusServer).BatchGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FastStatus_BatchGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FastStatusServer).BatchGet(ctx, req.(*BatchGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FastStatus_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FastStatusServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FastStatus_List_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FastStatusServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FastStatus_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FastStatusServer).Watch(m, &grpc.GenericServerStream[WatchRequest, Resource]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FastStatus_WatchServer = grpc.ServerStreamingServer[Resource]
+
+// FastStatus_ServiceDesc is the grpc.ServiceDesc for FastStatus service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FastStatus_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "faststatus.FastStatus",
+	HandlerType: (*FastStatusServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Get",
+			Handler:    _FastStatus_Get_Handler,
+		},
+		{
+			MethodName: "Save",
+			Handler:    _FastStatus_Save_Handler,
+		},
+		{
+			MethodName: "BatchGet",
+			Handler:    _FastStatus_BatchGet_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _FastStatus_List_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _FastStatus_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "faststatuspb/service.proto",
+}
@@ -0,0 +1,76 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+// An IDGenerator generates new IDs. Most code should use CryptoGenerator,
+// but tests and simulations may use a deterministic generator so their IDs
+// are reproducible.
+type IDGenerator interface {
+	NewID() (ID, error)
+}
+
+// IDGeneratorFunc allows an ordinary function to be used as an IDGenerator.
+type IDGeneratorFunc func() (ID, error)
+
+// NewID calls fn().
+func (fn IDGeneratorFunc) NewID() (ID, error) {
+	return fn()
+}
+
+// CryptoGenerator generates random version 4 IDs from crypto/rand, just
+// like NewID.
+var CryptoGenerator IDGenerator = IDGeneratorFunc(NewID)
+
+// NewSeededGenerator returns an IDGenerator of version 4 IDs drawn from a
+// pseudo-random source with the given seed. Generators with the same seed
+// produce the same sequence of IDs. It is safe for concurrent use, but the
+// IDs are predictable, so it is not suitable outside of tests and
+// simulations.
+func NewSeededGenerator(seed int64) IDGenerator {
+	var mu sync.Mutex
+	src := rand.New(rand.NewSource(seed))
+	return IDGeneratorFunc(func() (ID, error) {
+		id := ID{}
+		mu.Lock()
+		_, err := src.Read(id[:])
+		mu.Unlock()
+		if err != nil {
+			return ID{}, fmt.Errorf("reading seeded bytes for new ID: %w", err)
+		}
+		id[6] = (id[6] & 0x0f) | (4 << 4)
+		id[8] = (id[8] & 0xbf) | 0x80
+		return id, nil
+	})
+}
+
+// NewSequentialGenerator returns an IDGenerator that counts up from start,
+// treating the ID as a big-endian number. The first ID generated is the one
+// after start. The IDs are not valid UUIDs of any version, which makes them
+// easy to spot in test output. It is safe for concurrent use, and returns an
+// error once the count would wrap around to zero.
+func NewSequentialGenerator(start ID) IDGenerator {
+	var (
+		mu        sync.Mutex
+		next      = start
+		exhausted bool
+	)
+	return IDGeneratorFunc(func() (ID, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		for i := len(next) - 1; i >= 0 && !exhausted; i-- {
+			next[i]++
+			if next[i] != 0 {
+				return next, nil
+			}
+		}
+		exhausted = true
+		return ID{}, fmt.Errorf("sequential IDs exhausted")
+	})
+}
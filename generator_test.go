@@ -0,0 +1,133 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus_test
+
+import (
+	"fmt"
+	"testing"
+	"testing/quick"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+func TestCryptoGeneratorIsV4(t *testing.T) {
+	isV4 := func() bool {
+		id, err := faststatus.CryptoGenerator.NewID()
+		return err == nil && id.Version() == 4
+	}
+	if err := quick.Check(isV4, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSeededGeneratorIsReproducible(t *testing.T) {
+	f := func(seed int64) bool {
+		a, b := faststatus.NewSeededGenerator(seed), faststatus.NewSeededGenerator(seed)
+		for i := 0; i < 10; i++ {
+			idA, errA := a.NewID()
+			idB, errB := b.NewID()
+			if errA != nil || errB != nil || idA != idB || idA.Version() != 4 {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSeededGeneratorIsUnique(t *testing.T) {
+	gen := faststatus.NewSeededGenerator(1)
+	seen := make(map[faststatus.ID]struct{})
+	for i := 0; i < 1000; i++ {
+		id, err := gen.NewID()
+		if err != nil {
+			t.Fatalf("NewID() = %+v, expected no error", err)
+		}
+		if _, ok := seen[id]; ok {
+			t.Fatalf("NewID() = %s, which was already generated", id)
+		}
+		seen[id] = struct{}{}
+	}
+}
+
+func TestSequentialGenerator(t *testing.T) {
+	testCases := []struct {
+		name    string
+		start   faststatus.ID
+		want    []faststatus.ID
+		wantErr bool
+	}{
+		{"from zero",
+			faststatus.ID{},
+			[]faststatus.ID{
+				{15: 0x01},
+				{15: 0x02},
+				{15: 0x03},
+			},
+			false,
+		},
+		{"carries",
+			faststatus.ID{14: 0x01, 15: 0xfe},
+			[]faststatus.ID{
+				{14: 0x01, 15: 0xff},
+				{14: 0x02, 15: 0x00},
+			},
+			false,
+		},
+		{"exhausted",
+			faststatus.ID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe},
+			[]faststatus.ID{
+				{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			},
+			true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			gen := faststatus.NewSequentialGenerator(tc.start)
+			for _, want := range tc.want {
+				got, err := gen.NewID()
+				if err != nil {
+					t.Fatalf("NewID() = %+v, expected no error", err)
+				}
+				if got != want {
+					t.Fatalf("NewID() = %x, expected %x", got, want)
+				}
+			}
+			if !tc.wantErr {
+				return
+			}
+			for i := 0; i < 2; i++ {
+				if _, err := gen.NewID(); err == nil {
+					t.Fatalf("NewID() = <nil>, expected error once exhausted")
+				}
+			}
+		})
+	}
+}
+
+func TestNewResourceWith(t *testing.T) {
+	t.Run("generator error", func(t *testing.T) {
+		gen := faststatus.IDGeneratorFunc(func() (faststatus.ID, error) {
+			return faststatus.ID{}, fmt.Errorf("an error")
+		})
+		if _, err := faststatus.NewResourceWith(gen); err == nil {
+			t.Fatalf("NewResourceWith() = <nil>, expected error")
+		}
+	})
+
+	t.Run("generated ID", func(t *testing.T) {
+		gen := faststatus.NewSequentialGenerator(faststatus.ID{})
+		got, err := faststatus.NewResourceWith(gen)
+		if err != nil {
+			t.Fatalf("NewResourceWith() = %+v, expected no error", err)
+		}
+		if want := (faststatus.Resource{ID: faststatus.ID{15: 0x01}}); !got.Equal(want) {
+			t.Fatalf("NewResourceWith() = %+v, expected %+v", got, want)
+		}
+	})
+}
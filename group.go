@@ -0,0 +1,323 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// A Group is a named set of Resources that are often considered together,
+// like a floor of meeting rooms or a pool of build servers.
+type Group struct {
+	ID      ID
+	Name    string
+	Members []ID
+}
+
+// NewGroup creates a new Group with a generated ID and otherwise zero-value properties.
+func NewGroup() Group {
+	id, _ := NewID()
+	return Group{ID: id}
+}
+
+// Equal allows quick equality comparison for two group values. Members
+// are compared in order.
+func (g Group) Equal(other Group) bool {
+	if g.ID != other.ID || g.Name != other.Name || len(g.Members) != len(other.Members) {
+		return false
+	}
+	for i := range g.Members {
+		if g.Members[i] != other.Members[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// String will return the text representation of a valid group.
+func (g Group) String() string {
+	txt, err := g.MarshalText()
+	if err != nil {
+		return ""
+	}
+	return string(txt)
+}
+
+// MarshalText encodes a Group to the text representation. The first line
+// holds the group ID and name, followed by one line for each member ID:
+//   {{ID}} {{Name}}
+//   {{Member}}
+// Formatted as follows:
+//   01234567-89ab-cdef-0123-456789abcdef Floor 3
+//   23456789-abcd-ef01-2345-6789abcdef01
+//   456789ab-cdef-0123-4567-89abcdef0123
+func (g Group) MarshalText() ([]byte, error) {
+	txt := make([]byte, 0, 37*(len(g.Members)+1)+len(g.Name))
+
+	id, err := g.ID.MarshalText()
+	if err != nil {
+		return nil, fmt.Errorf("marshaling text for ID: %w", err)
+	}
+	txt = append(txt, id...)
+	if len(g.Name) > 0 {
+		txt = append(txt, ' ')
+		txt = append(txt, g.Name...)
+	}
+
+	for _, member := range g.Members {
+		m, err := member.MarshalText()
+		if err != nil {
+			return nil, fmt.Errorf("marshaling text for member ID: %w", err)
+		}
+		txt = append(txt, '\n')
+		txt = append(txt, m...)
+	}
+
+	return txt, nil
+}
+
+// UnmarshalText decodes a Group from text. This matches the output of the
+// `MarshalText` method, though blank lines are ignored.
+func (g *Group) UnmarshalText(txt []byte) error {
+	lines := bytes.Split(txt, []byte("\n"))
+
+	tmp := Group{}
+
+	header := bytes.SplitN(lines[0], []byte(" "), 2)
+	if err := (&tmp.ID).UnmarshalText(header[0]); err != nil {
+		return fmt.Errorf("parsing ID from text: %w", err)
+	}
+	if len(header) > 1 {
+		tmp.Name = string(header[1])
+	}
+
+	for _, line := range lines[1:] {
+		if len(line) == 0 {
+			continue
+		}
+		var member ID
+		if err := (&member).UnmarshalText(line); err != nil {
+			return fmt.Errorf("parsing member ID from text: %w", err)
+		}
+		tmp.Members = append(tmp.Members, member)
+	}
+
+	*g = tmp
+	return nil
+}
+
+// MarshalJSON will return a simple json structure for a group.
+func (g Group) MarshalJSON() ([]byte, error) {
+	members := g.Members
+	if members == nil {
+		members = []ID{}
+	}
+	tmpGroup := struct {
+		ID      ID     `json:"id"`
+		Name    string `json:"name"`
+		Members []ID   `json:"members"`
+	}{
+		g.ID,
+		g.Name,
+		members,
+	}
+	return json.Marshal(tmpGroup)
+}
+
+// UnmarshalJSON will populate a Group with data from a json struct
+// according to the same format as MarshalJSON. Will overwrite any values
+// already assigned to the Group.
+func (g *Group) UnmarshalJSON(raw []byte) error {
+	tmp := new(struct {
+		ID      ID
+		Name    string
+		Members []ID
+	})
+	if err := json.Unmarshal(raw, tmp); err != nil {
+		return err
+	}
+
+	g.ID = tmp.ID
+	g.Name = tmp.Name
+	g.Members = nil
+	if len(tmp.Members) > 0 {
+		g.Members = tmp.Members
+	}
+	return nil
+}
+
+const groupBinaryVersion = 0x00
+
+// MarshalBinary returns a portable binary version of a Group. The header
+// matches that of a Resource, and is followed by the group ID, a varint
+// count of members, the member IDs, and finally the name.
+func (g Group) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 4+16, 4+16+binary.MaxVarintLen64+16*len(g.Members)+len(g.Name))
+
+	copy(b[0:2], MagicBytes[:])
+	b[2] = groupBinaryVersion
+
+	id, err := g.ID.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("marshaling ID to binary: %w", err)
+	}
+	copy(b[4:20], id)
+
+	var count [binary.MaxVarintLen64]byte
+	b = append(b, count[:binary.PutUvarint(count[:], uint64(len(g.Members)))]...)
+	for _, member := range g.Members {
+		m, err := member.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("marshaling member ID to binary: %w", err)
+		}
+		b = append(b, m...)
+	}
+
+	return append(b, g.Name...), nil
+}
+
+// UnmarshalBinary replaces a Group with the Group represented by the
+// binary input. The input binary must match the form of the MarshalBinary
+// method.
+func (g *Group) UnmarshalBinary(b []byte) error {
+	switch {
+	case len(b) < 21:
+		return fmt.Errorf("input binary data too short")
+	case !bytes.Equal(b[0:2], MagicBytes[:]):
+		return fmt.Errorf("unexpected magic bytes")
+	case b[2] > groupBinaryVersion:
+		return fmt.Errorf("unexpected version number for binary format")
+	default:
+	}
+
+	tmp := Group{}
+
+	if err := (&tmp.ID).UnmarshalBinary(b[4:20]); err != nil {
+		return fmt.Errorf("parsing ID from binary: %w", err)
+	}
+
+	count, n := binary.Uvarint(b[20:])
+	if n <= 0 {
+		return fmt.Errorf("parsing member count from binary")
+	}
+	b = b[20+n:]
+	if count > uint64(len(b))/16 {
+		return fmt.Errorf("input binary data too short for %d members", count)
+	}
+	for i := uint64(0); i < count; i++ {
+		var member ID
+		if err := (&member).UnmarshalBinary(b[0:16]); err != nil {
+			return fmt.Errorf("parsing member ID from binary: %w", err)
+		}
+		tmp.Members = append(tmp.Members, member)
+		b = b[16:]
+	}
+	tmp.Name = string(b)
+
+	*g = tmp
+	return nil
+}
+
+// GroupStatus summarizes the Status of every member of a Group.
+type GroupStatus struct {
+	Status Status
+	Counts map[Status]int
+}
+
+// Summarize aggregates the Status of the given Resources. The aggregate
+// Status is Free if any resource is Free, Busy if any other resource is
+// Busy, Unknown if every resource is Unknown, and Occupied otherwise, since
+// no resource that is known is available. No resources at all means
+// nothing is available, so that is reported as Occupied.
+func Summarize(resources ...Resource) GroupStatus {
+	gs := GroupStatus{Counts: make(map[Status]int)}
+	for s := Free; s <= Unknown; s++ {
+		gs.Counts[s] = 0
+	}
+	for _, r := range resources {
+		gs.Counts[r.Status]++
+	}
+	switch {
+	case gs.Counts[Free] > 0:
+		gs.Status = Free
+	case gs.Counts[Busy] > 0:
+		gs.Status = Busy
+	case len(resources) > 0 && gs.Counts[Unknown] == len(resources):
+		gs.Status = Unknown
+	default:
+		gs.Status = Occupied
+	}
+	return gs
+}
+
+// MarshalText encodes a GroupStatus as the aggregate Status followed by
+// the count for each Status:
+//   busy free=0 busy=2 occupied=1 unknown=0
+func (gs GroupStatus) MarshalText() ([]byte, error) {
+	txt := make([]byte, 0, 64)
+
+	status, err := gs.Status.MarshalText()
+	if err != nil {
+		return nil, fmt.Errorf("marshaling Status to text: %w", err)
+	}
+	txt = append(txt, status...)
+
+	for s := Free; s <= Unknown; s++ {
+		name, _ := s.MarshalText()
+		txt = append(txt, ' ')
+		txt = append(txt, name...)
+		txt = append(txt, '=')
+		txt = strconv.AppendInt(txt, int64(gs.Counts[s]), 10)
+	}
+
+	return txt, nil
+}
+
+// UnmarshalText decodes a GroupStatus from text matching the output of the
+// `MarshalText` method.
+func (gs *GroupStatus) UnmarshalText(txt []byte) error {
+	elements := bytes.Split(txt, []byte(" "))
+
+	tmp := GroupStatus{Counts: make(map[Status]int)}
+
+	if err := (&tmp.Status).UnmarshalText(elements[0]); err != nil {
+		return fmt.Errorf("parsing Status from text: %w", err)
+	}
+
+	for _, element := range elements[1:] {
+		pair := bytes.SplitN(element, []byte("="), 2)
+		if len(pair) != 2 {
+			return fmt.Errorf("invalid status count %q", element)
+		}
+		var s Status
+		if err := (&s).UnmarshalText(pair[0]); err != nil {
+			return fmt.Errorf("parsing Status from count: %w", err)
+		}
+		count, err := strconv.Atoi(string(pair[1]))
+		if err != nil {
+			return fmt.Errorf("parsing count for %s: %w", s, err)
+		}
+		tmp.Counts[s] = count
+	}
+
+	*gs = tmp
+	return nil
+}
+
+// MarshalJSON returns a json structure with the aggregate status and the
+// count for each status, keyed by name.
+func (gs GroupStatus) MarshalJSON() ([]byte, error) {
+	tmpStatus := struct {
+		Status Status         `json:"status"`
+		Counts map[Status]int `json:"counts"`
+	}{
+		gs.Status,
+		gs.Counts,
+	}
+	return json.Marshal(tmpStatus)
+}
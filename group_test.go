@@ -0,0 +1,248 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+func TestGroupMarshalText(t *testing.T) {
+	testCases := []struct {
+		name      string
+		group     faststatus.Group
+		wantValue []byte
+	}{
+		{"zero value",
+			faststatus.Group{},
+			[]byte("00000000-0000-0000-0000-000000000000"),
+		},
+		{"name without members",
+			faststatus.Group{
+				ID:   faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+				Name: "Floor 3",
+			},
+			[]byte("01234567-89ab-cdef-0123-456789abcdef Floor 3"),
+		},
+		{"name with members",
+			faststatus.Group{
+				ID:   faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+				Name: "Floor 3",
+				Members: []faststatus.ID{
+					{0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01},
+					{0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23},
+				},
+			},
+			[]byte("01234567-89ab-cdef-0123-456789abcdef Floor 3\n23456789-abcd-ef01-2345-6789abcdef01\n456789ab-cdef-0123-4567-89abcdef0123"),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.group.MarshalText()
+			if err != nil {
+				t.Fatalf("%+v.MarshalText() = %+v, expected no error", tc.group, err)
+			}
+			if !reflect.DeepEqual(got, tc.wantValue) {
+				t.Fatalf("%+v.MarshalText() = %q, expected %q", tc.group, got, tc.wantValue)
+			}
+		})
+	}
+}
+
+func TestGroupUnmarshalTextBadData(t *testing.T) {
+	testCases := []struct {
+		name string
+		txt  []byte
+	}{
+		{"empty", []byte("")},
+		{"invalid id", []byte("not-an-id Floor 3")},
+		{"invalid member", []byte("01234567-89ab-cdef-0123-456789abcdef Floor 3\nnot-an-id")},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got faststatus.Group
+			if err := (&got).UnmarshalText(tc.txt); err == nil {
+				t.Fatalf("group.UnmarshalText(%q) = nil, expected error", tc.txt)
+			}
+		})
+	}
+}
+
+func TestGroupMarshalUnmarshalText(t *testing.T) {
+	f := func(g faststatus.Group) bool {
+		b, err := g.MarshalText()
+		if err != nil {
+			return false
+		}
+		got := new(faststatus.Group)
+		if err := got.UnmarshalText(b); err != nil {
+			t.Logf("unmarshaling %q: %+v", b, err)
+			return false
+		}
+		return got.Equal(g)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGroupMarshalUnmarshalJSON(t *testing.T) {
+	f := func(g faststatus.Group) bool {
+		b, err := json.Marshal(g)
+		if err != nil {
+			return false
+		}
+		got := new(faststatus.Group)
+		if err := json.Unmarshal(b, got); err != nil {
+			t.Logf("unmarshaling %s: %+v", b, err)
+			return false
+		}
+		return got.Equal(g)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGroupMarshalUnmarshalBinary(t *testing.T) {
+	f := func(g faststatus.Group) bool {
+		b, err := g.MarshalBinary()
+		if err != nil {
+			return false
+		}
+		got := new(faststatus.Group)
+		if err := got.UnmarshalBinary(b); err != nil {
+			t.Logf("unmarshaling %x: %+v", b, err)
+			return false
+		}
+		return got.Equal(g)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGroupUnmarshalBinaryTruncated(t *testing.T) {
+	f := func(g faststatus.Group) bool {
+		if len(g.Members) == 0 {
+			return true
+		}
+		b, _ := g.MarshalBinary()
+		b = b[:len(b)-len(g.Name)-1]
+		return new(faststatus.Group).UnmarshalBinary(b) != nil
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGroupUnmarshalBinaryHugeCount(t *testing.T) {
+	g := faststatus.Group{Name: "floor 3"}
+	valid, err := g.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v.MarshalBinary() = %+v, expected no error", g, err)
+	}
+	// 16 times each count overflows 64 bits, or is just past the data
+	for _, count := range []uint64{1 << 60, 1<<60 + 1, math.MaxUint64, uint64(len(g.Name))/16 + 1} {
+		b := append([]byte{}, valid[:20]...)
+		b = binary.AppendUvarint(b, count)
+		b = append(b, g.Name...)
+		if err := new(faststatus.Group).UnmarshalBinary(b); err == nil {
+			t.Errorf("UnmarshalBinary(%x) with %d members = <nil>, expected error", b, count)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	testCases := []struct {
+		name       string
+		statuses   []faststatus.Status
+		wantStatus faststatus.Status
+		wantText   string
+	}{
+		{"no members",
+			nil,
+			faststatus.Occupied,
+			"occupied free=0 busy=0 occupied=0 unknown=0",
+		},
+		{"any free",
+			[]faststatus.Status{faststatus.Occupied, faststatus.Free, faststatus.Busy},
+			faststatus.Free,
+			"free free=1 busy=1 occupied=1 unknown=0",
+		},
+		{"all occupied",
+			[]faststatus.Status{faststatus.Occupied, faststatus.Occupied},
+			faststatus.Occupied,
+			"occupied free=0 busy=0 occupied=2 unknown=0",
+		},
+		{"some busy",
+			[]faststatus.Status{faststatus.Occupied, faststatus.Busy, faststatus.Busy},
+			faststatus.Busy,
+			"busy free=0 busy=2 occupied=1 unknown=0",
+		},
+		{"occupied and unknown",
+			[]faststatus.Status{faststatus.Occupied, faststatus.Occupied, faststatus.Unknown},
+			faststatus.Occupied,
+			"occupied free=0 busy=0 occupied=2 unknown=1",
+		},
+		{"busy and unknown",
+			[]faststatus.Status{faststatus.Busy, faststatus.Unknown},
+			faststatus.Busy,
+			"busy free=0 busy=1 occupied=0 unknown=1",
+		},
+		{"all unknown",
+			[]faststatus.Status{faststatus.Unknown, faststatus.Unknown},
+			faststatus.Unknown,
+			"unknown free=0 busy=0 occupied=0 unknown=2",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			resources := make([]faststatus.Resource, len(tc.statuses))
+			for i, s := range tc.statuses {
+				resources[i] = faststatus.NewResource()
+				resources[i].Status = s
+			}
+			got := faststatus.Summarize(resources...)
+			if got.Status != tc.wantStatus {
+				t.Fatalf("Summarize(%+v).Status = %s, expected %s", tc.statuses, got.Status, tc.wantStatus)
+			}
+			txt, err := got.MarshalText()
+			if err != nil {
+				t.Fatalf("%+v.MarshalText() = %+v, expected no error", got, err)
+			}
+			if string(txt) != tc.wantText {
+				t.Fatalf("%+v.MarshalText() = %q, expected %q", got, txt, tc.wantText)
+			}
+			var roundTrip faststatus.GroupStatus
+			if err := (&roundTrip).UnmarshalText(txt); err != nil {
+				t.Fatalf("UnmarshalText(%q) = %+v, expected no error", txt, err)
+			}
+			if !reflect.DeepEqual(roundTrip, got) {
+				t.Fatalf("UnmarshalText(%q) = %+v, expected %+v", txt, roundTrip, got)
+			}
+		})
+	}
+}
+
+func TestGroupStatusMarshalJSON(t *testing.T) {
+	gs := faststatus.Summarize(faststatus.Resource{Status: faststatus.Busy})
+	got, err := json.Marshal(gs)
+	if err != nil {
+		t.Fatalf("json.Marshal(%+v) = %+v, expected no error", gs, err)
+	}
+	want := `{"status":"busy","counts":{"busy":1,"free":0,"occupied":0,"unknown":0}}`
+	if string(got) != want {
+		t.Fatalf("json.Marshal(%+v) = %s, expected %s", gs, got, want)
+	}
+}
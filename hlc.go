@@ -0,0 +1,191 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// An HLC is a hybrid logical clock timestamp: physical time, a logical
+// counter to order events within the same physical time, and the ID of the
+// node that made it to break any remaining ties. HLCs from Clocks that
+// have seen each other's timestamps are totally ordered, consistent with
+// causality, even when the physical clocks are skewed. The zero value is
+// an unset HLC.
+type HLC struct {
+	// Wall is the physical time, in nanoseconds since the Unix epoch.
+	Wall int64
+	// Logical orders events that share the same Wall.
+	Logical uint32
+	// Node identifies the Clock that made the HLC.
+	Node uint32
+}
+
+// IsZero reports whether the HLC is unset.
+func (h HLC) IsZero() bool {
+	return h == HLC{}
+}
+
+// Compare returns -1 if h is before other, +1 if it is after, and 0 if the
+// two are the same. The Wall is compared first, then Logical, then Node.
+func (h HLC) Compare(other HLC) int {
+	switch {
+	case h.Wall < other.Wall:
+		return -1
+	case h.Wall > other.Wall:
+		return 1
+	case h.Logical < other.Logical:
+		return -1
+	case h.Logical > other.Logical:
+		return 1
+	case h.Node < other.Node:
+		return -1
+	case h.Node > other.Node:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// After reports whether h is after other.
+func (h HLC) After(other HLC) bool {
+	return h.Compare(other) > 0
+}
+
+// Before reports whether h is before other.
+func (h HLC) Before(other HLC) bool {
+	return h.Compare(other) < 0
+}
+
+// hlcTextLen is the length of the text representation of an HLC.
+const hlcTextLen = 16 + 1 + 8 + 1 + 8
+
+// String returns the text representation of an HLC.
+func (h HLC) String() string {
+	txt, _ := h.MarshalText()
+	return string(txt)
+}
+
+// MarshalText encodes an HLC as fixed-width hex fields, so that the text
+// sorts in the same order as the HLCs, formatted as follows:
+//   {{Wall}}-{{Logical}}-{{Node}}
+//   14acd3a2f1c84000-00000003-0000002a
+func (h HLC) MarshalText() ([]byte, error) {
+	return h.AppendText(make([]byte, 0, hlcTextLen))
+}
+
+// AppendText appends the text representation of an HLC to dst.
+func (h HLC) AppendText(dst []byte) ([]byte, error) {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[0:8], uint64(h.Wall))
+	binary.BigEndian.PutUint32(b[8:12], h.Logical)
+	binary.BigEndian.PutUint32(b[12:16], h.Node)
+	var txt [hlcTextLen]byte
+	hex.Encode(txt[0:16], b[0:8])
+	txt[16] = '-'
+	hex.Encode(txt[17:25], b[8:12])
+	txt[25] = '-'
+	hex.Encode(txt[26:34], b[12:16])
+	return append(dst, txt[:]...), nil
+}
+
+// UnmarshalText decodes an HLC from the output of the `MarshalText` method.
+func (h *HLC) UnmarshalText(txt []byte) error {
+	if len(txt) != hlcTextLen || txt[16] != '-' || txt[25] != '-' {
+		return fmt.Errorf("invalid HLC text")
+	}
+	var b [16]byte
+	for _, part := range []struct{ dst, src []byte }{
+		{b[0:8], txt[0:16]},
+		{b[8:12], txt[17:25]},
+		{b[12:16], txt[26:34]},
+	} {
+		if _, err := hex.Decode(part.dst, part.src); err != nil {
+			return fmt.Errorf("invalid HLC text: %w", err)
+		}
+	}
+	return h.UnmarshalBinary(b[:])
+}
+
+// MarshalBinary encodes an HLC as 16 bytes: the Wall, Logical and Node,
+// each big-endian.
+func (h HLC) MarshalBinary() ([]byte, error) {
+	return h.AppendBinary(make([]byte, 0, 16))
+}
+
+// AppendBinary appends the binary representation of an HLC to dst.
+func (h HLC) AppendBinary(dst []byte) ([]byte, error) {
+	dst = binary.BigEndian.AppendUint64(dst, uint64(h.Wall))
+	dst = binary.BigEndian.AppendUint32(dst, h.Logical)
+	return binary.BigEndian.AppendUint32(dst, h.Node), nil
+}
+
+// UnmarshalBinary decodes an HLC from the output of the `MarshalBinary`
+// method.
+func (h *HLC) UnmarshalBinary(b []byte) error {
+	if len(b) != 16 {
+		return fmt.Errorf("HLC binary must be 16 bytes")
+	}
+	h.Wall = int64(binary.BigEndian.Uint64(b[0:8]))
+	h.Logical = binary.BigEndian.Uint32(b[8:12])
+	h.Node = binary.BigEndian.Uint32(b[12:16])
+	return nil
+}
+
+// A Clock makes HLCs for one node. It is safe for concurrent use.
+type Clock struct {
+	node uint32
+	now  func() time.Time
+
+	mu   sync.Mutex
+	last HLC
+}
+
+// NewClock returns a Clock for the node with the given ID, which should be
+// unique among the nodes that write the same Resources.
+func NewClock(node uint32) *Clock {
+	return &Clock{node: node, now: time.Now}
+}
+
+// Now returns an HLC for a local event, like a write, after every HLC the
+// Clock has made or seen.
+func (c *Clock) Now() HLC {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	wall := c.now().UnixNano()
+	if wall > c.last.Wall {
+		c.last = HLC{Wall: wall, Node: c.node}
+	} else {
+		c.last = HLC{Wall: c.last.Wall, Logical: c.last.Logical + 1, Node: c.node}
+	}
+	return c.last
+}
+
+// Update returns an HLC for receiving a remote HLC, after both it and
+// every HLC the Clock has made or seen.
+func (c *Clock) Update(remote HLC) HLC {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	wall := c.now().UnixNano()
+	next := HLC{Wall: wall, Node: c.node}
+	switch {
+	case wall > c.last.Wall && wall > remote.Wall:
+	case c.last.Wall == remote.Wall:
+		next.Wall = c.last.Wall
+		next.Logical = c.last.Logical + 1
+		if remote.Logical >= c.last.Logical {
+			next.Logical = remote.Logical + 1
+		}
+	case c.last.Wall > remote.Wall:
+		next.Wall, next.Logical = c.last.Wall, c.last.Logical+1
+	default:
+		next.Wall, next.Logical = remote.Wall, remote.Logical+1
+	}
+	c.last = next
+	return next
+}
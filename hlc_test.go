@@ -0,0 +1,207 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+func TestHLCCompare(t *testing.T) {
+	testCases := []struct {
+		name string
+		a, b faststatus.HLC
+		want int
+	}{
+		{"zero", faststatus.HLC{}, faststatus.HLC{}, 0},
+		{"same", faststatus.HLC{Wall: 5, Logical: 2, Node: 1}, faststatus.HLC{Wall: 5, Logical: 2, Node: 1}, 0},
+		{"wall first", faststatus.HLC{Wall: 4, Logical: 9, Node: 9}, faststatus.HLC{Wall: 5}, -1},
+		{"then logical", faststatus.HLC{Wall: 5, Logical: 3}, faststatus.HLC{Wall: 5, Logical: 2, Node: 9}, 1},
+		{"then node", faststatus.HLC{Wall: 5, Logical: 2, Node: 1}, faststatus.HLC{Wall: 5, Logical: 2, Node: 2}, -1},
+		{"negative wall", faststatus.HLC{Wall: -1}, faststatus.HLC{}, -1},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.a.Compare(tc.b); got != tc.want {
+				t.Fatalf("%v.Compare(%v) = %d, expected %d", tc.a, tc.b, got, tc.want)
+			}
+			if got := tc.b.Compare(tc.a); got != -tc.want {
+				t.Fatalf("%v.Compare(%v) = %d, expected %d", tc.b, tc.a, got, -tc.want)
+			}
+			if got := tc.a.After(tc.b); got != (tc.want > 0) {
+				t.Fatalf("%v.After(%v) = %t, expected %t", tc.a, tc.b, got, tc.want > 0)
+			}
+			if got := tc.a.Before(tc.b); got != (tc.want < 0) {
+				t.Fatalf("%v.Before(%v) = %t, expected %t", tc.a, tc.b, got, tc.want < 0)
+			}
+		})
+	}
+}
+
+func TestHLCText(t *testing.T) {
+	h := faststatus.HLC{Wall: 0x14acd3a2f1c84000, Logical: 3, Node: 42}
+	const want = "14acd3a2f1c84000-00000003-0000002a"
+	if got := h.String(); got != want {
+		t.Fatalf("%#v.String() = %q, expected %q", h, got, want)
+	}
+
+	f := func(a, b faststatus.HLC) bool {
+		at, err := a.MarshalText()
+		if err != nil {
+			return false
+		}
+		bt, err := b.MarshalText()
+		if err != nil {
+			return false
+		}
+		var got faststatus.HLC
+		if err := (&got).UnmarshalText(at); err != nil || got != a {
+			return false
+		}
+		// non-negative walls sort the same as text
+		if a.Wall < 0 || b.Wall < 0 {
+			return true
+		}
+		return bytes.Compare(at, bt) == a.Compare(b)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHLCUnmarshalTextBadData(t *testing.T) {
+	for _, txt := range []string{
+		"",
+		"14acd3a2f1c84000-00000003",
+		"14acd3a2f1c84000-00000003-0000002a0",
+		"14acd3a2f1c84000_00000003_0000002a",
+		"14acd3a2f1c8400g-00000003-0000002a",
+		"14acd3a2f1c84000-0000000x-0000002a",
+	} {
+		var h faststatus.HLC
+		if err := (&h).UnmarshalText([]byte(txt)); err == nil {
+			t.Errorf("UnmarshalText(%q) = <nil>, expected error", txt)
+		}
+	}
+}
+
+func TestHLCBinary(t *testing.T) {
+	f := func(h faststatus.HLC) bool {
+		b, err := h.MarshalBinary()
+		if err != nil || len(b) != 16 {
+			return false
+		}
+		var got faststatus.HLC
+		return (&got).UnmarshalBinary(b) == nil && got == h
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+	var h faststatus.HLC
+	if err := (&h).UnmarshalBinary(make([]byte, 15)); err == nil {
+		t.Fatalf("UnmarshalBinary of 15 bytes = <nil>, expected error")
+	}
+}
+
+func TestClockNow(t *testing.T) {
+	now := time.Unix(100, 0)
+	c := faststatus.NewClock(7)
+	c.SetNow(func() time.Time { return now })
+
+	first := c.Now()
+	if want := (faststatus.HLC{Wall: now.UnixNano(), Node: 7}); first != want {
+		t.Fatalf("Now() = %v, expected %v", first, want)
+	}
+	// the physical clock stands still, and then goes backwards
+	second := c.Now()
+	now = now.Add(-time.Second)
+	third := c.Now()
+	if !second.After(first) || !third.After(second) || third.Wall != first.Wall {
+		t.Fatalf("Now() = %v, %v, %v, expected increasing at the same Wall", first, second, third)
+	}
+	now = now.Add(2 * time.Second)
+	if fourth := c.Now(); fourth.Logical != 0 || !fourth.After(third) {
+		t.Fatalf("Now() = %v after the physical clock passed %v, expected a new Wall", fourth, third)
+	}
+}
+
+func TestClockUpdate(t *testing.T) {
+	now := time.Unix(100, 0)
+	testCases := []struct {
+		name   string
+		last   faststatus.HLC
+		remote faststatus.HLC
+		want   faststatus.HLC
+	}{
+		{"physical ahead",
+			faststatus.HLC{Wall: now.UnixNano() - 2, Logical: 5},
+			faststatus.HLC{Wall: now.UnixNano() - 1, Logical: 3},
+			faststatus.HLC{Wall: now.UnixNano(), Node: 1},
+		},
+		{"remote ahead",
+			faststatus.HLC{Wall: now.UnixNano(), Logical: 5},
+			faststatus.HLC{Wall: now.UnixNano() + 10, Logical: 3, Node: 2},
+			faststatus.HLC{Wall: now.UnixNano() + 10, Logical: 4, Node: 1},
+		},
+		{"local ahead",
+			faststatus.HLC{Wall: now.UnixNano() + 10, Logical: 5},
+			faststatus.HLC{Wall: now.UnixNano() + 5, Logical: 8, Node: 2},
+			faststatus.HLC{Wall: now.UnixNano() + 10, Logical: 6, Node: 1},
+		},
+		{"same wall",
+			faststatus.HLC{Wall: now.UnixNano() + 10, Logical: 5},
+			faststatus.HLC{Wall: now.UnixNano() + 10, Logical: 8, Node: 2},
+			faststatus.HLC{Wall: now.UnixNano() + 10, Logical: 9, Node: 1},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := faststatus.NewClock(1)
+			c.SetNow(func() time.Time { return time.Unix(0, tc.last.Wall) })
+			for i := uint32(0); i <= tc.last.Logical; i++ {
+				c.Now()
+			}
+			c.SetNow(func() time.Time { return now })
+			got := c.Update(tc.remote)
+			if got != tc.want {
+				t.Fatalf("Update(%v) = %v, expected %v", tc.remote, got, tc.want)
+			}
+			if !got.After(tc.remote) {
+				t.Fatalf("Update(%v) = %v, expected after the remote HLC", tc.remote, got)
+			}
+		})
+	}
+}
+
+func TestClockConcurrent(t *testing.T) {
+	c := faststatus.NewClock(1)
+	c.SetNow(func() time.Time { return time.Unix(100, 0) })
+	const n = 100
+	results := make(chan faststatus.HLC, 2*n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- c.Now()
+			results <- c.Update(faststatus.HLC{Wall: 100, Node: 2})
+		}()
+	}
+	wg.Wait()
+	close(results)
+	seen := make(map[faststatus.HLC]bool)
+	for h := range results {
+		if seen[h] {
+			t.Fatalf("HLC %v returned more than once", h)
+		}
+		seen[h] = true
+	}
+}
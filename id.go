@@ -4,13 +4,19 @@
 package faststatus
 
 import (
+	"bytes"
 	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"sync"
+	"time"
 )
 
-// ID is a UUIDv4 compatible byte array. Implementation is based on
-// portions of github.com/satori/go.uuid.
+// ID is a UUID compatible byte array: a random version 4, a name-based
+// version 5, or a time-ordered version 7. Implementation is based on portions of
+// github.com/satori/go.uuid.
 type ID [16]byte
 
 // NewID generates a new version 4 UUID. Returns errors from reading the
@@ -18,18 +24,101 @@ type ID [16]byte
 func NewID() (ID, error) {
 	id := ID{}
 	if _, err := rand.Read(id[:]); err != nil {
-		return ID{}, fmt.Errorf("reading random bytes for new ID: %+v", err)
+		return ID{}, fmt.Errorf("reading random bytes for new ID: %w", err)
 	}
 	id[6] = (id[6] & 0x0f) | (4 << 4)
 	id[8] = (id[8] & 0xbf) | 0x80
 	return id, nil
 }
 
+// Namespaces for NewIDFromName, as predefined by RFC 4122.
+var (
+	NamespaceDNS = ID{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	NamespaceURL = ID{0x6b, 0xa7, 0xb8, 0x11, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+)
+
+// NewIDFromName derives a version 5 UUID from the SHA-1 hash of a namespace
+// and a name. The same namespace and name always give the same ID, so well
+// known resources can have their IDs computed without a lookup.
+func NewIDFromName(namespace ID, name string) ID {
+	h := sha1.New()
+	h.Write(namespace[:])
+	h.Write([]byte(name))
+
+	id := ID{}
+	copy(id[:], h.Sum(nil))
+	id[6] = (id[6] & 0x0f) | (5 << 4)
+	id[8] = (id[8] & 0xbf) | 0x80
+	return id
+}
+
+// v7 holds the last timestamp and counter used by NewIDv7, so IDs from one
+// process sort in the order they were created.
+var v7 struct {
+	sync.Mutex
+	ms  uint64
+	seq uint16
+}
+
+// NewIDv7 generates a new version 7 UUID, which starts with a millisecond
+// Unix timestamp so that IDs sort by creation time. IDs created within the
+// same millisecond are ordered by a 12 bit counter. Returns errors from
+// reading the entropy source.
+func NewIDv7() (ID, error) {
+	id := ID{}
+	if _, err := rand.Read(id[8:]); err != nil {
+		return ID{}, fmt.Errorf("reading random bytes for new ID: %w", err)
+	}
+
+	v7.Lock()
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	if ms > v7.ms {
+		v7.ms, v7.seq = ms, 0
+	} else if v7.seq++; v7.seq > 0x0fff {
+		v7.ms, v7.seq = v7.ms+1, 0
+	}
+	ms, seq := v7.ms, v7.seq
+	v7.Unlock()
+
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], ms)
+	copy(id[0:6], ts[2:])
+	binary.BigEndian.PutUint16(id[6:8], seq)
+	id[6] = (id[6] & 0x0f) | (7 << 4)
+	id[8] = (id[8] & 0xbf) | 0x80
+	return id, nil
+}
+
+// Version returns the UUID version of the ID, like 4 for a random ID from
+// NewID, 5 for a name-based ID from NewIDFromName, or 7 for a time-ordered
+// ID from NewIDv7.
+func (id ID) Version() int {
+	return int(id[6] >> 4)
+}
+
+// Time returns the creation time embedded in a version 7 ID, to the
+// millisecond. Other versions carry no time and return the zero value.
+func (id ID) Time() time.Time {
+	if id.Version() != 7 {
+		return time.Time{}
+	}
+	var ts [8]byte
+	copy(ts[2:], id[0:6])
+	ms := int64(binary.BigEndian.Uint64(ts[:]))
+	return time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond)).UTC()
+}
+
 // MarshalBinary implements the encoding.BinaryMarshaler interface
 func (id ID) MarshalBinary() ([]byte, error) {
 	return id[:], nil
 }
 
+// AppendBinary appends the 16 bytes of the id to dst, implementing the
+// encoding.BinaryAppender interface.
+func (id ID) AppendBinary(dst []byte) ([]byte, error) {
+	return append(dst, id[:]...), nil
+}
+
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
 // and enforces the 16 byte length of an ID
 func (id *ID) UnmarshalBinary(b []byte) error {
@@ -43,31 +132,91 @@ func (id *ID) UnmarshalBinary(b []byte) error {
 // MarshalText outputs the id as the canonical hexadecimal representation:
 // xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx.
 func (id ID) MarshalText() ([]byte, error) {
-	txt := make([]byte, 36)
+	return id.AppendText(make([]byte, 0, 36))
+}
+
+// AppendText appends the canonical hexadecimal representation of the id
+// to dst, implementing the encoding.TextAppender interface.
+func (id ID) AppendText(dst []byte) ([]byte, error) {
+	n := len(dst)
+	dst = append(dst, "00000000-0000-0000-0000-000000000000"...)
+	txt := dst[n:]
 
 	hex.Encode(txt[0:8], id[0:4])
-	txt[8] = '-'
 	hex.Encode(txt[9:13], id[4:6])
-	txt[13] = '-'
 	hex.Encode(txt[14:18], id[6:8])
-	txt[18] = '-'
 	hex.Encode(txt[19:23], id[8:10])
-	txt[23] = '-'
 	hex.Encode(txt[24:], id[10:])
 
-	return txt, nil
+	return dst, nil
 }
 
+// ShortString returns the id as 26 characters of Crockford's base32, which
+// is shorter than the canonical form for use in URLs, QR codes and text
+// messages. Like the canonical form, it sorts in the same order as the ID.
+func (id ID) ShortString() string {
+	txt := make([]byte, 1, shortLen)
+	txt[0] = crockford[id[0]>>5]
+	acc, bits := uint(id[0]&0x1f), uint(5)
+	for _, b := range id[1:] {
+		acc = acc<<8 | uint(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			txt = append(txt, crockford[(acc>>bits)&0x1f])
+		}
+	}
+	return string(txt)
+}
+
+const (
+	crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	shortLen  = 26
+	urnPrefix = "urn:uuid:"
+)
+
+// crockfordValues maps each character to its value in Crockford's base32,
+// or 0xff if it is not part of the alphabet. Decoding is case insensitive,
+// and the easily confused I, L and O decode as 1, 1 and 0.
+var crockfordValues = func() [256]byte {
+	var values [256]byte
+	for i := range values {
+		values[i] = 0xff
+	}
+	for i := 0; i < len(crockford); i++ {
+		values[crockford[i]] = byte(i)
+		values[crockford[i]|0x20] = byte(i)
+	}
+	for c, v := range map[byte]byte{'I': 1, 'L': 1, 'O': 0} {
+		values[c] = v
+		values[c|0x20] = v
+	}
+	return values
+}()
+
 // UnmarshalText populates the id with a uuid value represented by the text in
 // the canonical form: xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx. While any of the
 // dashes in the form may be left out, any non-hexadecimal characters will result
-// in an error.
+// in an error. The canonical form may also be wrapped as a URN, like
+// urn:uuid:xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx, or in braces, like
+// {xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx}. The 26 character form from the
+// `ShortString` method is accepted as well.
 func (id *ID) UnmarshalText(txt []byte) error {
+	switch {
+	case len(txt) > len(urnPrefix) && bytes.EqualFold(txt[:len(urnPrefix)], []byte(urnPrefix)):
+		txt = txt[len(urnPrefix):]
+	case len(txt) > 2 && txt[0] == '{' && txt[len(txt)-1] == '}':
+		txt = txt[1 : len(txt)-1]
+	case len(txt) == shortLen:
+		return id.unmarshalShort(txt)
+	default:
+	}
+
 	if len(txt) < 32 {
 		return fmt.Errorf("UUID text must be longer than 32 characters")
 	}
 
-	buf := make([]byte, 16)
+	var buf [16]byte
 
 	var i int
 	for _, n := range []int{8, 4, 4, 4, 12} {
@@ -75,7 +224,7 @@ func (id *ID) UnmarshalText(txt []byte) error {
 			txt = txt[1:]
 		}
 		if _, err := hex.Decode(buf[i:i+(n/2)], txt[0:n]); err != nil {
-			return fmt.Errorf("decoding hex into uuid: %+v", err)
+			return fmt.Errorf("decoding hex into uuid: %w", err)
 		}
 		txt = txt[n:]
 		i = i + (n / 2)
@@ -84,5 +233,29 @@ func (id *ID) UnmarshalText(txt []byte) error {
 		return fmt.Errorf("too long for uuid: %+v", txt)
 	}
 
+	return id.UnmarshalBinary(buf[:])
+}
+
+// unmarshalShort populates the id from the output of the `ShortString`
+// method. The first character holds only the top 3 bits of the ID.
+func (id *ID) unmarshalShort(txt []byte) error {
+	var arr [16]byte
+	buf := arr[:0]
+	acc, bits := uint(crockfordValues[txt[0]]), uint(3)
+	if acc > 7 {
+		return fmt.Errorf("decoding base32 into uuid: invalid character %q", txt[0])
+	}
+	for _, c := range txt[1:] {
+		v := crockfordValues[c]
+		if v == 0xff {
+			return fmt.Errorf("decoding base32 into uuid: invalid character %q", c)
+		}
+		acc = acc<<5 | uint(v)
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			buf = append(buf, byte(acc>>bits))
+		}
+	}
 	return id.UnmarshalBinary(buf)
 }
@@ -6,8 +6,10 @@ package faststatus_test
 import (
 	"bytes"
 	"reflect"
+	"strings"
 	"testing"
 	"testing/quick"
+	"time"
 
 	"github.com/lazyengineering/faststatus"
 )
@@ -32,6 +34,103 @@ func TestNewIDIsUUID(t *testing.T) {
 	}
 }
 
+func TestNewIDv7IsV7(t *testing.T) {
+	isV7 := func() bool {
+		id, err := faststatus.NewIDv7()
+		return err == nil && id.Version() == 7 && (id[8]&0xc0)|0x80 == 0x80
+	}
+	if err := quick.Check(isV7, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNewIDv7Time(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	id, err := faststatus.NewIDv7()
+	if err != nil {
+		t.Fatalf("NewIDv7() = %+v, expected no error", err)
+	}
+	after := time.Now()
+	if got := id.Time(); got.Before(before) || got.After(after) {
+		t.Fatalf("Time() = %s, expected between %s and %s", got, before, after)
+	}
+}
+
+func TestNewIDv7IsOrdered(t *testing.T) {
+	prev, _ := faststatus.NewIDv7()
+	for i := 0; i < 10000; i++ {
+		id, err := faststatus.NewIDv7()
+		if err != nil {
+			t.Fatalf("NewIDv7() = %+v, expected no error", err)
+		}
+		if bytes.Compare(prev[:], id[:]) >= 0 {
+			t.Fatalf("NewIDv7() = %s after %s, expected ascending order", id, prev)
+		}
+		prev = id
+	}
+}
+
+func TestNewIDFromName(t *testing.T) {
+	testCases := []struct {
+		name      string
+		namespace faststatus.ID
+		input     string
+		want      string
+	}{
+		{"dns", faststatus.NamespaceDNS, "python.org", "886313e1-3b8a-5372-9b90-0c9aee199e5d"},
+		{"url", faststatus.NamespaceURL, "http://python.org/", "4c565f0d-3f5a-5890-b41b-20cf47701c5e"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			id := faststatus.NewIDFromName(tc.namespace, tc.input)
+			if got, _ := id.MarshalText(); string(got) != tc.want {
+				t.Fatalf("NewIDFromName(%q) = %s, expected %s", tc.input, got, tc.want)
+			}
+			if v := id.Version(); v != 5 {
+				t.Fatalf("Version() = %d, expected 5", v)
+			}
+		})
+	}
+}
+
+func TestNewIDFromNameIsDeterministic(t *testing.T) {
+	f := func(namespace faststatus.ID, name string) bool {
+		return faststatus.NewIDFromName(namespace, name) == faststatus.NewIDFromName(namespace, name)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestIDVersion(t *testing.T) {
+	testCases := []struct {
+		name        string
+		txt         string
+		wantVersion int
+		wantTime    time.Time
+	}{
+		{"zero", "00000000-0000-0000-0000-000000000000", 0, time.Time{}},
+		{"version 4", "6789abcd-ef01-4345-8789-abcdef012345", 4, time.Time{}},
+		{"version 7", "0158b2c1-6d00-7123-8789-abcdef012345", 7, time.Date(2016, 11, 30, 1, 2, 50, 624000000, time.UTC)},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var id faststatus.ID
+			if err := (&id).UnmarshalText([]byte(tc.txt)); err != nil {
+				t.Fatalf("UnmarshalText(%q) = %+v, expected no error", tc.txt, err)
+			}
+			if got := id.Version(); got != tc.wantVersion {
+				t.Fatalf("Version() = %d, expected %d", got, tc.wantVersion)
+			}
+			if got := id.Time(); !got.Equal(tc.wantTime) {
+				t.Fatalf("Time() = %s, expected %s", got, tc.wantTime)
+			}
+		})
+	}
+}
+
 func TestIDMarshalBinary(t *testing.T) {
 	is16Bytes := func(id faststatus.ID) bool {
 		b, err := id.MarshalBinary()
@@ -214,6 +313,51 @@ func TestIDUnmarshalText(t *testing.T) {
 			false,
 			faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
 		},
+		{"urn",
+			[]byte("urn:uuid:01234567-89ab-cdef-0123-456789abcdef"),
+			false,
+			faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		},
+		{"urn, upper case",
+			[]byte("URN:UUID:0123456789ABCDEF0123456789ABCDEF"),
+			false,
+			faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		},
+		{"urn, wrong namespace",
+			[]byte("urn:uuie:01234567-89ab-cdef-0123-456789abcdef"),
+			true,
+			faststatus.ID{},
+		},
+		{"braces",
+			[]byte("{01234567-89ab-cdef-0123-456789abcdef}"),
+			false,
+			faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		},
+		{"unclosed brace",
+			[]byte("{01234567-89ab-cdef-0123-456789abcdef"),
+			true,
+			faststatus.ID{},
+		},
+		{"short",
+			[]byte("014D2PF2DBSQQG28T5CY4TQKFF"),
+			false,
+			faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		},
+		{"short, lower case and confusable characters",
+			[]byte("o14d2pf2dbsqqg28t5cy4tqkff"),
+			false,
+			faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		},
+		{"short, out of range",
+			[]byte("814D2PF2DBSQQG28T5CY4TQKFF"),
+			true,
+			faststatus.ID{},
+		},
+		{"short, invalid character",
+			[]byte("014D2PF2DBSQQG28T5CY4TQKFU"),
+			true,
+			faststatus.ID{},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -248,3 +392,65 @@ func TestIDMarshalUnmarshalText(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestIDShortString(t *testing.T) {
+	id := faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	if got, want := id.ShortString(), "014D2PF2DBSQQG28T5CY4TQKFF"; got != want {
+		t.Fatalf("ShortString() = %q, expected %q", got, want)
+	}
+	if got, want := (faststatus.ID{}).ShortString(), "00000000000000000000000000"; got != want {
+		t.Fatalf("ShortString() = %q, expected %q", got, want)
+	}
+}
+
+func TestIDShortStringUnmarshalText(t *testing.T) {
+	f := func(id faststatus.ID) bool {
+		got := new(faststatus.ID)
+		err := got.UnmarshalText([]byte(id.ShortString()))
+		return err == nil && *got == id
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIDShortStringIsOrdered(t *testing.T) {
+	f := func(a, b faststatus.ID) bool {
+		return bytes.Compare(a[:], b[:]) == strings.Compare(a.ShortString(), b.ShortString())
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIDAppendText(t *testing.T) {
+	f := func(prefix []byte, id faststatus.ID) bool {
+		want, _ := id.MarshalText()
+		got, err := id.AppendText(append([]byte{}, prefix...))
+		return err == nil && bytes.Equal(got, append(append([]byte{}, prefix...), want...))
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIDAppendBinary(t *testing.T) {
+	f := func(prefix []byte, id faststatus.ID) bool {
+		got, err := id.AppendBinary(append([]byte{}, prefix...))
+		return err == nil && bytes.Equal(got, append(append([]byte{}, prefix...), id[:]...))
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIDUnmarshalTextAllocs(t *testing.T) {
+	id, _ := faststatus.NewID()
+	txt, _ := id.MarshalText()
+	short := []byte(id.ShortString())
+	for _, in := range [][]byte{txt, short} {
+		if allocs := testing.AllocsPerRun(100, func() { new(faststatus.ID).UnmarshalText(in) }); allocs != 0 {
+			t.Fatalf("UnmarshalText(%s) allocates %v times per run, expected none", in, allocs)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+// Package watch subscribes the servers of the rest and rpc packages to the
+// changes in a Store, for streaming them to clients.
+package watch
+
+import (
+	"sync"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+// Store calls a function with each Resource as it becomes the most recent
+// version.
+type Store interface {
+	Watch(func(faststatus.Resource)) (cancel func())
+}
+
+// buffer is how many changes a subscriber may fall behind before it
+// overflows.
+const buffer = 64
+
+// Subscribe watches ws for the Resources that match. Each matching
+// Resource is received from changes as it becomes the most recent version.
+// If the subscriber falls too far behind, overflow is closed and later
+// changes are dropped, so the subscriber should end its stream. Call
+// cancel to stop watching.
+func Subscribe(ws Store, match func(faststatus.Resource) bool) (changes <-chan faststatus.Resource, overflow <-chan struct{}, cancel func()) {
+	var (
+		c    = make(chan faststatus.Resource, buffer)
+		o    = make(chan struct{})
+		once sync.Once
+	)
+	cancel = ws.Watch(func(r faststatus.Resource) {
+		if !match(r) {
+			return
+		}
+		select {
+		case c <- r:
+		default:
+			once.Do(func() { close(o) })
+		}
+	})
+	return c, o, cancel
+}
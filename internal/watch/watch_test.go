@@ -0,0 +1,57 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package watch_test
+
+import (
+	"testing"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/internal/watch"
+)
+
+func TestSubscribe(t *testing.T) {
+	ms := &mockStore{}
+	busy := func(r faststatus.Resource) bool { return r.Status == faststatus.Busy }
+	changes, overflow, cancel := watch.Subscribe(ms, busy)
+	defer cancel()
+
+	free, want := faststatus.NewResource(), faststatus.NewResource()
+	want.Status = faststatus.Busy
+	ms.fn(free)
+	ms.fn(want)
+	select {
+	case got := <-changes:
+		if !got.Equal(want) {
+			t.Fatalf("received %+v, expected %+v", got, want)
+		}
+	default:
+		t.Fatalf("received nothing, expected %+v", want)
+	}
+
+	// falling behind closes overflow instead of blocking the Store
+	for i := 0; ; i++ {
+		select {
+		case <-overflow:
+			cancel()
+			if ms.fn != nil {
+				t.Fatalf("still watching after cancel")
+			}
+			return
+		default:
+		}
+		if i > 1000 {
+			t.Fatalf("overflow not closed after %d changes", i)
+		}
+		ms.fn(want)
+	}
+}
+
+type mockStore struct {
+	fn func(faststatus.Resource)
+}
+
+func (s *mockStore) Watch(fn func(faststatus.Resource)) func() {
+	s.fn = fn
+	return func() { s.fn = nil }
+}
@@ -0,0 +1,539 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// MessagePack formats used by the MessagePack encoding.
+const (
+	msgpackNil      = 0xc0
+	msgpackBin8     = 0xc4
+	msgpackBin16    = 0xc5
+	msgpackBin32    = 0xc6
+	msgpackExt8     = 0xc7
+	msgpackExt16    = 0xc8
+	msgpackExt32    = 0xc9
+	msgpackFloat32  = 0xca
+	msgpackFloat64  = 0xcb
+	msgpackUint8    = 0xcc
+	msgpackUint16   = 0xcd
+	msgpackUint32   = 0xce
+	msgpackUint64   = 0xcf
+	msgpackInt8     = 0xd0
+	msgpackInt16    = 0xd1
+	msgpackInt32    = 0xd2
+	msgpackInt64    = 0xd3
+	msgpackFixExt1  = 0xd4
+	msgpackFixExt2  = 0xd5
+	msgpackFixExt4  = 0xd6
+	msgpackFixExt8  = 0xd7
+	msgpackFixExt16 = 0xd8
+	msgpackStr8     = 0xd9
+	msgpackStr16    = 0xda
+	msgpackStr32    = 0xdb
+	msgpackArray16  = 0xdc
+	msgpackArray32  = 0xdd
+	msgpackMap16    = 0xde
+	msgpackMap32    = 0xdf
+
+	msgpackFixMap   = 0x80
+	msgpackFixArray = 0x90
+	msgpackFixStr   = 0xa0
+
+	msgpackExtTimestamp = -1
+)
+
+// MarshalMsgpack encodes an ID as a MessagePack bin of 16 bytes.
+func (id ID) MarshalMsgpack() ([]byte, error) {
+	return id.appendMsgpack(make([]byte, 0, 18)), nil
+}
+
+func (id ID) appendMsgpack(b []byte) []byte {
+	return append(append(b, msgpackBin8, 16), id[:]...)
+}
+
+// UnmarshalMsgpack decodes an ID from a MessagePack bin of 16 bytes.
+func (id *ID) UnmarshalMsgpack(b []byte) error {
+	r := msgpackReader{b}
+	tmp, err := r.id()
+	if err != nil {
+		return err
+	}
+	if len(r.b) > 0 {
+		return fmt.Errorf("unexpected data after MessagePack ID")
+	}
+	*id = tmp
+	return nil
+}
+
+// MarshalMsgpack encodes a Status as a MessagePack positive fixint. An
+// invalid Status (out of range, etc.) will result in an error.
+func (s Status) MarshalMsgpack() ([]byte, error) {
+	if s > Unknown {
+		return nil, errOutOfRange
+	}
+	return []byte{byte(s)}, nil
+}
+
+// UnmarshalMsgpack decodes a Status from a MessagePack integer.
+func (s *Status) UnmarshalMsgpack(b []byte) error {
+	r := msgpackReader{b}
+	tmp, err := r.status()
+	if err != nil {
+		return err
+	}
+	if len(r.b) > 0 {
+		return fmt.Errorf("unexpected data after MessagePack Status")
+	}
+	*s = tmp
+	return nil
+}
+
+// MarshalMsgpack encodes a Resource as a MessagePack map with the keys
+// "id", "status" and "since", like the json structure, and "labels",
+// "message" and "until" when they are set. Since and Until use the
+// timestamp extension type, keeping full precision but not the time zone.
+// A zero-value Since is nil.
+func (r Resource) MarshalMsgpack() ([]byte, error) {
+	if r.Status > Unknown {
+		return nil, fmt.Errorf("marshaling Status to MessagePack: %w", errOutOfRange)
+	}
+	if err := checkMessage(r.Message); err != nil {
+		return nil, fmt.Errorf("marshaling Message to MessagePack: %w", err)
+	}
+	if err := checkUntil(r.Since, r.Until); err != nil {
+		return nil, fmt.Errorf("marshaling Until to MessagePack: %w", err)
+	}
+	n := byte(3)
+	for _, set := range []bool{len(r.Labels) > 0, r.Message != "", !r.Until.IsZero()} {
+		if set {
+			n++
+		}
+	}
+
+	b := make([]byte, 0, 48)
+	b = append(b, msgpackFixMap|n)
+	b = appendMsgpackStr(b, "id")
+	b = r.ID.appendMsgpack(b)
+	b = appendMsgpackStr(b, "status")
+	b = append(b, byte(r.Status))
+	b = appendMsgpackStr(b, "since")
+	if r.Since.IsZero() {
+		b = append(b, msgpackNil)
+	} else {
+		b = appendMsgpackTime(b, r.Since)
+	}
+	if len(r.Labels) > 0 {
+		b = appendMsgpackStr(b, "labels")
+		b = appendMsgpackMapLen(b, len(r.Labels))
+		for _, k := range sortedKeys(r.Labels) {
+			b = appendMsgpackStr(appendMsgpackStr(b, k), r.Labels[k])
+		}
+	}
+	if r.Message != "" {
+		b = appendMsgpackStr(appendMsgpackStr(b, "message"), r.Message)
+	}
+	if !r.Until.IsZero() {
+		b = appendMsgpackTime(appendMsgpackStr(b, "until"), r.Until)
+	}
+	return b, nil
+}
+
+// UnmarshalMsgpack decodes a Resource from a MessagePack map matching the
+// output of the `MarshalMsgpack` method. Unknown keys are skipped, and
+// missing keys are left as the zero value.
+func (r *Resource) UnmarshalMsgpack(b []byte) error {
+	mr := msgpackReader{b}
+	n, err := mr.mapLen()
+	if err != nil {
+		return err
+	}
+
+	tmp := Resource{}
+	for i := 0; i < n; i++ {
+		key, err := mr.str()
+		if err != nil {
+			return fmt.Errorf("parsing MessagePack map key: %w", err)
+		}
+		switch key {
+		case "id":
+			if tmp.ID, err = mr.id(); err != nil {
+				return fmt.Errorf("parsing ID from MessagePack: %w", err)
+			}
+		case "status":
+			if tmp.Status, err = mr.status(); err != nil {
+				return fmt.Errorf("parsing Status from MessagePack: %w", err)
+			}
+		case "since":
+			if tmp.Since, err = mr.time(); err != nil {
+				return fmt.Errorf("parsing Since from MessagePack: %w", err)
+			}
+		case "labels":
+			if tmp.Labels, err = mr.labels(); err != nil {
+				return fmt.Errorf("parsing Labels from MessagePack: %w", err)
+			}
+		case "message":
+			if tmp.Message, err = mr.str(); err != nil {
+				return fmt.Errorf("parsing Message from MessagePack: %w", err)
+			}
+			if err := checkMessage(tmp.Message); err != nil {
+				return fmt.Errorf("parsing Message from MessagePack: %w", err)
+			}
+		case "until":
+			if tmp.Until, err = mr.time(); err != nil {
+				return fmt.Errorf("parsing Until from MessagePack: %w", err)
+			}
+		default:
+			if err := mr.skip(0); err != nil {
+				return fmt.Errorf("skipping MessagePack value for %q: %w", key, err)
+			}
+		}
+	}
+	if len(mr.b) > 0 {
+		return fmt.Errorf("unexpected data after MessagePack Resource")
+	}
+	if err := checkUntil(tmp.Since, tmp.Until); err != nil {
+		return fmt.Errorf("parsing Until from MessagePack: %w", err)
+	}
+
+	*r = tmp
+	return nil
+}
+
+func appendMsgpackStr(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		b = append(b, msgpackFixStr|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, msgpackStr8, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, msgpackStr16), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, msgpackStr32), uint32(n))
+	}
+	return append(b, s...)
+}
+
+func appendMsgpackMapLen(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, msgpackFixMap|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, msgpackMap16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, msgpackMap32), uint32(n))
+	}
+}
+
+// appendMsgpackTime appends the smallest form of the timestamp extension
+// that can hold t.
+func appendMsgpackTime(b []byte, t time.Time) []byte {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	switch {
+	case sec >= 0 && sec < 1<<32 && nsec == 0:
+		b = append(b, msgpackFixExt4, 0xff)
+		return binary.BigEndian.AppendUint32(b, uint32(sec))
+	case sec >= 0 && sec < 1<<34:
+		b = append(b, msgpackFixExt8, 0xff)
+		return binary.BigEndian.AppendUint64(b, nsec<<34|uint64(sec))
+	default:
+		b = append(b, msgpackExt8, 12, 0xff)
+		b = binary.BigEndian.AppendUint32(b, uint32(nsec))
+		return binary.BigEndian.AppendUint64(b, uint64(sec))
+	}
+}
+
+// maxMsgpackDepth bounds how deeply nested an unknown value may be.
+const maxMsgpackDepth = 16
+
+// msgpackReader reads the subset of MessagePack needed for the faststatus
+// types.
+type msgpackReader struct {
+	b []byte
+}
+
+// bytes returns the next n bytes.
+func (r *msgpackReader) bytes(n uint64) ([]byte, error) {
+	if uint64(len(r.b)) < n {
+		return nil, fmt.Errorf("unexpected end of MessagePack data")
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b, nil
+}
+
+// uint reads a big-endian unsigned integer of size bytes.
+func (r *msgpackReader) uint(size uint64) (uint64, error) {
+	b, err := r.bytes(size)
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	for _, c := range b {
+		n = n<<8 | uint64(c)
+	}
+	return n, nil
+}
+
+func (r *msgpackReader) format() (byte, error) {
+	b, err := r.bytes(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (r *msgpackReader) mapLen() (int, error) {
+	f, err := r.format()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case f&0xf0 == msgpackFixMap:
+		return int(f & 0x0f), nil
+	case f == msgpackMap16:
+		n, err := r.uint(2)
+		return int(n), err
+	case f == msgpackMap32:
+		n, err := r.uint(4)
+		return int(n), err
+	default:
+		return 0, fmt.Errorf("expected MessagePack map")
+	}
+}
+
+// labels reads a map of str to str, which is nil when empty.
+func (r *msgpackReader) labels() (map[string]string, error) {
+	n, err := r.mapLen()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string)
+	for i := 0; i < n; i++ {
+		k, err := r.str()
+		if err != nil {
+			return nil, err
+		}
+		if labels[k], err = r.str(); err != nil {
+			return nil, err
+		}
+	}
+	return labels, nil
+}
+
+func (r *msgpackReader) str() (string, error) {
+	f, err := r.format()
+	if err != nil {
+		return "", err
+	}
+	var n uint64
+	switch {
+	case f&0xe0 == msgpackFixStr:
+		n = uint64(f & 0x1f)
+	case f == msgpackStr8:
+		n, err = r.uint(1)
+	case f == msgpackStr16:
+		n, err = r.uint(2)
+	case f == msgpackStr32:
+		n, err = r.uint(4)
+	default:
+		return "", fmt.Errorf("expected MessagePack str")
+	}
+	if err != nil {
+		return "", err
+	}
+	b, err := r.bytes(n)
+	return string(b), err
+}
+
+func (r *msgpackReader) id() (ID, error) {
+	f, err := r.format()
+	if err != nil {
+		return ID{}, err
+	}
+	var n uint64
+	switch f {
+	case msgpackBin8:
+		n, err = r.uint(1)
+	case msgpackBin16:
+		n, err = r.uint(2)
+	case msgpackBin32:
+		n, err = r.uint(4)
+	default:
+		return ID{}, fmt.Errorf("ID must be a MessagePack bin of 16 bytes")
+	}
+	if err != nil {
+		return ID{}, err
+	}
+	if n != 16 {
+		return ID{}, fmt.Errorf("ID must be a MessagePack bin of 16 bytes")
+	}
+	b, err := r.bytes(n)
+	if err != nil {
+		return ID{}, err
+	}
+	var id ID
+	copy(id[:], b)
+	return id, nil
+}
+
+func (r *msgpackReader) status() (Status, error) {
+	f, err := r.format()
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	switch {
+	case f < 0x80:
+		n = uint64(f)
+	case f == msgpackUint8, f == msgpackInt8:
+		n, err = r.uint(1)
+	case f == msgpackUint16, f == msgpackInt16:
+		n, err = r.uint(2)
+	case f == msgpackUint32, f == msgpackInt32:
+		n, err = r.uint(4)
+	case f == msgpackUint64, f == msgpackInt64:
+		n, err = r.uint(8)
+	default:
+		return 0, fmt.Errorf("Status must be a MessagePack integer")
+	}
+	if err != nil {
+		return 0, err
+	}
+	// negative signed values become large here, and so are out of range
+	if n > uint64(Unknown) {
+		return 0, errOutOfRange
+	}
+	return Status(n), nil
+}
+
+func (r *msgpackReader) time() (time.Time, error) {
+	f, err := r.format()
+	if err != nil {
+		return time.Time{}, err
+	}
+	var size uint64
+	switch f {
+	case msgpackNil:
+		return time.Time{}, nil
+	case msgpackFixExt4:
+		size = 4
+	case msgpackFixExt8:
+		size = 8
+	case msgpackExt8:
+		if size, err = r.uint(1); err != nil {
+			return time.Time{}, err
+		}
+	default:
+		return time.Time{}, fmt.Errorf("time must be a MessagePack timestamp")
+	}
+	typ, err := r.format()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if int8(typ) != msgpackExtTimestamp {
+		return time.Time{}, fmt.Errorf("unexpected MessagePack extension type %d for time", int8(typ))
+	}
+	b, err := r.bytes(size)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var sec int64
+	var nsec uint32
+	switch size {
+	case 4:
+		sec = int64(binary.BigEndian.Uint32(b))
+	case 8:
+		v := binary.BigEndian.Uint64(b)
+		sec, nsec = int64(v&(1<<34-1)), uint32(v>>34)
+	case 12:
+		nsec, sec = binary.BigEndian.Uint32(b), int64(binary.BigEndian.Uint64(b[4:]))
+	default:
+		return time.Time{}, fmt.Errorf("invalid MessagePack timestamp length %d", size)
+	}
+	if nsec >= 1e9 {
+		return time.Time{}, fmt.Errorf("invalid nanoseconds in MessagePack timestamp")
+	}
+	return time.Unix(sec, int64(nsec)), nil
+}
+
+// skip reads past the next value, however it is nested.
+func (r *msgpackReader) skip(depth int) error {
+	if depth > maxMsgpackDepth {
+		return fmt.Errorf("MessagePack data nested too deeply")
+	}
+	f, err := r.format()
+	if err != nil {
+		return err
+	}
+
+	var size, items uint64
+	switch {
+	case f < 0x80, f >= 0xe0, f == msgpackNil, f == 0xc2, f == 0xc3:
+		return nil
+	case f&0xf0 == msgpackFixMap:
+		items = 2 * uint64(f&0x0f)
+	case f&0xf0 == msgpackFixArray:
+		items = uint64(f & 0x0f)
+	case f&0xe0 == msgpackFixStr:
+		size = uint64(f & 0x1f)
+	case f == msgpackBin8, f == msgpackStr8:
+		size, err = r.uint(1)
+	case f == msgpackBin16, f == msgpackStr16:
+		size, err = r.uint(2)
+	case f == msgpackBin32, f == msgpackStr32:
+		size, err = r.uint(4)
+	case f == msgpackExt8:
+		size, err = r.uint(1)
+		size++
+	case f == msgpackExt16:
+		size, err = r.uint(2)
+		size++
+	case f == msgpackExt32:
+		size, err = r.uint(4)
+		size++
+	case f == msgpackUint8, f == msgpackInt8:
+		size = 1
+	case f == msgpackUint16, f == msgpackInt16:
+		size = 2
+	case f == msgpackUint32, f == msgpackInt32, f == msgpackFloat32:
+		size = 4
+	case f == msgpackUint64, f == msgpackInt64, f == msgpackFloat64:
+		size = 8
+	case f >= msgpackFixExt1 && f <= msgpackFixExt16:
+		size = 1 + 1<<(f-msgpackFixExt1)
+	case f == msgpackArray16:
+		items, err = r.uint(2)
+	case f == msgpackArray32:
+		items, err = r.uint(4)
+	case f == msgpackMap16:
+		items, err = r.uint(2)
+		items *= 2
+	case f == msgpackMap32:
+		items, err = r.uint(4)
+		items *= 2
+	default:
+		return fmt.Errorf("invalid MessagePack format 0x%02x", f)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := r.bytes(size); err != nil {
+		return err
+	}
+	for i := uint64(0); i < items; i++ {
+		if err := r.skip(depth + 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
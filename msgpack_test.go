@@ -0,0 +1,224 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus_test
+
+import (
+	"bytes"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+// msgpackTestResource is the head of a MessagePack Resource up to the
+// Since value.
+var msgpackTestResource = append(append([]byte{
+	0x83,
+	0xa2, 'i', 'd', 0xc4, 0x10},
+	cborTestID[:]...),
+	0xa6, 's', 't', 'a', 't', 'u', 's', 0x01,
+	0xa5, 's', 'i', 'n', 'c', 'e',
+)
+
+func msgpackResource(tail ...byte) []byte {
+	return append(append([]byte{}, msgpackTestResource...), tail...)
+}
+
+func TestIDMarshalMsgpack(t *testing.T) {
+	got, err := cborTestID.MarshalMsgpack()
+	if err != nil {
+		t.Fatalf("ID.MarshalMsgpack() = %+v, expected no error", err)
+	}
+	want := append([]byte{0xc4, 0x10}, cborTestID[:]...)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("ID.MarshalMsgpack() = %x, expected %x", got, want)
+	}
+}
+
+func TestIDUnmarshalMsgpack(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []byte
+		want      faststatus.ID
+		wantError bool
+	}{
+		{"bin8", append([]byte{0xc4, 0x10}, cborTestID[:]...), cborTestID, false},
+		{"bin16", append([]byte{0xc5, 0x00, 0x10}, cborTestID[:]...), cborTestID, false},
+		{"too short", append([]byte{0xc4, 0x0f}, cborTestID[:15]...), faststatus.ID{}, true},
+		{"truncated", append([]byte{0xc4, 0x10}, cborTestID[:15]...), faststatus.ID{}, true},
+		{"str", append([]byte{0xb0}, cborTestID[:]...), faststatus.ID{}, true},
+		{"trailing data", append(append([]byte{0xc4, 0x10}, cborTestID[:]...), 0x00), faststatus.ID{}, true},
+		{"empty", []byte{}, faststatus.ID{}, true},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got faststatus.ID
+			err := (&got).UnmarshalMsgpack(tc.input)
+			if (err != nil) != tc.wantError {
+				t.Fatalf("ID.UnmarshalMsgpack(%x) = %+v, expected error: %t", tc.input, err, tc.wantError)
+			}
+			if got != tc.want {
+				t.Fatalf("ID.UnmarshalMsgpack(%x) => %x, expected %x", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestStatusMarshalUnmarshalMsgpack(t *testing.T) {
+	f := func(s faststatus.Status) bool {
+		b, err := s.MarshalMsgpack()
+		if err != nil || len(b) != 1 {
+			t.Logf("Status(%d).MarshalMsgpack() = %x, %+v", s, b, err)
+			return false
+		}
+		var got faststatus.Status
+		if err := (&got).UnmarshalMsgpack(b); err != nil {
+			t.Logf("Status.UnmarshalMsgpack(%x) = %+v", b, err)
+			return false
+		}
+		return got == s
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStatusUnmarshalMsgpack(t *testing.T) {
+	tests := []struct {
+		name                  string
+		input                 []byte
+		want                  faststatus.Status
+		wantErrorIsOutOfRange bool
+	}{
+		{"fixint", []byte{0x02}, faststatus.Occupied, false},
+		{"uint8", []byte{0xcc, 0x03}, faststatus.Unknown, false},
+		{"int16", []byte{0xd1, 0x00, 0x01}, faststatus.Busy, false},
+		{"out of range", []byte{0x04}, faststatus.Free, true},
+		{"negative", []byte{0xd0, 0xff}, faststatus.Free, true},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got faststatus.Status
+			err := (&got).UnmarshalMsgpack(tc.input)
+			if tc.wantErrorIsOutOfRange != faststatus.IsOutOfRange(err) {
+				t.Fatalf("Status.UnmarshalMsgpack(%x) = %+v, expected out of range error: %t", tc.input, err, tc.wantErrorIsOutOfRange)
+			}
+			if !tc.wantErrorIsOutOfRange && err != nil {
+				t.Fatalf("Status.UnmarshalMsgpack(%x) = %+v, expected no error", tc.input, err)
+			}
+			if got != tc.want {
+				t.Fatalf("Status.UnmarshalMsgpack(%x) => %d, expected %d", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResourceMarshalMsgpack(t *testing.T) {
+	tests := []struct {
+		name  string
+		since time.Time
+		want  []byte
+	}{
+		{"zero time", time.Time{}, msgpackResource(0xc0)},
+		{"timestamp 32", time.Unix(1363896240, 0), msgpackResource(0xd6, 0xff, 0x51, 0x4b, 0x67, 0xb0)},
+		{"timestamp 64", time.Unix(1363896240, 1), msgpackResource(0xd7, 0xff, 0x00, 0x00, 0x00, 0x04, 0x51, 0x4b, 0x67, 0xb0)},
+		{"timestamp 96", time.Unix(-1, 0), msgpackResource(0xc7, 0x0c, 0xff, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff)},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			r := faststatus.Resource{ID: cborTestID, Status: faststatus.Busy, Since: tc.since}
+			got, err := r.MarshalMsgpack()
+			if err != nil {
+				t.Fatalf("%+v.MarshalMsgpack() = %+v, expected no error", r, err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("%+v.MarshalMsgpack() = %x, expected %x", r, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResourceUnmarshalMsgpack(t *testing.T) {
+	busy := func(since time.Time) faststatus.Resource {
+		return faststatus.Resource{ID: cborTestID, Status: faststatus.Busy, Since: since}
+	}
+	tests := []struct {
+		name      string
+		input     []byte
+		want      faststatus.Resource
+		wantError bool
+	}{
+		{"zero time", msgpackResource(0xc0), busy(time.Time{}), false},
+		{"timestamp 32", msgpackResource(0xd6, 0xff, 0x51, 0x4b, 0x67, 0xb0), busy(time.Unix(1363896240, 0)), false},
+		{"timestamp 64", msgpackResource(0xd7, 0xff, 0x00, 0x00, 0x00, 0x04, 0x51, 0x4b, 0x67, 0xb0), busy(time.Unix(1363896240, 1)), false},
+		{"timestamp 96", msgpackResource(0xc7, 0x0c, 0xff, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff), busy(time.Unix(-1, 0)), false},
+		{"unknown keys",
+			append([]byte{0x85,
+				0xa4, 'n', 'o', 't', 'e', 0x92, 0x01, 0x81, 0xa1, 'x', 0xc4, 0x03, 1, 2, 3,
+				0xa3, 'f', 'o', 'o', 0xd4, 0x01, 0x00},
+				msgpackResource(0xc0)[1:]...),
+			busy(time.Time{}),
+			false,
+		},
+		{"map16", append([]byte{0xde, 0x00, 0x03}, msgpackResource(0xc0)[1:]...), busy(time.Time{}), false},
+		{"missing keys", []byte{0x80}, faststatus.Resource{}, false},
+		{"not a map", []byte{0x90}, faststatus.Resource{}, true},
+		{"wrong extension type", msgpackResource(0xd6, 0x01, 0x51, 0x4b, 0x67, 0xb0), faststatus.Resource{}, true},
+		{"integer time", msgpackResource(0xce, 0x51, 0x4b, 0x67, 0xb0), faststatus.Resource{}, true},
+		{"bad nanoseconds", msgpackResource(0xc7, 0x0c, 0xff, 0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0, 0, 0, 0, 0), faststatus.Resource{}, true},
+		{"truncated", msgpackResource(0xd6, 0xff, 0x51), faststatus.Resource{}, true},
+		{"trailing data", msgpackResource(0xc0, 0x00), faststatus.Resource{}, true},
+		{"status out of range",
+			bytes.Replace(msgpackResource(0xc0), []byte{'s', 0x01}, []byte{'s', 0x04}, 1),
+			faststatus.Resource{},
+			true,
+		},
+		{"deeply nested unknown",
+			append([]byte{0x81, 0xa1, 'x'}, bytes.Repeat([]byte{0x91}, 64)...),
+			faststatus.Resource{},
+			true,
+		},
+		{"empty", []byte{}, faststatus.Resource{}, true},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got faststatus.Resource
+			err := (&got).UnmarshalMsgpack(tc.input)
+			if (err != nil) != tc.wantError {
+				t.Fatalf("Resource.UnmarshalMsgpack(%x) = %+v, expected error: %t", tc.input, err, tc.wantError)
+			}
+			if !got.Equal(tc.want) {
+				t.Fatalf("Resource.UnmarshalMsgpack(%x) => %+v, expected %+v", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResourceMarshalUnmarshalMsgpackQuick(t *testing.T) {
+	f := func(r faststatus.Resource) bool {
+		b, err := r.MarshalMsgpack()
+		if err != nil {
+			t.Logf("%+v.MarshalMsgpack() = %+v", r, err)
+			return false
+		}
+		var got faststatus.Resource
+		if err := (&got).UnmarshalMsgpack(b); err != nil {
+			t.Logf("Resource.UnmarshalMsgpack(%x) = %+v", b, err)
+			return false
+		}
+		if !got.Equal(r) {
+			t.Logf("UnmarshalMsgpack(MarshalMsgpack(%+v)) = %+v", r, got)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+// Occupancy counts how much of a Resource is in use, for resources like
+// parking lots or conference rooms where a sensor can report raw counts
+// rather than a Status.
+type Occupancy struct {
+	Count    int
+	Capacity int
+}
+
+// Status derives the Status for the Occupancy: Free when the count is zero,
+// Occupied when the count has reached capacity, and Busy otherwise. Without
+// a capacity, any non-zero count is Busy.
+func (o Occupancy) Status() Status {
+	switch {
+	case o.Count <= 0:
+		return Free
+	case o.Capacity > 0 && o.Count >= o.Capacity:
+		return Occupied
+	default:
+		return Busy
+	}
+}
+
+// Add returns the Occupancy with delta added to the count. The count will
+// not go below zero, but may exceed capacity.
+func (o Occupancy) Add(delta int) Occupancy {
+	o.Count += delta
+	if o.Count < 0 {
+		o.Count = 0
+	}
+	return o
+}
+
+// String returns the text representation of the Occupancy.
+func (o Occupancy) String() string {
+	txt, _ := o.MarshalText()
+	return string(txt)
+}
+
+// MarshalText encodes an Occupancy as the count and capacity separated by
+// a slash, like "3/10".
+func (o Occupancy) MarshalText() ([]byte, error) {
+	txt := make([]byte, 0, 16)
+	txt = strconv.AppendInt(txt, int64(o.Count), 10)
+	txt = append(txt, '/')
+	txt = strconv.AppendInt(txt, int64(o.Capacity), 10)
+	return txt, nil
+}
+
+// UnmarshalText decodes an Occupancy from text matching the output of the
+// `MarshalText` method. Negative values are not allowed.
+func (o *Occupancy) UnmarshalText(txt []byte) error {
+	elements := bytes.Split(txt, []byte("/"))
+	if len(elements) != 2 {
+		return fmt.Errorf("invalid occupancy text")
+	}
+	count, err := strconv.Atoi(string(elements[0]))
+	if err != nil {
+		return fmt.Errorf("parsing count from text: %w", err)
+	}
+	capacity, err := strconv.Atoi(string(elements[1]))
+	if err != nil {
+		return fmt.Errorf("parsing capacity from text: %w", err)
+	}
+	if count < 0 || capacity < 0 {
+		return fmt.Errorf("occupancy must not be negative")
+	}
+	o.Count = count
+	o.Capacity = capacity
+	return nil
+}
+
+// MarshalBinary encodes an Occupancy as two varints: count and capacity.
+func (o Occupancy) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 2*binary.MaxVarintLen64)
+	n := binary.PutUvarint(b, uint64(o.Count))
+	n += binary.PutUvarint(b[n:], uint64(o.Capacity))
+	return b[:n], nil
+}
+
+// UnmarshalBinary decodes an Occupancy from the output of the
+// `MarshalBinary` method.
+func (o *Occupancy) UnmarshalBinary(b []byte) error {
+	count, n := binary.Uvarint(b)
+	if n <= 0 {
+		return fmt.Errorf("parsing count from binary")
+	}
+	capacity, m := binary.Uvarint(b[n:])
+	if m <= 0 {
+		return fmt.Errorf("parsing capacity from binary")
+	}
+	if n+m != len(b) {
+		return fmt.Errorf("input binary data too long")
+	}
+	o.Count = int(count)
+	o.Capacity = int(capacity)
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus_test
+
+import (
+	"testing"
+	"testing/quick"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+func TestOccupancyStatus(t *testing.T) {
+	testCases := []struct {
+		name       string
+		occupancy  faststatus.Occupancy
+		wantStatus faststatus.Status
+	}{
+		{"zero value", faststatus.Occupancy{}, faststatus.Free},
+		{"empty", faststatus.Occupancy{Count: 0, Capacity: 10}, faststatus.Free},
+		{"partly full", faststatus.Occupancy{Count: 3, Capacity: 10}, faststatus.Busy},
+		{"full", faststatus.Occupancy{Count: 10, Capacity: 10}, faststatus.Occupied},
+		{"over capacity", faststatus.Occupancy{Count: 12, Capacity: 10}, faststatus.Occupied},
+		{"no capacity", faststatus.Occupancy{Count: 12}, faststatus.Busy},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.occupancy.Status(); got != tc.wantStatus {
+				t.Fatalf("%+v.Status() = %s, expected %s", tc.occupancy, got, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestOccupancyAddNeverNegative(t *testing.T) {
+	f := func(count uint16, delta int16) bool {
+		got := faststatus.Occupancy{Count: int(count), Capacity: 10}.Add(int(delta))
+		if int(count)+int(delta) < 0 {
+			return got.Count == 0
+		}
+		return got.Count == int(count)+int(delta)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOccupancyUnmarshalText(t *testing.T) {
+	testCases := []struct {
+		txt           string
+		wantError     bool
+		wantOccupancy faststatus.Occupancy
+	}{
+		{"3/10", false, faststatus.Occupancy{Count: 3, Capacity: 10}},
+		{"0/0", false, faststatus.Occupancy{}},
+		{"3", true, faststatus.Occupancy{}},
+		{"3/10/2", true, faststatus.Occupancy{}},
+		{"-1/10", true, faststatus.Occupancy{}},
+		{"a/10", true, faststatus.Occupancy{}},
+		{"3/b", true, faststatus.Occupancy{}},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.txt, func(t *testing.T) {
+			var got faststatus.Occupancy
+			err := (&got).UnmarshalText([]byte(tc.txt))
+			if (err != nil) != tc.wantError {
+				t.Fatalf("occupancy.UnmarshalText(%q) = %+v, expected error? %+v", tc.txt, err, tc.wantError)
+			}
+			if got != tc.wantOccupancy {
+				t.Fatalf("occupancy.UnmarshalText(%q) gives %+v, expected %+v", tc.txt, got, tc.wantOccupancy)
+			}
+		})
+	}
+}
+
+func TestOccupancyMarshalUnmarshalText(t *testing.T) {
+	f := func(count, capacity uint16) bool {
+		o := faststatus.Occupancy{Count: int(count), Capacity: int(capacity)}
+		b, err := o.MarshalText()
+		if err != nil {
+			return false
+		}
+		var got faststatus.Occupancy
+		return (&got).UnmarshalText(b) == nil && got == o
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOccupancyMarshalUnmarshalBinary(t *testing.T) {
+	f := func(count, capacity uint32) bool {
+		o := faststatus.Occupancy{Count: int(count), Capacity: int(capacity)}
+		b, err := o.MarshalBinary()
+		if err != nil {
+			return false
+		}
+		var got faststatus.Occupancy
+		return (&got).UnmarshalBinary(b) == nil && got == o
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -5,9 +5,14 @@ package faststatus
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // A Resource represents any resource (a person, a bathroom, a server, etc.)
@@ -16,14 +21,64 @@ type Resource struct {
 	ID     ID
 	Status Status
 	Since  time.Time
+	// Labels carry context about the resource, like "building=hq" or
+	// "type=printer", for selecting resources with a Selector. A Resource
+	// without labels has nil Labels.
+	Labels map[string]string
+	// Message is an optional note about the Status, like "in a 1:1". It
+	// is at most MaxMessageLen bytes of UTF-8, without control characters.
+	Message string
+	// Until is when the Status is expected to change, if known. It must
+	// be after Since.
+	Until time.Time
+	// HLC orders writes to the Resource when it is set, in place of Since,
+	// so that skewed wall clocks cannot reorder them. See Clock.
+	HLC HLC
+}
+
+// MaxMessageLen is the most bytes a Resource Message may hold.
+const MaxMessageLen = 256
+
+// checkMessage returns an error for a Message that cannot be encoded.
+func checkMessage(msg string) error {
+	switch {
+	case len(msg) > MaxMessageLen:
+		return fmt.Errorf("message longer than %d bytes", MaxMessageLen)
+	case !utf8.ValidString(msg):
+		return fmt.Errorf("message is not valid UTF-8")
+	case strings.IndexFunc(msg, unicode.IsControl) >= 0:
+		return fmt.Errorf("message contains a control character")
+	default:
+		return nil
+	}
+}
+
+// checkUntil returns an error for an Until that is set but not after Since.
+func checkUntil(since, until time.Time) error {
+	if !until.IsZero() && !until.After(since) {
+		return fmt.Errorf("until %s is not after since %s", until.Format(time.RFC3339Nano), since.Format(time.RFC3339Nano))
+	}
+	return nil
 }
 
 // NewResource creates a new Resource with a generated ID and otherwise zero-value properties.
+// Should the entropy source fail, the ID is left as the zero value; use
+// NewResourceWith to handle that error.
 func NewResource() Resource {
 	id, _ := NewID()
 	return Resource{ID: id}
 }
 
+// NewResourceWith creates a new Resource with an ID from the given
+// generator and otherwise zero-value properties.
+func NewResourceWith(gen IDGenerator) (Resource, error) {
+	id, err := gen.NewID()
+	if err != nil {
+		return Resource{}, fmt.Errorf("generating ID for new resource: %w", err)
+	}
+	return Resource{ID: id}, nil
+}
+
 // Equal allows quick equality comparison for two resource values.
 // Use this instead of the equality operator because a Resource contains
 // a `time.Time` value, which cannot be compared with confidence.
@@ -31,18 +86,35 @@ func (r Resource) Equal(other Resource) bool {
 	switch {
 	case r.ID != other.ID,
 		r.Status != other.Status,
-		!r.Since.Equal(other.Since):
+		!r.Since.Equal(other.Since),
+		!labelsEqual(r.Labels, other.Labels),
+		r.Message != other.Message,
+		!r.Until.Equal(other.Until),
+		r.HLC != other.HLC:
 		return false
 	default:
 		return true
 	}
 }
 
+// labelsEqual compares labels, with nil equal to empty.
+func labelsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || v != w {
+			return false
+		}
+	}
+	return true
+}
+
 // String will return a single-line representation of a valid resource.
 // In order to optimize for standard streams, the output is as follows:
-//   {{ID}} {{Status}} {{Since}}
+//   {{ID}} {{Status}} {{Since}} {{Until}} {{Message}}
 // Formatted as follows:
-//   01234567-89ab-cdef-0123-456789abcdef busy 2006-01-02T15:04:05Z07:00 My Resource
+//   01234567-89ab-cdef-0123-456789abcdef busy 2006-01-02T15:04:05Z07:00 2006-01-02T15:30:00Z07:00 in a 1:1
 func (r Resource) String() string {
 	txt, err := r.MarshalText()
 	if err != nil {
@@ -53,62 +125,135 @@ func (r Resource) String() string {
 
 // MarshalText encodes a Resource to the text representation. In order to
 // better stream text, the output is as follows:
-//   {{ID}} {{Status}} {{Since}}
+//   {{ID}} {{Status}} {{Since}} {{Until}} {{Message}}
 // Formatted as follows:
-//   01234567-89ab-cdef-0123-456789abcdef busy 2006-01-02T15:04:05Z07:00 My Resource
-// An invalid Status (out of range, etc.) will result in an error.
+//   01234567-89ab-cdef-0123-456789abcdef busy 2006-01-02T15:04:05Z07:00 2006-01-02T15:30:00Z07:00 in a 1:1
+// Until and Message are left off when neither is set, and a zero-value
+// Until is "-" when there is a Message. Labels are not part of the text
+// representation. An invalid Status (out of range, etc.), Message, or
+// Until will result in an error.
 func (r Resource) MarshalText() ([]byte, error) {
-	txt := make([]byte, 0, 128)
+	return r.AppendText(make([]byte, 0, 128))
+}
 
-	id, err := r.ID.MarshalText()
+// AppendText appends the text representation of a Resource to dst,
+// implementing the encoding.TextAppender interface. It does not allocate
+// beyond growing dst.
+func (r Resource) AppendText(dst []byte) ([]byte, error) {
+	txt, err := r.ID.AppendText(dst)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling text for ID: %+v", err)
+		return nil, fmt.Errorf("marshaling text for ID: %w", err)
 	}
-	txt = append(txt, id...)
 
 	txt = append(txt, ' ')
-	status, err := r.Status.MarshalText()
+	txt, err = r.Status.AppendText(txt)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling Status to text: %+v", err)
+		return nil, fmt.Errorf("marshaling Status to text: %w", err)
 	}
-	txt = append(txt, status...)
 
 	txt = append(txt, ' ')
-	since, err := r.Since.MarshalText()
-	if err != nil {
-		return nil, fmt.Errorf("marshaling Since to text: %+v", err)
+	if y := r.Since.Year(); y < 0 || y >= 10000 {
+		return nil, fmt.Errorf("marshaling Since to text: year outside of range [0,9999]")
+	}
+	txt = r.Since.AppendFormat(txt, time.RFC3339Nano)
+
+	if r.Message == "" && r.Until.IsZero() {
+		return txt, nil
+	}
+
+	txt = append(txt, ' ')
+	if err := checkUntil(r.Since, r.Until); err != nil {
+		return nil, fmt.Errorf("marshaling Until to text: %w", err)
+	}
+	if r.Until.IsZero() {
+		txt = append(txt, '-')
+	} else {
+		if y := r.Until.Year(); y < 0 || y >= 10000 {
+			return nil, fmt.Errorf("marshaling Until to text: year outside of range [0,9999]")
+		}
+		txt = r.Until.AppendFormat(txt, time.RFC3339Nano)
+	}
+
+	if r.Message != "" {
+		if err := checkMessage(r.Message); err != nil {
+			return nil, fmt.Errorf("marshaling Message to text: %w", err)
+		}
+		txt = append(append(txt, ' '), r.Message...)
 	}
-	txt = append(txt, since...)
 
 	return txt, nil
 }
 
 // UnmarshalText decodes a Resource from a line of text. This matches the
-// output of the `MarshalText` method.
+// output of the `MarshalText` method. As in the original format, which had
+// no Until or Message, any other text after Since, such as a name, is
+// ignored: text is only read as Until and Message when its first word is
+// "-" or a time. Parsing does not allocate, except for the Message and for the
+// time zone of a Since or Until that is neither UTC nor local.
 func (r *Resource) UnmarshalText(txt []byte) error {
-	elements := bytes.Split(txt, []byte(" "))
-
-	if len(elements) < 3 {
-		return fmt.Errorf("invalid resource text")
+	var elements [3][]byte
+	for i := range elements {
+		if i > 0 {
+			if len(txt) == 0 {
+				return fmt.Errorf("invalid resource text")
+			}
+			txt = txt[1:]
+		}
+		end := bytes.IndexByte(txt, ' ')
+		if end < 0 {
+			end = len(txt)
+		}
+		elements[i], txt = txt[:end], txt[end:]
 	}
 
 	tmp := Resource{}
 
 	if err := (&tmp.ID).UnmarshalText(elements[0]); err != nil {
-		return fmt.Errorf("parsing ID from text: %+v", err)
+		return fmt.Errorf("parsing ID from text: %w", err)
 	}
 
 	if err := (&tmp.Status).UnmarshalText(elements[1]); err != nil {
-		return fmt.Errorf("parsing Status from text: %+v", err)
+		return fmt.Errorf("parsing Status from text: %w", err)
 	}
 
 	if err := (&tmp.Since).UnmarshalText(elements[2]); err != nil {
-		return fmt.Errorf("parsing Since from text: %+v", err)
+		return fmt.Errorf("parsing Since from text: %w", err)
 	}
 	if tmp.Since.IsZero() {
 		tmp.Since = time.Time{}
 	}
 
+	if len(txt) > 0 {
+		txt = txt[1:]
+		end := bytes.IndexByte(txt, ' ')
+		if end < 0 {
+			end = len(txt)
+		}
+		until, message := txt[:end], txt[end:]
+		legacy := false
+		switch {
+		case len(until) == 1 && until[0] == '-':
+		case (&tmp.Until).UnmarshalText(until) == nil:
+			if tmp.Until.IsZero() {
+				tmp.Until = time.Time{}
+			}
+		default:
+			// the original format allowed any text after Since
+			legacy = true
+		}
+		if !legacy {
+			if err := checkUntil(tmp.Since, tmp.Until); err != nil {
+				return fmt.Errorf("parsing Until from text: %w", err)
+			}
+			if len(message) > 0 {
+				tmp.Message = string(message[1:])
+				if err := checkMessage(tmp.Message); err != nil {
+					return fmt.Errorf("parsing Message from text: %w", err)
+				}
+			}
+		}
+	}
+
 	*r = tmp
 
 	return nil
@@ -116,16 +261,35 @@ func (r *Resource) UnmarshalText(txt []byte) error {
 
 // MarshalJSON will return simple a simple json structure for a resource.
 // Will not accept any Status that is out of range; see Status documentation
-// for more information.
+// for more information. Labels, Message, Until and HLC are omitted when not
+// set.
 func (r Resource) MarshalJSON() ([]byte, error) {
+	if err := checkMessage(r.Message); err != nil {
+		return nil, fmt.Errorf("marshaling Message to json: %w", err)
+	}
+	if err := checkUntil(r.Since, r.Until); err != nil {
+		return nil, fmt.Errorf("marshaling Until to json: %w", err)
+	}
 	tmpResource := struct {
-		ID     ID        `json:"id"`
-		Status Status    `json:"status"`
-		Since  time.Time `json:"since"`
+		ID      ID                `json:"id"`
+		Status  Status            `json:"status"`
+		Since   time.Time         `json:"since"`
+		Labels  map[string]string `json:"labels,omitempty"`
+		Message string            `json:"message,omitempty"`
+		Until   *time.Time        `json:"until,omitempty"`
+		HLC     *HLC              `json:"hlc,omitempty"`
 	}{
-		r.ID,
-		r.Status,
-		r.Since,
+		ID:      r.ID,
+		Status:  r.Status,
+		Since:   r.Since,
+		Labels:  r.Labels,
+		Message: r.Message,
+	}
+	if !r.Until.IsZero() {
+		tmpResource.Until = &r.Until
+	}
+	if !r.HLC.IsZero() {
+		tmpResource.HLC = &r.HLC
 	}
 	return json.Marshal(tmpResource)
 }
@@ -135,13 +299,23 @@ func (r Resource) MarshalJSON() ([]byte, error) {
 // already assigned to the Resource.
 func (r *Resource) UnmarshalJSON(raw []byte) error {
 	tmp := new(struct {
-		ID     ID
-		Status Status
-		Since  time.Time
+		ID      ID
+		Status  Status
+		Since   time.Time
+		Labels  map[string]string
+		Message string
+		Until   time.Time
+		HLC     HLC
 	})
 	if err := json.Unmarshal(raw, tmp); err != nil {
 		return err
 	}
+	if err := checkMessage(tmp.Message); err != nil {
+		return fmt.Errorf("parsing Message from json: %w", err)
+	}
+	if err := checkUntil(tmp.Since, tmp.Until); err != nil {
+		return fmt.Errorf("parsing Until from json: %w", err)
+	}
 
 	r.ID = tmp.ID
 	r.Status = tmp.Status
@@ -149,76 +323,336 @@ func (r *Resource) UnmarshalJSON(raw []byte) error {
 	if r.Since.IsZero() {
 		r.Since = time.Time{}
 	}
+	r.Labels = tmp.Labels
+	if len(r.Labels) == 0 {
+		r.Labels = nil
+	}
+	r.Message = tmp.Message
+	r.Until = tmp.Until
+	if r.Until.IsZero() {
+		r.Until = time.Time{}
+	}
+	r.HLC = tmp.HLC
 	return nil
 }
 
-const binaryVersion = 0x00
+// binaryVersion 0x01 added the Unknown Status, which is not valid in
+// version 0x00. Version 0x02 added the extension section, with Labels.
+// Version 0x03 added Message and Until. The HLC is an extension field
+// that older readers skip, so it needs no version of its own.
+const binaryVersion = 0x03
+
+// Tags of the fields in the extension section of the binary format.
+const (
+	// extLabels holds the Labels: each key and then its value, in order
+	// of the keys, each as a varint length followed by that many bytes.
+	extLabels = 1
+	// extMessage holds the Message as UTF-8, from version 0x03.
+	extMessage = 2
+	// extUntil holds Until in the same 15 byte form as Since, from
+	// version 0x03.
+	extUntil = 3
+	// extHLC holds the HLC as 16 bytes, in any version with an extension
+	// section.
+	extHLC = 4
+)
 
 // MagicBytes are the first two bytes of the portable binary representation of a Resource.
 var MagicBytes = [2]byte{0x90, 0xe9}
 
 // MarshalBinary returns a portable binary version of a Resource.
 // The resulting binary must contain a header with MagicBytes (0x09 0xe9),
-// a version byte, and a single empty buffer byte.
+// a version byte, and a single empty buffer byte. The header is followed
+// by 32 bytes holding the ID, Status and Since, and then the extension
+// section: any number of fields, each a varint tag, a varint length, and
+// that many bytes of value. Decoders skip any tag they do not know, so new
+// fields can be added without breaking older readers. The version byte is
+// the lowest version that can represent the Resource, so readers that have
+// not yet been upgraded can decode any Resource without the newer fields.
 func (r Resource) MarshalBinary() ([]byte, error) {
-	b := make([]byte, 4+32)
+	return r.MarshalBinaryVersion(r.minBinaryVersion())
+}
 
-	if n := copy(b[0:2], MagicBytes[:]); n != 2 {
-		return nil, fmt.Errorf("unable to copy correct magic bytes")
+// AppendBinary appends the portable binary version of a Resource to dst,
+// implementing the encoding.BinaryAppender interface. It does not allocate
+// beyond growing dst.
+func (r Resource) AppendBinary(dst []byte) ([]byte, error) {
+	return r.appendBinaryVersion(dst, r.minBinaryVersion())
+}
+
+// minBinaryVersion returns the lowest version of the binary format that
+// can represent the Resource.
+func (r Resource) minBinaryVersion() byte {
+	switch {
+	case r.Message != "" || !r.Until.IsZero():
+		return 0x03
+	case len(r.Labels) > 0 || !r.HLC.IsZero():
+		return 0x02
+	case r.Status == Unknown:
+		return 0x01
+	default:
+		return 0x00
 	}
-	b[2] = binaryVersion
+}
 
-	id, err := r.ID.MarshalBinary()
+// MarshalBinaryVersion returns a portable binary version of a Resource in
+// the form of an earlier version of the binary format, for readers that
+// have not yet been upgraded. Versions before 0x03 cannot represent a
+// Message or Until. Versions before 0x02 are always 36 bytes, without an
+// extension section, so cannot represent Labels or an HLC, and version
+// 0x00 cannot represent the Unknown Status.
+func (r Resource) MarshalBinaryVersion(v byte) ([]byte, error) {
+	return r.appendBinaryVersion(make([]byte, 0, 4+32), v)
+}
+
+func (r Resource) appendBinaryVersion(dst []byte, v byte) ([]byte, error) {
+	switch {
+	case v > binaryVersion:
+		return nil, fmt.Errorf("unexpected version number for binary format")
+	case v < 0x01 && r.Status == Unknown:
+		return nil, fmt.Errorf("marshaling Status to binary: %w", errOutOfRange)
+	case v < 0x02 && len(r.Labels) > 0:
+		return nil, fmt.Errorf("marshaling Labels to binary: version %d has no extension section", v)
+	case v < 0x03 && r.Message != "":
+		return nil, fmt.Errorf("marshaling Message to binary: not supported by version %d", v)
+	case v < 0x03 && !r.Until.IsZero():
+		return nil, fmt.Errorf("marshaling Until to binary: not supported by version %d", v)
+	case v < 0x02 && !r.HLC.IsZero():
+		return nil, fmt.Errorf("marshaling HLC to binary: version %d has no extension section", v)
+	default:
+	}
+	if err := checkMessage(r.Message); err != nil {
+		return nil, fmt.Errorf("marshaling Message to binary: %w", err)
+	}
+	if err := checkUntil(r.Since, r.Until); err != nil {
+		return nil, fmt.Errorf("marshaling Until to binary: %w", err)
+	}
+
+	b := append(dst, MagicBytes[0], MagicBytes[1], v, 0)
+
+	b, err := r.ID.AppendBinary(b)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling ID to binary: %+v", err)
+		return nil, fmt.Errorf("marshaling ID to binary: %w", err)
 	}
-	copy(b[4:20], id)
 
-	status, err := r.Status.MarshalBinary()
+	b, err = r.Status.AppendBinary(b)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling Status to binary: %+v", err)
+		return nil, fmt.Errorf("marshaling Status to binary: %w", err)
 	}
-	copy(b[20:21], status)
 
-	since, err := r.Since.MarshalBinary()
+	b, err = appendTimeBinary(b, r.Since)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling Since to binary: %+v", err)
+		return nil, fmt.Errorf("marshaling Since to binary: %w", err)
+	}
+
+	if len(r.Labels) > 0 {
+		b = appendExtension(b, extLabels, appendLabels(nil, r.Labels))
+	}
+
+	if r.Message != "" {
+		b = appendExtension(b, extMessage, []byte(r.Message))
+	}
+
+	if !r.Until.IsZero() {
+		var until [15]byte
+		value, err := appendTimeBinary(until[:0], r.Until)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling Until to binary: %w", err)
+		}
+		b = appendExtension(b, extUntil, value)
+	}
+
+	if !r.HLC.IsZero() {
+		var hlc [16]byte
+		value, _ := r.HLC.AppendBinary(hlc[:0])
+		b = appendExtension(b, extHLC, value)
 	}
-	copy(b[21:36], since)
 
 	return b, nil
 }
 
+// appendExtension appends a field of the extension section to dst.
+func appendExtension(dst []byte, tag uint64, value []byte) []byte {
+	dst = binary.AppendUvarint(dst, tag)
+	dst = binary.AppendUvarint(dst, uint64(len(value)))
+	return append(dst, value...)
+}
+
+// appendLabels appends the labels to dst in the form of the extLabels
+// field, in order of the keys so that equal labels encode identically.
+func appendLabels(dst []byte, labels map[string]string) []byte {
+	for _, k := range sortedKeys(labels) {
+		dst = binary.AppendUvarint(dst, uint64(len(k)))
+		dst = append(dst, k...)
+		dst = binary.AppendUvarint(dst, uint64(len(labels[k])))
+		dst = append(dst, labels[k]...)
+	}
+	return dst
+}
+
+func sortedKeys(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseLabels parses the value of the extLabels field.
+func parseLabels(b []byte) (map[string]string, error) {
+	next := func() (string, error) {
+		n, size := binary.Uvarint(b)
+		if size <= 0 || uint64(len(b)-size) < n {
+			return "", fmt.Errorf("invalid label length")
+		}
+		s := string(b[size : size+int(n)])
+		b = b[size+int(n):]
+		return s, nil
+	}
+	labels := make(map[string]string)
+	for len(b) > 0 {
+		k, err := next()
+		if err != nil {
+			return nil, err
+		}
+		v, err := next()
+		if err != nil {
+			return nil, err
+		}
+		labels[k] = v
+	}
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	return labels, nil
+}
+
+// appendTimeBinary appends the 15 byte output of the time's MarshalBinary
+// method to dst, without the allocation that method makes. Offsets that
+// are not whole minutes need a longer form, and are not supported.
+func appendTimeBinary(dst []byte, t time.Time) ([]byte, error) {
+	// seconds from January 1, year 1 to the Unix epoch
+	const unixToInternal = (1969*365 + 1969/4 - 1969/100 + 1969/400) * 24 * 60 * 60
+
+	offsetMin := int16(-1) // UTC
+	if t.Location() != time.UTC {
+		_, offset := t.Zone()
+		if offset%60 != 0 || offset/60 < -32768 || offset/60 == -1 || offset/60 > 32767 {
+			return nil, fmt.Errorf("unsupported zone offset %ds", offset)
+		}
+		offsetMin = int16(offset / 60)
+	}
+
+	var b [15]byte
+	b[0] = 1 // version of the time binary format
+	binary.BigEndian.PutUint64(b[1:9], uint64(t.Unix()+unixToInternal))
+	binary.BigEndian.PutUint32(b[9:13], uint32(t.Nanosecond()))
+	binary.BigEndian.PutUint16(b[13:15], uint16(offsetMin))
+	return append(dst, b[:]...), nil
+}
+
 // UnmarshalBinary replaces a Resource with the Resource represented
 // by the binary input. The input binary must match the form of the
-// MarshalBinary method.
+// MarshalBinary method, or of any earlier version of the format.
 func (r *Resource) UnmarshalBinary(b []byte) error {
 	switch {
 	case len(b) < 36:
 		return fmt.Errorf("input binary data too short")
-	case len(b) > 36:
-		return fmt.Errorf("input binay data too long")
 	case !bytes.Equal(b[0:2], MagicBytes[:]):
 		return fmt.Errorf("unexpected magic bytes")
 	case b[2] > binaryVersion:
 		return fmt.Errorf("unexpected version number for binary format")
+	case b[2] < 0x02 && len(b) > 36:
+		return fmt.Errorf("input binay data too long")
 	default:
 	}
 
 	tmp := Resource{}
 
 	if err := (&tmp.ID).UnmarshalBinary(b[4:20]); err != nil {
-		return fmt.Errorf("parsing ID from binary: %+v", err)
+		return fmt.Errorf("parsing ID from binary: %w", err)
 	}
 
 	if err := (&tmp.Status).UnmarshalBinary(b[20:21]); err != nil {
-		return fmt.Errorf("parsing Status from binary: %+v", err)
+		return fmt.Errorf("parsing Status from binary: %w", err)
+	}
+	if b[2] < 0x01 && tmp.Status == Unknown {
+		return fmt.Errorf("parsing Status from binary: %w", errOutOfRange)
 	}
 
 	if err := (&tmp.Since).UnmarshalBinary(b[21:36]); err != nil {
-		return fmt.Errorf("parsing Since from binary: %+v", err)
+		return fmt.Errorf("parsing Since from binary: %w", err)
+	}
+
+	// Unknown tags are skipped, for fields added by newer versions.
+	err := readExtensions(b[36:], func(tag uint64, value []byte) error {
+		switch tag {
+		case extLabels:
+			labels, err := parseLabels(value)
+			if err != nil {
+				return fmt.Errorf("parsing Labels: %w", err)
+			}
+			tmp.Labels = labels
+		case extMessage:
+			if b[2] < 0x03 {
+				return nil
+			}
+			tmp.Message = string(value)
+			if err := checkMessage(tmp.Message); err != nil {
+				return fmt.Errorf("parsing Message: %w", err)
+			}
+		case extUntil:
+			if b[2] < 0x03 {
+				return nil
+			}
+			if len(value) != 15 {
+				return fmt.Errorf("parsing Until: unexpected length %d", len(value))
+			}
+			if err := (&tmp.Until).UnmarshalBinary(value); err != nil {
+				return fmt.Errorf("parsing Until: %w", err)
+			}
+		case extHLC:
+			if err := (&tmp.HLC).UnmarshalBinary(value); err != nil {
+				return fmt.Errorf("parsing HLC: %w", err)
+			}
+		default:
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("parsing extensions from binary: %w", err)
+	}
+	if err := checkUntil(tmp.Since, tmp.Until); err != nil {
+		return fmt.Errorf("parsing Until from binary: %w", err)
 	}
 
 	*r = tmp
 	return nil
 }
+
+// readExtensions calls fn with the tag and value of each field in the
+// extension section of the binary format. Tags start at 1, so that stray
+// zero bytes are not mistaken for a field.
+func readExtensions(b []byte, fn func(tag uint64, value []byte) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 || tag == 0 {
+			return fmt.Errorf("invalid extension tag")
+		}
+		b = b[n:]
+		length, n := binary.Uvarint(b)
+		if n <= 0 {
+			return fmt.Errorf("invalid length for extension %d", tag)
+		}
+		b = b[n:]
+		if uint64(len(b)) < length {
+			return fmt.Errorf("extension %d too short", tag)
+		}
+		if err := fn(tag, b[:length]); err != nil {
+			return fmt.Errorf("extension %d: %w", tag, err)
+		}
+		b = b[length:]
+	}
+	return nil
+}
@@ -6,10 +6,13 @@ package faststatus_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"testing/quick"
 	"time"
+	"unicode"
 
 	"github.com/lazyengineering/faststatus"
 )
@@ -61,7 +64,7 @@ func TestResourceString(t *testing.T) {
 			"",
 			faststatus.Resource{
 				ID:     faststatus.ID{0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45},
-				Status: faststatus.Occupied + 1,
+				Status: faststatus.Unknown + 1,
 				Since: func() time.Time {
 					tt, _ := time.Parse(time.RFC3339, "2016-05-12T15:43:00-07:00")
 					return tt
@@ -130,7 +133,7 @@ func TestResourceMarshalText(t *testing.T) {
 		{"Out of Range",
 			faststatus.Resource{
 				ID:     faststatus.ID{0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45},
-				Status: faststatus.Occupied + 1,
+				Status: faststatus.Unknown + 1,
 				Since: func() time.Time {
 					tt, _ := time.Parse(time.RFC3339, "2016-05-12T15:43:00-07:00")
 					return tt
@@ -355,7 +358,7 @@ func TestResourceMarshalJSON(t *testing.T) {
 		{"Out of Range",
 			faststatus.Resource{
 				ID:     faststatus.ID{0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45},
-				Status: faststatus.Occupied + 1,
+				Status: faststatus.Unknown + 1,
 				Since: func() time.Time {
 					tt, _ := time.Parse(time.RFC3339, "2016-05-12T16:30:00-07:00")
 					return tt
@@ -490,7 +493,7 @@ func TestResourceUnmarshalJSON(t *testing.T) {
 		{"Out of Range",
 			[]byte(`{
 				"id":"6789abcd-ef01-2345-6789-abcdef012345",
-				"status":"3",
+				"status":"4",
 				"since":"2016-05-12T16:30:00-07:00"
 			}`),
 			faststatus.Resource{},
@@ -657,8 +660,14 @@ func TestResourceMarshalBinaryHasMagicBytes(t *testing.T) {
 
 func TestResourceMarshalBinaryVersionByte(t *testing.T) {
 	f := func(r faststatus.Resource) bool {
+		// without Labels, Message, Until or HLC, only the Unknown Status
+		// needs a version after 0x00
+		var want byte
+		if r.Status == faststatus.Unknown {
+			want = 0x01
+		}
 		b, _ := r.MarshalBinary()
-		return len(b) >= 3 && b[2] == faststatus.BinaryVersion
+		return len(b) >= 3 && b[2] == want
 	}
 	if err := quick.Check(f, nil); err != nil {
 		t.Fatal(err)
@@ -751,6 +760,20 @@ func TestResourceUnmarshalBinaryBadData(t *testing.T) {
 				return b
 			}(),
 		},
+		{"unknown status in version 0",
+			func() []byte {
+				b, _ := faststatus.Resource{
+					ID:     faststatus.ID{0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01},
+					Status: faststatus.Unknown,
+					Since: func() time.Time {
+						tt, _ := time.Parse(time.RFC3339, "2016-05-12T16:27:00-07:00")
+						return tt
+					}(),
+				}.MarshalBinary()
+				b[2] = 0x00
+				return b
+			}(),
+		},
 		{"bad time bytes",
 			func() []byte {
 				b, _ := faststatus.Resource{
@@ -800,6 +823,569 @@ func TestResourceMarshalUnmarshalBinaryQuick(t *testing.T) {
 	}
 }
 
+func TestResourceUnknownRoundTrips(t *testing.T) {
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01},
+		Status: faststatus.Unknown,
+		Since: func() time.Time {
+			tt, _ := time.Parse(time.RFC3339, "2016-05-12T16:27:00-07:00")
+			return tt
+		}(),
+	}
+
+	txt, err := r.MarshalText()
+	if err != nil {
+		t.Fatalf("%+v.MarshalText() = %+v, expected no error", r, err)
+	}
+	if want := "23456789-abcd-ef01-2345-6789abcdef01 unknown 2016-05-12T16:27:00-07:00"; string(txt) != want {
+		t.Fatalf("%+v.MarshalText() = %q, expected %q", r, txt, want)
+	}
+	var fromText faststatus.Resource
+	if err := (&fromText).UnmarshalText(txt); err != nil || !fromText.Equal(r) {
+		t.Fatalf("UnmarshalText(%q) = %+v, %+v, expected %+v", txt, fromText, err, r)
+	}
+
+	js, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("json.Marshal(%+v) = %+v, expected no error", r, err)
+	}
+	var fromJSON faststatus.Resource
+	if err := json.Unmarshal(js, &fromJSON); err != nil || !fromJSON.Equal(r) {
+		t.Fatalf("json.Unmarshal(%s) = %+v, %+v, expected %+v", js, fromJSON, err, r)
+	}
+
+	b, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v.MarshalBinary() = %+v, expected no error", r, err)
+	}
+	var fromBinary faststatus.Resource
+	if err := (&fromBinary).UnmarshalBinary(b); err != nil || !fromBinary.Equal(r) {
+		t.Fatalf("UnmarshalBinary(%x) = %+v, %+v, expected %+v", b, fromBinary, err, r)
+	}
+}
+
+func TestResourceUnmarshalBinaryVersion0(t *testing.T) {
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01},
+		Status: faststatus.Occupied,
+		Since: func() time.Time {
+			tt, _ := time.Parse(time.RFC3339, "2016-05-12T16:27:00-07:00")
+			return tt
+		}(),
+	}
+	b, _ := r.MarshalBinary()
+	b[2] = 0x00
+	var got faststatus.Resource
+	if err := (&got).UnmarshalBinary(b); err != nil || !got.Equal(r) {
+		t.Fatalf("UnmarshalBinary(%x) = %+v, %+v, expected %+v", b, got, err, r)
+	}
+}
+
+func TestResourceMarshalBinaryVersion(t *testing.T) {
+	for _, v := range []byte{0x00, 0x01, faststatus.BinaryVersion} {
+		v := v
+		t.Run(fmt.Sprintf("version %d", v), func(t *testing.T) {
+			f := func(r faststatus.Resource) bool {
+				b, err := r.MarshalBinaryVersion(v)
+				if err != nil || len(b) != 36 || b[2] != v {
+					return false
+				}
+				var got faststatus.Resource
+				return (&got).UnmarshalBinary(b) == nil && got.Equal(r)
+			}
+			if err := quick.Check(f, nil); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	unknown := faststatus.Resource{Status: faststatus.Unknown}
+	if _, err := unknown.MarshalBinaryVersion(0x00); err == nil {
+		t.Fatalf("MarshalBinaryVersion(0) of unknown status = <nil>, expected error")
+	}
+	if _, err := unknown.MarshalBinaryVersion(0x01); err != nil {
+		t.Fatalf("MarshalBinaryVersion(1) of unknown status = %+v, expected no error", err)
+	}
+	if _, err := unknown.MarshalBinaryVersion(faststatus.BinaryVersion + 1); err == nil {
+		t.Fatalf("MarshalBinaryVersion(%d) = <nil>, expected error", faststatus.BinaryVersion+1)
+	}
+}
+
+func TestResourceUnmarshalBinaryExtensions(t *testing.T) {
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01},
+		Status: faststatus.Busy,
+		Since: func() time.Time {
+			tt, _ := time.Parse(time.RFC3339, "2016-05-12T16:27:00-07:00")
+			return tt
+		}(),
+	}
+	testCases := []struct {
+		name       string
+		version    byte
+		extensions []byte
+		wantError  bool
+	}{
+		{"none", faststatus.BinaryVersion, nil, false},
+		{"unknown tags", faststatus.BinaryVersion, []byte{0x7f, 0x02, 0xaa, 0xbb, 0x80, 0x01, 0x00}, false},
+		{"long value", faststatus.BinaryVersion, append([]byte{0x7e, 0x80, 0x02}, make([]byte, 256)...), false},
+		{"zero tag", faststatus.BinaryVersion, []byte{0x00, 0x00}, true},
+		{"missing length", faststatus.BinaryVersion, []byte{0x7f}, true},
+		{"truncated value", faststatus.BinaryVersion, []byte{0x7f, 0x03, 0xaa, 0xbb}, true},
+		{"extensions in version 1", 0x01, []byte{0x7f, 0x00}, true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b, _ := r.MarshalBinaryVersion(tc.version)
+			b = append(b, tc.extensions...)
+			var got faststatus.Resource
+			err := (&got).UnmarshalBinary(b)
+			if (err != nil) != tc.wantError {
+				t.Fatalf("UnmarshalBinary(%x) = %+v, expected error? %t", b, err, tc.wantError)
+			}
+			if !tc.wantError && !got.Equal(r) {
+				t.Fatalf("UnmarshalBinary(%x) = %+v, expected %+v", b, got, r)
+			}
+		})
+	}
+}
+
+func TestResourceLabelsRoundTrip(t *testing.T) {
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01},
+		Status: faststatus.Busy,
+		Since:  time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC),
+		Labels: map[string]string{
+			"building": "hq",
+			"floor":    "3",
+			"note":     "",
+			"long":     strings.Repeat("x", 300),
+		},
+	}
+	codecs := []struct {
+		name      string
+		marshal   func(faststatus.Resource) ([]byte, error)
+		unmarshal func(*faststatus.Resource, []byte) error
+	}{
+		{"json", func(r faststatus.Resource) ([]byte, error) { return json.Marshal(r) }, func(r *faststatus.Resource, b []byte) error { return json.Unmarshal(b, r) }},
+		{"binary", faststatus.Resource.MarshalBinary, (*faststatus.Resource).UnmarshalBinary},
+		{"cbor", faststatus.Resource.MarshalCBOR, (*faststatus.Resource).UnmarshalCBOR},
+		{"msgpack", faststatus.Resource.MarshalMsgpack, (*faststatus.Resource).UnmarshalMsgpack},
+	}
+	for _, c := range codecs {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			b, err := c.marshal(r)
+			if err != nil {
+				t.Fatalf("marshaling %+v: %+v, expected no error", r, err)
+			}
+			again, _ := c.marshal(r)
+			if !bytes.Equal(b, again) {
+				t.Fatalf("marshaling %+v twice: %x then %x, expected the same", r, b, again)
+			}
+			var got faststatus.Resource
+			if err := c.unmarshal(&got, b); err != nil || !reflect.DeepEqual(got.Labels, r.Labels) {
+				t.Fatalf("unmarshaling %x = %+v, %+v, expected %+v", b, got, err, r)
+			}
+
+			r := r
+			r.Labels = map[string]string{}
+			b, _ = c.marshal(r)
+			if err := c.unmarshal(&got, b); err != nil || got.Labels != nil {
+				t.Fatalf("unmarshaling %x = %+v, %+v, expected nil Labels", b, got, err)
+			}
+		})
+	}
+}
+
+func TestResourceLabelsText(t *testing.T) {
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01},
+		Status: faststatus.Busy,
+		Since:  time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC),
+		Labels: map[string]string{"floor": "3"},
+	}
+	txt, err := r.MarshalText()
+	if err != nil {
+		t.Fatalf("%+v.MarshalText() = %+v, expected no error", r, err)
+	}
+	if want := "23456789-abcd-ef01-2345-6789abcdef01 busy 2017-03-14T15:09:26Z"; string(txt) != want {
+		t.Fatalf("%+v.MarshalText() = %q, expected %q", r, txt, want)
+	}
+}
+
+func TestResourceEqualLabels(t *testing.T) {
+	testCases := []struct {
+		name string
+		a, b map[string]string
+		want bool
+	}{
+		{"nil", nil, nil, true},
+		{"nil and empty", nil, map[string]string{}, true},
+		{"same", map[string]string{"floor": "3"}, map[string]string{"floor": "3"}, true},
+		{"different value", map[string]string{"floor": "3"}, map[string]string{"floor": "4"}, false},
+		{"different key", map[string]string{"floor": "3"}, map[string]string{"level": "3"}, false},
+		{"empty value and missing", map[string]string{"note": ""}, map[string]string{"floor": ""}, false},
+		{"subset", map[string]string{"floor": "3"}, map[string]string{"floor": "3", "type": "room"}, false},
+	}
+	for _, tc := range testCases {
+		a, b := faststatus.Resource{Labels: tc.a}, faststatus.Resource{Labels: tc.b}
+		if got := a.Equal(b); got != tc.want {
+			t.Errorf("%s: Equal() = %t, expected %t", tc.name, got, tc.want)
+		}
+		if got := b.Equal(a); got != tc.want {
+			t.Errorf("%s: reversed Equal() = %t, expected %t", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestResourceLabelsBinary(t *testing.T) {
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01},
+		Status: faststatus.Busy,
+		Since:  time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC),
+		Labels: map[string]string{"floor": "3", "b": "hq"},
+	}
+	b, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v.MarshalBinary() = %+v, expected no error", r, err)
+	}
+	want := []byte{0x01, 0x0d, 0x01, 'b', 0x02, 'h', 'q', 0x05, 'f', 'l', 'o', 'o', 'r', 0x01, '3'}
+	if !bytes.Equal(b[36:], want) {
+		t.Fatalf("%+v.MarshalBinary() extensions = %x, expected %x", r, b[36:], want)
+	}
+	if _, err := r.MarshalBinaryVersion(0x01); err == nil {
+		t.Fatalf("MarshalBinaryVersion(0x01) with labels = <nil>, expected error")
+	}
+
+	for _, bad := range [][]byte{
+		{0x01, 0x02, 0x05, 'f'},
+		{0x01, 0x03, 0x01, 'b', 0x02},
+		{0x01, 0x02, 0x01, 'b'},
+	} {
+		b, _ := faststatus.Resource{ID: r.ID, Since: r.Since}.MarshalBinary()
+		b = append(b, bad...)
+		var got faststatus.Resource
+		if err := (&got).UnmarshalBinary(b); err == nil {
+			t.Fatalf("UnmarshalBinary(%x) = <nil>, expected error", b)
+		}
+	}
+}
+
+func TestResourceMessageUntilText(t *testing.T) {
+	id := faststatus.ID{0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01}
+	since := time.Date(2017, 3, 14, 15, 9, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		resource faststatus.Resource
+		txt      string
+	}{
+		{"neither",
+			faststatus.Resource{ID: id, Status: faststatus.Busy, Since: since},
+			"23456789-abcd-ef01-2345-6789abcdef01 busy 2017-03-14T15:09:00Z",
+		},
+		{"until",
+			faststatus.Resource{ID: id, Status: faststatus.Busy, Since: since, Until: since.Add(21 * time.Minute)},
+			"23456789-abcd-ef01-2345-6789abcdef01 busy 2017-03-14T15:09:00Z 2017-03-14T15:30:00Z",
+		},
+		{"message",
+			faststatus.Resource{ID: id, Status: faststatus.Busy, Since: since, Message: "in a 1:1"},
+			"23456789-abcd-ef01-2345-6789abcdef01 busy 2017-03-14T15:09:00Z - in a 1:1",
+		},
+		{"both",
+			faststatus.Resource{ID: id, Status: faststatus.Busy, Since: since, Until: since.Add(21 * time.Minute), Message: "in a 1:1, back at 15:30"},
+			"23456789-abcd-ef01-2345-6789abcdef01 busy 2017-03-14T15:09:00Z 2017-03-14T15:30:00Z in a 1:1, back at 15:30",
+		},
+		{"message with extra spaces",
+			faststatus.Resource{ID: id, Status: faststatus.Busy, Since: since, Message: " away  "},
+			"23456789-abcd-ef01-2345-6789abcdef01 busy 2017-03-14T15:09:00Z -  away  ",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			txt, err := tc.resource.MarshalText()
+			if err != nil || string(txt) != tc.txt {
+				t.Fatalf("%+v.MarshalText() = %q, %+v, expected %q", tc.resource, txt, err, tc.txt)
+			}
+			var got faststatus.Resource
+			if err := (&got).UnmarshalText([]byte(tc.txt)); err != nil || !reflect.DeepEqual(got, tc.resource) {
+				t.Fatalf("UnmarshalText(%q) = %+v, %+v, expected %+v", tc.txt, got, err, tc.resource)
+			}
+		})
+	}
+}
+
+func TestResourceUnmarshalTextLegacyTrailer(t *testing.T) {
+	want := faststatus.Resource{
+		ID:     faststatus.ID{0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01},
+		Status: faststatus.Busy,
+		Since:  time.Date(2017, 3, 14, 15, 9, 0, 0, time.UTC),
+	}
+	for _, txt := range []string{
+		"23456789-abcd-ef01-2345-6789abcdef01 busy 2017-03-14T15:09:00Z ",
+		"23456789-abcd-ef01-2345-6789abcdef01 busy 2017-03-14T15:09:00Z  message",
+		"23456789-abcd-ef01-2345-6789abcdef01 busy 2017-03-14T15:09:00Z My Resource",
+		"23456789-abcd-ef01-2345-6789abcdef01 busy 2017-03-14T15:09:00Z later",
+		"23456789-abcd-ef01-2345-6789abcdef01 busy 2017-03-14T15:09:00Z 3rd floor",
+	} {
+		var got faststatus.Resource
+		if err := (&got).UnmarshalText([]byte(txt)); err != nil || !got.Equal(want) {
+			t.Errorf("UnmarshalText(%q) = %+v, %+v, expected %+v", txt, got, err, want)
+		}
+	}
+}
+
+func TestResourceMessageUntilTextBadData(t *testing.T) {
+	for _, txt := range []string{
+		"23456789-abcd-ef01-2345-6789abcdef01 busy 2017-03-14T15:09:00Z 2017-03-14T15:09:00Z",
+		"23456789-abcd-ef01-2345-6789abcdef01 busy 2017-03-14T15:09:00Z 2017-03-14T15:00:00Z",
+		"23456789-abcd-ef01-2345-6789abcdef01 busy 2017-03-14T15:09:00Z - tab\there",
+		"23456789-abcd-ef01-2345-6789abcdef01 busy 2017-03-14T15:09:00Z - " + strings.Repeat("x", faststatus.MaxMessageLen+1),
+		"23456789-abcd-ef01-2345-6789abcdef01 busy 2017-03-14T15:09:00Z - \xff",
+	} {
+		var got faststatus.Resource
+		if err := (&got).UnmarshalText([]byte(txt)); err == nil {
+			t.Errorf("UnmarshalText(%q) = <nil>, expected error", txt)
+		}
+	}
+}
+
+func TestResourceMessageUntilInvalid(t *testing.T) {
+	since := time.Date(2017, 3, 14, 15, 9, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		resource faststatus.Resource
+	}{
+		{"until equals since", faststatus.Resource{Since: since, Until: since}},
+		{"until before since", faststatus.Resource{Since: since, Until: since.Add(-time.Minute)}},
+		{"long message", faststatus.Resource{Since: since, Message: strings.Repeat("x", faststatus.MaxMessageLen+1)}},
+		{"newline in message", faststatus.Resource{Since: since, Message: "back\nsoon"}},
+		{"invalid UTF-8", faststatus.Resource{Since: since, Message: "\xff"}},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.resource.MarshalText(); err == nil {
+				t.Errorf("MarshalText() = <nil>, expected error")
+			}
+			if _, err := json.Marshal(tc.resource); err == nil {
+				t.Errorf("json.Marshal() = <nil>, expected error")
+			}
+			if _, err := tc.resource.MarshalBinary(); err == nil {
+				t.Errorf("MarshalBinary() = <nil>, expected error")
+			}
+			if _, err := tc.resource.MarshalCBOR(); err == nil {
+				t.Errorf("MarshalCBOR() = <nil>, expected error")
+			}
+			if _, err := tc.resource.MarshalMsgpack(); err == nil {
+				t.Errorf("MarshalMsgpack() = <nil>, expected error")
+			}
+		})
+	}
+
+	if _, err := (faststatus.Resource{Message: strings.Repeat("é", faststatus.MaxMessageLen/2)}).MarshalText(); err != nil {
+		t.Errorf("MarshalText() of a message of MaxMessageLen bytes = %+v, expected no error", err)
+	}
+	var got faststatus.Resource
+	js := []byte(`{"since":"2017-03-14T15:09:00Z","until":"2017-03-14T15:00:00Z"}`)
+	if err := json.Unmarshal(js, &got); err == nil {
+		t.Errorf("json.Unmarshal(%s) = <nil>, expected error", js)
+	}
+}
+
+func TestResourceMessageUntilRoundTrip(t *testing.T) {
+	codecs := []struct {
+		name      string
+		marshal   func(faststatus.Resource) ([]byte, error)
+		unmarshal func(*faststatus.Resource, []byte) error
+	}{
+		{"text", faststatus.Resource.MarshalText, (*faststatus.Resource).UnmarshalText},
+		{"json", func(r faststatus.Resource) ([]byte, error) { return json.Marshal(r) }, func(r *faststatus.Resource, b []byte) error { return json.Unmarshal(b, r) }},
+		{"binary", faststatus.Resource.MarshalBinary, (*faststatus.Resource).UnmarshalBinary},
+		{"cbor", faststatus.Resource.MarshalCBOR, (*faststatus.Resource).UnmarshalCBOR},
+		{"msgpack", faststatus.Resource.MarshalMsgpack, (*faststatus.Resource).UnmarshalMsgpack},
+	}
+	for _, c := range codecs {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			f := func(r faststatus.Resource, minutes uint16, message string) bool {
+				r.Until = r.Since.Add(time.Duration(minutes)*time.Minute + time.Minute)
+				r.Message = strings.Map(func(c rune) rune {
+					if unicode.IsControl(c) {
+						return -1
+					}
+					return c
+				}, message)
+				if len(r.Message) > faststatus.MaxMessageLen {
+					r.Message = ""
+				}
+				b, err := c.marshal(r)
+				if err != nil {
+					t.Logf("marshaling %+v: %+v", r, err)
+					return false
+				}
+				var got faststatus.Resource
+				if err := c.unmarshal(&got, b); err != nil {
+					t.Logf("unmarshaling %x: %+v", b, err)
+					return false
+				}
+				return got.Equal(r)
+			}
+			if err := quick.Check(f, nil); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestResourceMessageUntilBinaryVersions(t *testing.T) {
+	since := time.Date(2017, 3, 14, 15, 9, 0, 0, time.UTC)
+	r := faststatus.Resource{Since: since, Message: "in a 1:1", Until: since.Add(time.Hour)}
+	b, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v.MarshalBinary() = %+v, expected no error", r, err)
+	}
+	if b[2] != 0x03 {
+		t.Fatalf("%+v.MarshalBinary() version = %d, expected 3", r, b[2])
+	}
+	for _, v := range []byte{0x00, 0x01, 0x02} {
+		if _, err := r.MarshalBinaryVersion(v); err == nil {
+			t.Errorf("MarshalBinaryVersion(%d) with Message and Until = <nil>, expected error", v)
+		}
+	}
+
+	// version 0x02 did not define the tags, so they are skipped
+	b[2] = 0x02
+	var got faststatus.Resource
+	if err := (&got).UnmarshalBinary(b); err != nil || !got.Equal(faststatus.Resource{Since: since}) {
+		t.Fatalf("UnmarshalBinary(%x) = %+v, %+v, expected only Since", b, got, err)
+	}
+
+	for _, ext := range [][]byte{
+		{0x03, 0x01, 0x00},
+		{0x02, 0x01, '\n'},
+	} {
+		b, _ := faststatus.Resource{Since: since}.MarshalBinary()
+		b = append(b, ext...)
+		if err := (&got).UnmarshalBinary(b); err == nil {
+			t.Errorf("UnmarshalBinary(%x) = <nil>, expected error", b)
+		}
+	}
+}
+
+func TestResourceHLCRoundTrip(t *testing.T) {
+	codecs := []struct {
+		name      string
+		marshal   func(faststatus.Resource) ([]byte, error)
+		unmarshal func(*faststatus.Resource, []byte) error
+	}{
+		{"json", func(r faststatus.Resource) ([]byte, error) { return json.Marshal(r) }, func(r *faststatus.Resource, b []byte) error { return json.Unmarshal(b, r) }},
+		{"binary", faststatus.Resource.MarshalBinary, (*faststatus.Resource).UnmarshalBinary},
+	}
+	for _, c := range codecs {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			f := func(r faststatus.Resource, h faststatus.HLC) bool {
+				r.HLC = h
+				b, err := c.marshal(r)
+				if err != nil {
+					t.Logf("marshaling %+v: %+v", r, err)
+					return false
+				}
+				var got faststatus.Resource
+				if err := c.unmarshal(&got, b); err != nil {
+					t.Logf("unmarshaling %x: %+v", b, err)
+					return false
+				}
+				return got.Equal(r)
+			}
+			if err := quick.Check(f, nil); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestResourceMarshalBinaryLowestVersion(t *testing.T) {
+	since := time.Date(2017, 3, 14, 15, 9, 0, 0, time.UTC)
+	testCases := []struct {
+		name    string
+		r       faststatus.Resource
+		version byte
+	}{
+		{"plain", faststatus.Resource{Status: faststatus.Busy, Since: since}, 0x00},
+		{"unknown", faststatus.Resource{Status: faststatus.Unknown, Since: since}, 0x01},
+		{"labels", faststatus.Resource{Since: since, Labels: map[string]string{"floor": "3"}}, 0x02},
+		{"message", faststatus.Resource{Since: since, Message: "in a 1:1"}, 0x03},
+		{"until", faststatus.Resource{Since: since, Until: since.Add(time.Hour)}, 0x03},
+		{"hlc", faststatus.Resource{Since: since, HLC: faststatus.HLC{Wall: since.UnixNano()}}, 0x02},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := tc.r.MarshalBinary()
+			if err != nil {
+				t.Fatalf("%+v.MarshalBinary() = %+v, expected no error", tc.r, err)
+			}
+			// a reader of that version decodes what its writers wrote
+			want, err := tc.r.MarshalBinaryVersion(tc.version)
+			if err != nil || !bytes.Equal(b, want) {
+				t.Fatalf("%+v.MarshalBinary() = %x, expected %x as written by version %d", tc.r, b, want, tc.version)
+			}
+			a, err := tc.r.AppendBinary(nil)
+			if err != nil || !bytes.Equal(a, b) {
+				t.Fatalf("%+v.AppendBinary(nil) = %x, %+v, expected %x", tc.r, a, err, b)
+			}
+		})
+	}
+}
+
+func TestResourceHLCBinaryVersions(t *testing.T) {
+	since := time.Date(2017, 3, 14, 15, 9, 0, 0, time.UTC)
+	r := faststatus.Resource{Since: since, HLC: faststatus.HLC{Wall: since.UnixNano(), Logical: 2, Node: 7}}
+	b, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v.MarshalBinary() = %+v, expected no error", r, err)
+	}
+	// the HLC is an extension field, which older readers skip
+	if b[2] != 0x02 {
+		t.Fatalf("%+v.MarshalBinary() version = %d, expected 2", r, b[2])
+	}
+	for _, v := range []byte{0x00, 0x01} {
+		if _, err := r.MarshalBinaryVersion(v); err == nil {
+			t.Errorf("MarshalBinaryVersion(%d) with HLC = <nil>, expected error", v)
+		}
+	}
+	for _, v := range []byte{0x02, 0x03} {
+		b, err := r.MarshalBinaryVersion(v)
+		if err != nil {
+			t.Fatalf("MarshalBinaryVersion(%d) with HLC = %+v, expected no error", v, err)
+		}
+		var got faststatus.Resource
+		if err := (&got).UnmarshalBinary(b); err != nil || !got.Equal(r) {
+			t.Fatalf("UnmarshalBinary(%x) = %+v, %+v, expected %+v", b, got, err, r)
+		}
+	}
+
+	var got faststatus.Resource
+	b, _ = faststatus.Resource{Since: since}.MarshalBinaryVersion(0x02)
+	b = append(b, 0x04, 0x01, 0x00)
+	if err := (&got).UnmarshalBinary(b); err == nil {
+		t.Errorf("UnmarshalBinary(%x) = <nil>, expected error", b)
+	}
+}
+
+func TestResourceEqualHLC(t *testing.T) {
+	a := faststatus.Resource{HLC: faststatus.HLC{Wall: 1, Logical: 1, Node: 1}}
+	b := a
+	b.HLC.Node = 2
+	if a.Equal(b) {
+		t.Fatalf("%+v.Equal(%+v) = true, expected false", a, b)
+	}
+}
+
 func TestNewResourceHasAnID(t *testing.T) {
 	hasAnID := func() bool {
 		r := faststatus.NewResource()
@@ -835,3 +1421,127 @@ func TestNewResourceIsMostlyZero(t *testing.T) {
 		t.Fatalf("has non-zero-value properties: %+v", err)
 	}
 }
+
+func TestResourceAppendText(t *testing.T) {
+	f := func(prefix []byte, r faststatus.Resource) bool {
+		want, err := r.MarshalText()
+		if err != nil {
+			return false
+		}
+		got, err := r.AppendText(append([]byte{}, prefix...))
+		return err == nil && bytes.Equal(got, append(append([]byte{}, prefix...), want...))
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResourceAppendBinary(t *testing.T) {
+	f := func(prefix []byte, r faststatus.Resource) bool {
+		got, err := r.AppendBinary(append([]byte{}, prefix...))
+		if err != nil || !bytes.Equal(got[:len(prefix)], prefix) {
+			return false
+		}
+		got = got[len(prefix):]
+		since, _ := r.Since.MarshalBinary()
+		var back faststatus.Resource
+		return bytes.Equal(got[21:36], since) &&
+			(&back).UnmarshalBinary(got) == nil && back.Equal(r)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResourceAppendBinarySubMinuteOffset(t *testing.T) {
+	r := faststatus.Resource{Since: time.Date(1900, 1, 1, 0, 0, 0, 0, time.FixedZone("LMT", 1172))}
+	if _, err := r.AppendBinary(nil); err == nil {
+		t.Fatalf("AppendBinary() = <nil>, expected error for a sub-minute offset")
+	}
+}
+
+func TestResourceEncodingAllocs(t *testing.T) {
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01},
+		Status: faststatus.Occupied,
+		Since:  time.Date(2017, 3, 14, 15, 9, 26, 535897932, time.UTC),
+	}
+	ext := r
+	ext.Message = strings.Repeat("x", faststatus.MaxMessageLen)
+	ext.Until = r.Since.Add(time.Hour)
+	ext.HLC = faststatus.HLC{Wall: r.Since.UnixNano(), Logical: 1, Node: 2}
+	txt, _ := r.MarshalText()
+	bin, _ := r.MarshalBinary()
+	buf := make([]byte, 0, 128)
+	extBuf := make([]byte, 0, 128+faststatus.MaxMessageLen)
+
+	testCases := []struct {
+		name string
+		fn   func()
+	}{
+		{"AppendText", func() { r.AppendText(buf[:0]) }},
+		{"AppendBinary", func() { r.AppendBinary(buf[:0]) }},
+		{"AppendBinary extensions", func() { ext.AppendBinary(extBuf[:0]) }},
+		{"UnmarshalText", func() { new(faststatus.Resource).UnmarshalText(txt) }},
+		{"UnmarshalBinary", func() { new(faststatus.Resource).UnmarshalBinary(bin) }},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if allocs := testing.AllocsPerRun(100, tc.fn); allocs != 0 {
+				t.Fatalf("%s allocates %v times per run, expected none", tc.name, allocs)
+			}
+		})
+	}
+}
+
+func BenchmarkResourceAppendText(b *testing.B) {
+	r := faststatus.NewResource()
+	r.Since = time.Now().UTC()
+	buf := make([]byte, 0, 128)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf, _ = r.AppendText(buf[:0])
+	}
+}
+
+func BenchmarkResourceMarshalText(b *testing.B) {
+	r := faststatus.NewResource()
+	r.Since = time.Now().UTC()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.MarshalText()
+	}
+}
+
+func BenchmarkResourceUnmarshalText(b *testing.B) {
+	r := faststatus.NewResource()
+	r.Since = time.Now().UTC()
+	txt, _ := r.MarshalText()
+	var got faststatus.Resource
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		(&got).UnmarshalText(txt)
+	}
+}
+
+func BenchmarkResourceAppendBinary(b *testing.B) {
+	r := faststatus.NewResource()
+	r.Since = time.Now().UTC()
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf, _ = r.AppendBinary(buf[:0])
+	}
+}
+
+func BenchmarkResourceUnmarshalBinary(b *testing.B) {
+	r := faststatus.NewResource()
+	r.Since = time.Now().UTC()
+	bin, _ := r.MarshalBinary()
+	var got faststatus.Resource
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		(&got).UnmarshalBinary(bin)
+	}
+}
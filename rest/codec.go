@@ -0,0 +1,138 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+// A codec encodes and decodes a Resource for one media type.
+type codec struct {
+	contentType string
+	marshal     func(faststatus.Resource) ([]byte, error)
+	unmarshal   func(*faststatus.Resource, []byte) error
+}
+
+var textCodec = codec{
+	contentType: "text/plain; charset=utf-8",
+	marshal:     faststatus.Resource.MarshalText,
+	unmarshal:   (*faststatus.Resource).UnmarshalText,
+}
+
+var cborCodec = codec{
+	contentType: "application/cbor",
+	marshal:     faststatus.Resource.MarshalCBOR,
+	unmarshal:   (*faststatus.Resource).UnmarshalCBOR,
+}
+
+var msgpackCodec = codec{
+	contentType: "application/msgpack",
+	marshal:     faststatus.Resource.MarshalMsgpack,
+	unmarshal:   (*faststatus.Resource).UnmarshalMsgpack,
+}
+
+// codecs maps each supported media type to its codec.
+var codecs = map[string]codec{
+	"text/plain":            textCodec,
+	"application/cbor":      cborCodec,
+	"application/msgpack":   msgpackCodec,
+	"application/x-msgpack": msgpackCodec,
+}
+
+// responseCodec chooses the codec for a response from the Accept header
+// of the request, preferring the highest quality and then the earliest
+// listed. Anything unsupported falls back to text, so clients that do not
+// ask for a format always get one they can read.
+func responseCodec(r *http.Request) codec {
+	best, bestQ := textCodec, 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		c, ok := codecs[mediaType]
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if qs, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(qs, 64); err != nil {
+				continue
+			}
+		}
+		if q > bestQ {
+			best, bestQ = c, q
+		}
+	}
+	return best
+}
+
+// requestCodec chooses the codec for a request body from its Content-Type
+// header, which defaults to text.
+func requestCodec(r *http.Request) (codec, error) {
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		return textCodec, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return codec{}, &restError{
+			err:  fmt.Errorf("parsing Content-Type: %w", err),
+			code: http.StatusUnsupportedMediaType,
+		}
+	}
+	c, ok := codecs[mediaType]
+	if !ok {
+		return codec{}, &restError{
+			err:  fmt.Errorf("unsupported Content-Type %q", mediaType),
+			code: http.StatusUnsupportedMediaType,
+		}
+	}
+	return c, nil
+}
+
+// readResource decodes the Resource in the request body.
+func readResource(r *http.Request) (faststatus.Resource, error) {
+	c, err := requestCodec(r)
+	if err != nil {
+		return faststatus.Resource{}, err
+	}
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return faststatus.Resource{}, fmt.Errorf("reading from request body: %w", err)
+	}
+	var resource faststatus.Resource
+	if err := c.unmarshal(&resource, b); err != nil {
+		return faststatus.Resource{}, &restError{
+			err:  fmt.Errorf("unmarshaling resource from request: %w", err),
+			code: http.StatusBadRequest,
+		}
+	}
+	return resource, nil
+}
+
+// writeResource responds with the Resource in the format the request
+// accepts, and the given status code.
+func writeResource(w http.ResponseWriter, r *http.Request, code int, resource faststatus.Resource) error {
+	c := responseCodec(r)
+	rb, err := c.marshal(resource)
+	if err != nil {
+		return fmt.Errorf("marshaling resource for response: %w", err)
+	}
+	w.Header().Set("Content-Type", c.contentType)
+	w.Header().Add("Vary", "Accept")
+	if !resource.HLC.IsZero() {
+		w.Header().Set(hlcHeader, resource.HLC.String())
+	}
+	w.WriteHeader(code)
+	w.Write(rb)
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest_test
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/rest"
+)
+
+var codecTestResource = faststatus.Resource{
+	ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+	Status: faststatus.Busy,
+	Since:  time.Date(2017, 3, 4, 5, 6, 7, 0, time.UTC),
+}
+
+const codecTestPath = "/01234567-89ab-cdef-0123-456789abcdef"
+
+func TestHandlerGetContentNegotiation(t *testing.T) {
+	tests := []struct {
+		name      string
+		accept    string
+		wantType  string
+		unmarshal func(*faststatus.Resource, []byte) error
+	}{
+		{"none", "", "text/plain", (*faststatus.Resource).UnmarshalText},
+		{"anything", "*/*", "text/plain", (*faststatus.Resource).UnmarshalText},
+		{"unsupported", "application/xml", "text/plain", (*faststatus.Resource).UnmarshalText},
+		{"cbor", "application/cbor", "application/cbor", (*faststatus.Resource).UnmarshalCBOR},
+		{"msgpack", "application/msgpack", "application/msgpack", (*faststatus.Resource).UnmarshalMsgpack},
+		{"x-msgpack", "application/x-msgpack", "application/msgpack", (*faststatus.Resource).UnmarshalMsgpack},
+		{"first listed", "application/cbor, application/msgpack", "application/cbor", (*faststatus.Resource).UnmarshalCBOR},
+		{"quality", "application/cbor;q=0.5, application/msgpack", "application/msgpack", (*faststatus.Resource).UnmarshalMsgpack},
+		{"quality zero", "application/cbor;q=0", "text/plain", (*faststatus.Resource).UnmarshalText},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			s := &rest.Server{Store: &mockStore{
+				getFn: func(faststatus.ID) (faststatus.Resource, error) {
+					return codecTestResource, nil
+				},
+			}}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, codecTestPath, nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			s.ServeHTTP(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusOK)
+			}
+			gotType, _, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+			if err != nil || gotType != tc.wantType {
+				t.Fatalf("Content-Type %q, %+v, expected %q", gotType, err, tc.wantType)
+			}
+			if got := w.Header().Get("Vary"); got != "Accept" {
+				t.Fatalf("Vary %q, expected %q", got, "Accept")
+			}
+			var got faststatus.Resource
+			if err := tc.unmarshal(&got, w.Body.Bytes()); err != nil || !got.Equal(codecTestResource) {
+				t.Fatalf("response body %x decoded to %+v, %+v, expected %+v", w.Body.Bytes(), got, err, codecTestResource)
+			}
+		})
+	}
+}
+
+func TestHandlerPutContentType(t *testing.T) {
+	cborBody, _ := codecTestResource.MarshalCBOR()
+	msgpackBody, _ := codecTestResource.MarshalMsgpack()
+	textBody, _ := codecTestResource.MarshalText()
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		wantCode    int
+		wantSaved   bool
+	}{
+		{"default text", "", textBody, http.StatusOK, true},
+		{"text", "text/plain; charset=utf-8", textBody, http.StatusOK, true},
+		{"cbor", "application/cbor", cborBody, http.StatusOK, true},
+		{"msgpack", "application/msgpack", msgpackBody, http.StatusOK, true},
+		{"x-msgpack", "application/x-msgpack", msgpackBody, http.StatusOK, true},
+		{"mismatched body", "application/cbor", msgpackBody, http.StatusBadRequest, false},
+		{"unsupported", "application/json", textBody, http.StatusUnsupportedMediaType, false},
+		{"invalid", "application/", textBody, http.StatusUnsupportedMediaType, false},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var saved faststatus.Resource
+			store := &mockStore{
+				saveFn: func(r faststatus.Resource) error {
+					saved = r
+					return nil
+				},
+			}
+			s := &rest.Server{Store: store}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, codecTestPath, bytes.NewReader(tc.body))
+			if tc.contentType != "" {
+				r.Header.Set("Content-Type", tc.contentType)
+			}
+			s.ServeHTTP(w, r)
+
+			if w.Code != tc.wantCode {
+				t.Fatalf("returned Status Code %03d, expected %03d", w.Code, tc.wantCode)
+			}
+			if (store.saveCalled > 0) != tc.wantSaved {
+				t.Fatalf("Save called %d times, expected called: %t", store.saveCalled, tc.wantSaved)
+			}
+			if tc.wantSaved && !saved.Equal(codecTestResource) {
+				t.Fatalf("saved %+v, expected %+v", saved, codecTestResource)
+			}
+		})
+	}
+}
+
+func TestHandlerMessageUntil(t *testing.T) {
+	r := codecTestResource
+	r.Message = "in a 1:1, back at 15:30"
+	r.Until = r.Since.Add(time.Hour)
+	s := &rest.Server{Store: &mockStore{
+		getFn: func(faststatus.ID) (faststatus.Resource, error) {
+			return r, nil
+		},
+		saveFn: func(faststatus.Resource) error {
+			return nil
+		},
+	}}
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, codecTestPath, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusOK)
+	}
+	want := "01234567-89ab-cdef-0123-456789abcdef busy 2017-03-04T05:06:07Z 2017-03-04T06:06:07Z in a 1:1, back at 15:30"
+	if got := w.Body.String(); got != want {
+		t.Fatalf("returned body %q, expected %q", got, want)
+	}
+
+	bad := "01234567-89ab-cdef-0123-456789abcdef busy 2017-03-04T05:06:07Z 2017-03-04T04:06:07Z"
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodPut, codecTestPath, strings.NewReader(bad)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusBadRequest)
+	}
+}
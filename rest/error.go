@@ -4,8 +4,12 @@
 package rest
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
 )
 
 type restError struct {
@@ -13,20 +17,49 @@ type restError struct {
 	code int
 }
 
-func (e restError) Error() string {
+func (e *restError) Error() string {
 	return fmt.Sprintf("%03d %+v", e.code, e.err)
 }
 
-func (e restError) Code() int {
+func (e *restError) Code() int {
 	return e.code
 }
 
+func (e *restError) Unwrap() error {
+	return e.err
+}
+
+// errorCode returns the HTTP status code of the first error in the chain
+// that has one, or 500.
 func errorCode(e error) int {
 	type codeError interface {
 		Code() int
 	}
-	if e, ok := e.(codeError); ok {
-		return e.Code()
+	var ce codeError
+	if errors.As(e, &ce) {
+		return ce.Code()
 	}
 	return http.StatusInternalServerError
 }
+
+// validate returns a 400 error listing every invalid field of the Resource
+// in a request, including an ID that does not match the path ID and a Since
+// or HLC further ahead of the server clock than the skew policy allows.
+func validate(id faststatus.ID, resource faststatus.Resource, skew faststatus.SkewPolicy) error {
+	var verr faststatus.ValidationError
+	errors.As(resource.ValidateAt(time.Now(), skew), &verr)
+	if id != resource.ID {
+		txt, _ := id.MarshalText()
+		verr = append(verr, &faststatus.FieldError{
+			Field: "ID",
+			Err:   fmt.Errorf("does not match path ID %s", txt),
+		})
+	}
+	if len(verr) == 0 {
+		return nil
+	}
+	return &restError{
+		err:  verr,
+		code: http.StatusBadRequest,
+	}
+}
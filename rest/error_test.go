@@ -0,0 +1,65 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+func TestErrorCode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"plain error", errors.New("an error"), http.StatusInternalServerError},
+		{"rest error", &restError{code: http.StatusConflict}, http.StatusConflict},
+		{"wrapped rest error", fmt.Errorf("handling: %w", &restError{code: http.StatusNotFound}), http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := errorCode(tc.err); got != tc.wantCode {
+				t.Fatalf("errorCode(%+v) = %03d, expected %03d", tc.err, got, tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestRestErrorUnwraps(t *testing.T) {
+	err := fmt.Errorf("saving: %w", &restError{
+		err:  fmt.Errorf("saving resource to store: %w", faststatus.ErrConflict),
+		code: http.StatusConflict,
+	})
+	if !errors.Is(err, faststatus.ErrConflict) || !faststatus.ConflictError(err) {
+		t.Fatalf("%+v does not match faststatus.ErrConflict, expected it to", err)
+	}
+}
+
+func TestExpiryParamsErrorText(t *testing.T) {
+	testCases := []struct {
+		query string
+		want  string
+	}{
+		{"?ttl=soon", `400 parsing ttl "soon": time: invalid duration "soon"`},
+		{"?ttl=-1m", `400 negative ttl "-1m"`},
+		{"?fallback=gone", `400 parsing fallback "gone": `},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.query, func(t *testing.T) {
+			_, _, err := expiryParams(httptest.NewRequest(http.MethodPut, "/"+tc.query, nil))
+			if err == nil || !strings.HasPrefix(err.Error(), tc.want) || strings.Contains(err.Error(), "%!") {
+				t.Fatalf("expiryParams(%q) = %v, expected %q", tc.query, err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+// ExpiryStore saves Resources that fall back to another Status if they are
+// not renewed in time. Saving with a ttl and heartbeats are only available
+// when the Server's Store also implements ExpiryStore.
+type ExpiryStore interface {
+	SaveWithTTL(faststatus.Resource, time.Duration, faststatus.Status) error
+	Heartbeat(faststatus.ID, time.Duration) (time.Time, error)
+}
+
+// defaultFallback is the Status reported for an expired Resource unless the
+// request asks for another.
+const defaultFallback = faststatus.Unknown
+
+func (s *Server) handleHeartbeat(id faststatus.ID, w http.ResponseWriter, r *http.Request) error {
+	switch r.Method {
+	case http.MethodPost:
+		return s.postHeartbeat(id).serveHTTP(w, r)
+	default:
+		return &restError{code: http.StatusMethodNotAllowed}
+	}
+}
+
+func (s *Server) expiryStore() (ExpiryStore, error) {
+	es, ok := s.Store.(ExpiryStore)
+	if !ok {
+		return nil, &restError{
+			err:  fmt.Errorf("store does not support expiry"),
+			code: http.StatusNotImplemented,
+		}
+	}
+	return es, nil
+}
+
+// expiryParams parses the ttl and fallback query parameters, like
+// "?ttl=15m&fallback=free". Both are optional.
+func expiryParams(r *http.Request) (time.Duration, faststatus.Status, error) {
+	var (
+		ttl      time.Duration
+		fallback = defaultFallback
+		err      error
+	)
+	query := r.URL.Query()
+	if txt := query.Get("ttl"); txt != "" {
+		if ttl, err = time.ParseDuration(txt); err != nil {
+			return 0, fallback, &restError{
+				err:  fmt.Errorf("parsing ttl %q: %w", txt, err),
+				code: http.StatusBadRequest,
+			}
+		}
+		if ttl < 0 {
+			return 0, fallback, &restError{
+				err:  fmt.Errorf("negative ttl %q", txt),
+				code: http.StatusBadRequest,
+			}
+		}
+	}
+	if txt := query.Get("fallback"); txt != "" {
+		if err := (&fallback).UnmarshalText([]byte(txt)); err != nil {
+			return 0, fallback, &restError{
+				err:  fmt.Errorf("parsing fallback %q: %w", txt, err),
+				code: http.StatusBadRequest,
+			}
+		}
+	}
+	return ttl, fallback, nil
+}
+
+// postHeartbeat renews the expiry of a resource, optionally with a new ttl,
+// and responds with the new expiry time.
+func (s *Server) postHeartbeat(id faststatus.ID) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		es, err := s.expiryStore()
+		if err != nil {
+			return err
+		}
+		ttl, _, err := expiryParams(r)
+		if err != nil {
+			return err
+		}
+		at, err := es.Heartbeat(id, ttl)
+		switch {
+		case faststatus.NotFoundError(err):
+			return &restError{
+				err:  err,
+				code: http.StatusNotFound,
+			}
+		case faststatus.ConflictError(err):
+			return &restError{
+				err:  err,
+				code: http.StatusConflict,
+			}
+		case err != nil:
+			return fmt.Errorf("renewing expiry in store: %w", err)
+		}
+		txt, err := at.MarshalText()
+		if err != nil {
+			return fmt.Errorf("marshaling expiry for response: %w", err)
+		}
+		w.Write(txt)
+		return nil
+	}
+}
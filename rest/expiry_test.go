@@ -0,0 +1,139 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/rest"
+)
+
+func TestHandlerPutWithTTL(t *testing.T) {
+	resource := faststatus.NewResource()
+	resource.Status = faststatus.Occupied
+	resource.Since = time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+	idB, _ := resource.ID.MarshalText()
+	body, _ := resource.MarshalText()
+
+	testCases := []struct {
+		name         string
+		query        string
+		wantCode     int
+		wantTTL      time.Duration
+		wantFallback faststatus.Status
+	}{
+		{"ttl", "?ttl=15m", http.StatusOK, 15 * time.Minute, faststatus.Unknown},
+		{"ttl and fallback", "?ttl=1h&fallback=busy", http.StatusOK, time.Hour, faststatus.Busy},
+		{"bad ttl", "?ttl=soon", http.StatusBadRequest, 0, faststatus.Free},
+		{"negative ttl", "?ttl=-1m", http.StatusBadRequest, 0, faststatus.Free},
+		{"bad fallback", "?ttl=1m&fallback=gone", http.StatusBadRequest, 0, faststatus.Free},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				gotTTL      time.Duration
+				gotFallback faststatus.Status
+			)
+			store := &mockExpiryStore{
+				saveWithTTLFn: func(r faststatus.Resource, ttl time.Duration, fallback faststatus.Status) error {
+					gotTTL, gotFallback = ttl, fallback
+					return nil
+				},
+			}
+			var s = &rest.Server{Store: store}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, "/"+string(idB)+tc.query, bytes.NewReader(body))
+			s.ServeHTTP(w, r)
+			if w.Code != tc.wantCode {
+				t.Fatalf("returned Status Code %03d, expected %03d", w.Code, tc.wantCode)
+			}
+			if gotTTL != tc.wantTTL || gotFallback != tc.wantFallback {
+				t.Fatalf("SaveWithTTL called with %s, %s, expected %s, %s", gotTTL, gotFallback, tc.wantTTL, tc.wantFallback)
+			}
+			if store.saveCalled != 0 {
+				t.Fatalf("Store Save called %d times, expected none", store.saveCalled)
+			}
+		})
+	}
+
+	t.Run("not implemented", func(t *testing.T) {
+		var s = &rest.Server{Store: &mockStore{}}
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, "/"+string(idB)+"?ttl=15m", bytes.NewReader(body))
+		s.ServeHTTP(w, r)
+		if w.Code != http.StatusNotImplemented {
+			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusNotImplemented)
+		}
+	})
+}
+
+func TestHandlerPostHeartbeat(t *testing.T) {
+	id, _ := faststatus.NewID()
+	idB, _ := id.MarshalText()
+	at := time.Date(2017, 3, 14, 15, 24, 26, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		query    string
+		err      error
+		wantCode int
+		wantTTL  time.Duration
+		wantBody string
+	}{
+		{"keep ttl", "", nil, http.StatusOK, 0, "2017-03-14T15:24:26Z"},
+		{"new ttl", "?ttl=15m", nil, http.StatusOK, 15 * time.Minute, "2017-03-14T15:24:26Z"},
+		{"no ttl", "", conflictError(true), http.StatusConflict, 0, ""},
+		{"never saved", "?ttl=15m", notFoundError(true), http.StatusNotFound, 15 * time.Minute, ""},
+		{"bad ttl", "?ttl=soon", nil, http.StatusBadRequest, 0, ""},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var gotTTL time.Duration
+			var s = &rest.Server{Store: &mockExpiryStore{
+				heartbeatFn: func(got faststatus.ID, ttl time.Duration) (time.Time, error) {
+					if got != id {
+						t.Fatalf("Heartbeat called with %s, expected %s", got, id)
+					}
+					gotTTL = ttl
+					return at, tc.err
+				},
+			}}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/"+string(idB)+"/heartbeat"+tc.query, nil)
+			s.ServeHTTP(w, r)
+			if w.Code != tc.wantCode {
+				t.Fatalf("returned Status Code %03d, expected %03d", w.Code, tc.wantCode)
+			}
+			if gotTTL != tc.wantTTL {
+				t.Fatalf("Heartbeat called with %s, expected %s", gotTTL, tc.wantTTL)
+			}
+			if tc.wantCode == http.StatusOK && w.Body.String() != tc.wantBody {
+				t.Fatalf("responded with %q, expected %q", w.Body.String(), tc.wantBody)
+			}
+		})
+	}
+}
+
+type mockExpiryStore struct {
+	mockStore
+	saveWithTTLFn func(faststatus.Resource, time.Duration, faststatus.Status) error
+	heartbeatFn   func(faststatus.ID, time.Duration) (time.Time, error)
+}
+
+func (s *mockExpiryStore) SaveWithTTL(r faststatus.Resource, ttl time.Duration, fallback faststatus.Status) error {
+	return s.saveWithTTLFn(r, ttl, fallback)
+}
+
+func (s *mockExpiryStore) Heartbeat(id faststatus.ID, ttl time.Duration) (time.Time, error) {
+	return s.heartbeatFn(id, ttl)
+}
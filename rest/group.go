@@ -0,0 +1,224 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+const groupsPrefix = "/groups/"
+
+// GroupStore gets, saves, and deletes Groups. The group endpoints are
+// only available when the Server's Store also implements GroupStore.
+type GroupStore interface {
+	SaveGroup(faststatus.Group) error
+	GetGroup(faststatus.ID) (faststatus.Group, error)
+	DeleteGroup(faststatus.ID) error
+}
+
+func (s *Server) handleGroups(w http.ResponseWriter, r *http.Request) error {
+	path := strings.TrimPrefix(r.URL.Path, groupsPrefix)
+	if path == "" {
+		switch r.Method {
+		case http.MethodPost:
+			return s.postGroup().serveHTTP(w, r)
+		default:
+			return &restError{code: http.StatusMethodNotAllowed}
+		}
+	}
+
+	parts := strings.SplitN(path, "/", 2)
+	var id faststatus.ID
+	if err := (&id).UnmarshalText([]byte(parts[0])); err != nil {
+		return &restError{
+			err:  fmt.Errorf("unmarshalling group id from path: %w", err),
+			code: http.StatusNotFound,
+		}
+	}
+	if len(parts) == 2 {
+		if parts[1] != "status" {
+			return &restError{code: http.StatusNotFound}
+		}
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			return s.getGroupStatus(id).serveHTTP(w, r)
+		default:
+			return &restError{code: http.StatusMethodNotAllowed}
+		}
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		return s.getGroup(id).serveHTTP(w, r)
+	case http.MethodPut:
+		return s.putGroup(id).serveHTTP(w, r)
+	case http.MethodDelete:
+		return s.deleteGroup(id).serveHTTP(w, r)
+	default:
+		return &restError{code: http.StatusMethodNotAllowed}
+	}
+}
+
+func (s *Server) groupStore() (GroupStore, error) {
+	gs, ok := s.Store.(GroupStore)
+	if !ok {
+		return nil, &restError{
+			err:  fmt.Errorf("store does not support groups"),
+			code: http.StatusNotImplemented,
+		}
+	}
+	return gs, nil
+}
+
+func readGroup(r *http.Request) (faststatus.Group, error) {
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return faststatus.Group{}, fmt.Errorf("reading from request body: %w", err)
+	}
+	var g faststatus.Group
+	if err := (&g).UnmarshalText(b); err != nil {
+		return faststatus.Group{}, &restError{
+			err:  fmt.Errorf("unmarshaling group from request: %w", err),
+			code: http.StatusBadRequest,
+		}
+	}
+	return g, nil
+}
+
+func (s *Server) postGroup() handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		gs, err := s.groupStore()
+		if err != nil {
+			return err
+		}
+		g, err := readGroup(r)
+		if err != nil {
+			return err
+		}
+		if g.ID == (faststatus.ID{}) {
+			if g.ID, err = s.ids().NewID(); err != nil {
+				return fmt.Errorf("generating group ID: %w", err)
+			}
+		}
+		if err := gs.SaveGroup(g); err != nil {
+			return fmt.Errorf("saving group to store: %w", err)
+		}
+		gb, err := g.MarshalText()
+		if err != nil {
+			return fmt.Errorf("marshaling group for response: %w", err)
+		}
+		id, _ := g.ID.MarshalText()
+		w.Header().Set("Location", groupsPrefix+string(id))
+		w.WriteHeader(http.StatusCreated)
+		w.Write(gb)
+		return nil
+	}
+}
+
+func (s *Server) putGroup(id faststatus.ID) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		gs, err := s.groupStore()
+		if err != nil {
+			return err
+		}
+		g, err := readGroup(r)
+		if err != nil {
+			return err
+		}
+		if id != g.ID {
+			return &restError{
+				err:  fmt.Errorf("group ID %q does not match path ID %q", g.ID, id),
+				code: http.StatusBadRequest,
+			}
+		}
+		if err := gs.SaveGroup(g); err != nil {
+			return fmt.Errorf("saving group to store: %w", err)
+		}
+		gb, err := g.MarshalText()
+		if err != nil {
+			return fmt.Errorf("marshaling group for response: %w", err)
+		}
+		w.Write(gb)
+		return nil
+	}
+}
+
+func (s *Server) getGroup(id faststatus.ID) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		gs, err := s.groupStore()
+		if err != nil {
+			return err
+		}
+		g, err := gs.GetGroup(id)
+		if faststatus.NotFoundError(err) {
+			return &restError{
+				err:  err,
+				code: http.StatusNotFound,
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("getting group from store: %w", err)
+		}
+		gb, err := g.MarshalText()
+		if err != nil {
+			return fmt.Errorf("marshaling group for response: %w", err)
+		}
+		w.Write(gb)
+		return nil
+	}
+}
+
+func (s *Server) deleteGroup(id faststatus.ID) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		gs, err := s.groupStore()
+		if err != nil {
+			return err
+		}
+		if err := gs.DeleteGroup(id); err != nil {
+			return fmt.Errorf("deleting group from store: %w", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+}
+
+func (s *Server) getGroupStatus(id faststatus.ID) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		gs, err := s.groupStore()
+		if err != nil {
+			return err
+		}
+		g, err := gs.GetGroup(id)
+		if faststatus.NotFoundError(err) {
+			return &restError{
+				err:  err,
+				code: http.StatusNotFound,
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("getting group from store: %w", err)
+		}
+		members := make([]faststatus.Resource, 0, len(g.Members))
+		for _, member := range g.Members {
+			resource, err := s.Store.Get(member)
+			if faststatus.NotFoundError(err) {
+				resource = faststatus.Resource{ID: member, Status: faststatus.Unknown}
+			} else if err != nil {
+				return fmt.Errorf("getting member resource from store: %w", err)
+			}
+			members = append(members, resource)
+		}
+		sb, err := faststatus.Summarize(members...).MarshalText()
+		if err != nil {
+			return fmt.Errorf("marshaling group status for response: %w", err)
+		}
+		w.Write(sb)
+		return nil
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/rest"
+)
+
+func TestHandlerGroupsNotImplemented(t *testing.T) {
+	var s = &rest.Server{Store: &mockStore{}}
+
+	id, _ := faststatus.NewID()
+	idB, _ := id.MarshalText()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/groups/"+string(idB), nil)
+	s.ServeHTTP(w, r)
+	if w.Code != http.StatusNotImplemented {
+		t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusNotImplemented)
+	}
+}
+
+func TestHandlerPostGroup(t *testing.T) {
+	t.Run("assigns an ID", func(t *testing.T) {
+		var saved faststatus.Group
+		store := &mockGroupStore{saveGroupFn: func(g faststatus.Group) error {
+			saved = g
+			return nil
+		}}
+		var s = &rest.Server{Store: store}
+
+		body := "00000000-0000-0000-0000-000000000000 Floor 3\n01234567-89ab-cdef-0123-456789abcdef"
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/groups/", strings.NewReader(body))
+		s.ServeHTTP(w, r)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusCreated)
+		}
+		if store.saveGroupCalled != 1 {
+			t.Fatalf("Store SaveGroup called %d times, expected exactly once", store.saveGroupCalled)
+		}
+		if saved.ID == (faststatus.ID{}) {
+			t.Fatalf("saved group with zero-value ID")
+		}
+		var got faststatus.Group
+		if err := (&got).UnmarshalText(w.Body.Bytes()); err != nil {
+			t.Fatalf("Response body failed to unmarshal to Group: %+v", err)
+		}
+		if !got.Equal(saved) {
+			t.Fatalf("Response body unmarshals to %+v, expected %+v", got, saved)
+		}
+		id, _ := saved.ID.MarshalText()
+		if loc := w.HeaderMap.Get("Location"); loc != "/groups/"+string(id) {
+			t.Fatalf("Location %q, expected %q", loc, "/groups/"+string(id))
+		}
+	})
+
+	t.Run("bad body", func(t *testing.T) {
+		var s = &rest.Server{Store: &mockGroupStore{}}
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/groups/", strings.NewReader("not a group"))
+		s.ServeHTTP(w, r)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusBadRequest)
+		}
+	})
+}
+
+func TestHandlerPutGroup(t *testing.T) {
+	g := faststatus.NewGroup()
+	g.Name = "Floor 3"
+	g.Members = []faststatus.ID{faststatus.NewResource().ID}
+	body, _ := g.MarshalText()
+	id, _ := g.ID.MarshalText()
+
+	t.Run("id does not match", func(t *testing.T) {
+		store := &mockGroupStore{}
+		var s = &rest.Server{Store: store}
+		other, _ := faststatus.NewID()
+		otherB, _ := other.MarshalText()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, "/groups/"+string(otherB), bytes.NewReader(body))
+		s.ServeHTTP(w, r)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusBadRequest)
+		}
+		if store.saveGroupCalled != 0 {
+			t.Fatalf("Store SaveGroup called %d times, expected none", store.saveGroupCalled)
+		}
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		store := &mockGroupStore{saveGroupFn: func(faststatus.Group) error {
+			return fmt.Errorf("an error")
+		}}
+		var s = &rest.Server{Store: store}
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, "/groups/"+string(id), bytes.NewReader(body))
+		s.ServeHTTP(w, r)
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusInternalServerError)
+		}
+	})
+
+	t.Run("good request", func(t *testing.T) {
+		var saved faststatus.Group
+		store := &mockGroupStore{saveGroupFn: func(g faststatus.Group) error {
+			saved = g
+			return nil
+		}}
+		var s = &rest.Server{Store: store}
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, "/groups/"+string(id), bytes.NewReader(body))
+		s.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusOK)
+		}
+		if !saved.Equal(g) {
+			t.Fatalf("saved %+v, expected %+v", saved, g)
+		}
+		if !bytes.Equal(w.Body.Bytes(), body) {
+			t.Fatalf("responded with %s, expected %s", w.Body.Bytes(), body)
+		}
+	})
+}
+
+func TestHandlerGetAndDeleteGroup(t *testing.T) {
+	g := faststatus.NewGroup()
+	g.Name = "Floor 3"
+	id, _ := g.ID.MarshalText()
+
+	t.Run("not found", func(t *testing.T) {
+		var s = &rest.Server{Store: &mockGroupStore{getGroupFn: func(faststatus.ID) (faststatus.Group, error) {
+			return faststatus.Group{}, notFoundError(true)
+		}}}
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/groups/"+string(id), nil)
+		s.ServeHTTP(w, r)
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("found", func(t *testing.T) {
+		var s = &rest.Server{Store: &mockGroupStore{getGroupFn: func(faststatus.ID) (faststatus.Group, error) {
+			return g, nil
+		}}}
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/groups/"+string(id), nil)
+		s.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusOK)
+		}
+		var got faststatus.Group
+		if err := (&got).UnmarshalText(w.Body.Bytes()); err != nil {
+			t.Fatalf("Response body failed to unmarshal to Group: %+v", err)
+		}
+		if !got.Equal(g) {
+			t.Fatalf("Response body unmarshals to %+v, expected %+v", got, g)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		var deleted faststatus.ID
+		store := &mockGroupStore{deleteGroupFn: func(id faststatus.ID) error {
+			deleted = id
+			return nil
+		}}
+		var s = &rest.Server{Store: store}
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodDelete, "/groups/"+string(id), nil)
+		s.ServeHTTP(w, r)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusNoContent)
+		}
+		if deleted != g.ID {
+			t.Fatalf("deleted %s, expected %s", deleted, g.ID)
+		}
+	})
+}
+
+func TestHandlerGetGroupStatus(t *testing.T) {
+	members := map[faststatus.ID]faststatus.Resource{}
+	g := faststatus.NewGroup()
+	for _, status := range []faststatus.Status{faststatus.Occupied, faststatus.Busy, faststatus.Busy} {
+		resource := faststatus.NewResource()
+		resource.Status = status
+		members[resource.ID] = resource
+		g.Members = append(g.Members, resource.ID)
+	}
+	missing, _ := faststatus.NewID()
+	g.Members = append(g.Members, missing)
+
+	store := &mockGroupStore{
+		mockStore: mockStore{getFn: func(id faststatus.ID) (faststatus.Resource, error) {
+			if r, ok := members[id]; ok {
+				return r, nil
+			}
+			return faststatus.Resource{}, notFoundError(true)
+		}},
+		getGroupFn: func(faststatus.ID) (faststatus.Group, error) {
+			return g, nil
+		},
+	}
+	var s = &rest.Server{Store: store}
+
+	id, _ := g.ID.MarshalText()
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/groups/"+string(id)+"/status", nil)
+	s.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusOK)
+	}
+	if store.getCalled != len(g.Members) {
+		t.Fatalf("Store Get called %d times, expected %d", store.getCalled, len(g.Members))
+	}
+	want := "busy free=0 busy=2 occupied=1 unknown=1"
+	if got := w.Body.String(); got != want {
+		t.Fatalf("responded with %q, expected %q", got, want)
+	}
+}
+
+type mockGroupStore struct {
+	mockStore
+	saveGroupCalled int
+	saveGroupFn     func(faststatus.Group) error
+	getGroupFn      func(faststatus.ID) (faststatus.Group, error)
+	deleteGroupFn   func(faststatus.ID) error
+}
+
+func (s *mockGroupStore) SaveGroup(g faststatus.Group) error {
+	s.saveGroupCalled++
+	return s.saveGroupFn(g)
+}
+
+func (s *mockGroupStore) GetGroup(id faststatus.ID) (faststatus.Group, error) {
+	return s.getGroupFn(id)
+}
+
+func (s *mockGroupStore) DeleteGroup(id faststatus.ID) error {
+	return s.deleteGroupFn(id)
+}
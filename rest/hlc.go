@@ -0,0 +1,49 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+// hlcHeader carries the HLC of a Resource, in the form of the HLC
+// MarshalText method, whatever the encoding of the body. Clients send the
+// latest HLC they have seen with a PUT, and every response with a
+// Resource that has an HLC includes it, so that a client can tell whether
+// a later GET reflects its own writes.
+const hlcHeader = "Faststatus-HLC"
+
+// stamp returns the Resource from the request as it is to be saved: with
+// the HLC in the hlcHeader if set, checked against the Server Skew, and
+// then with the HLC updated by the Server Clock if it has one. A client
+// HLC too far ahead is rejected before it can push the Clock ahead.
+func (s *Server) stamp(r *http.Request, resource faststatus.Resource) (faststatus.Resource, error) {
+	if txt := r.Header.Get(hlcHeader); txt != "" {
+		if err := (&resource.HLC).UnmarshalText([]byte(txt)); err != nil {
+			return faststatus.Resource{}, &restError{
+				err:  fmt.Errorf("parsing %s header: %w", hlcHeader, err),
+				code: http.StatusBadRequest,
+			}
+		}
+	}
+	resource, err := s.Skew.Check(resource, time.Now())
+	if err != nil {
+		return faststatus.Resource{}, &restError{
+			err:  err,
+			code: http.StatusBadRequest,
+		}
+	}
+	switch {
+	case s.Clock == nil:
+	case resource.HLC.IsZero():
+		resource.HLC = s.Clock.Now()
+	default:
+		resource.HLC = s.Clock.Update(resource.HLC)
+	}
+	return resource, nil
+}
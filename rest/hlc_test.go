@@ -0,0 +1,169 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/rest"
+)
+
+func TestHandlerPutHLC(t *testing.T) {
+	body := "01234567-89ab-cdef-0123-456789abcdef busy 2017-03-04T05:06:07Z"
+	remote := faststatus.HLC{Wall: codecTestResource.Since.UnixNano() + 1<<40, Logical: 3, Node: 2}
+	testCases := []struct {
+		name       string
+		clock      bool
+		header     string
+		wantCode   int
+		wantHLC    func(faststatus.HLC) bool
+		wantHeader bool
+	}{
+		{"no clock or header", false, "", http.StatusOK,
+			func(h faststatus.HLC) bool { return h.IsZero() }, false},
+		{"header without clock", false, remote.String(), http.StatusOK,
+			func(h faststatus.HLC) bool { return h == remote }, true},
+		{"clock without header", true, "", http.StatusOK,
+			func(h faststatus.HLC) bool { return h.Node == 9 && !h.IsZero() }, true},
+		{"clock after header", true, remote.String(), http.StatusOK,
+			func(h faststatus.HLC) bool { return h.Node == 9 && h.After(remote) }, true},
+		{"invalid header", true, "yesterday", http.StatusBadRequest,
+			nil, false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var saved faststatus.Resource
+			s := &rest.Server{Store: &mockStore{
+				saveFn: func(r faststatus.Resource) error {
+					saved = r
+					return nil
+				},
+			}}
+			if tc.clock {
+				s.Clock = faststatus.NewClock(9)
+			}
+			req := httptest.NewRequest(http.MethodPut, codecTestPath, strings.NewReader(body))
+			if tc.header != "" {
+				req.Header.Set("Faststatus-HLC", tc.header)
+			}
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, req)
+			if w.Code != tc.wantCode {
+				t.Fatalf("returned Status Code %03d, expected %03d", w.Code, tc.wantCode)
+			}
+			if tc.wantHLC == nil {
+				return
+			}
+			if !tc.wantHLC(saved.HLC) {
+				t.Fatalf("saved HLC %v, not expected", saved.HLC)
+			}
+			got := w.Header().Get("Faststatus-HLC")
+			if (got != "") != tc.wantHeader {
+				t.Fatalf("returned Faststatus-HLC header %q, expected set? %t", got, tc.wantHeader)
+			}
+			if tc.wantHeader && got != saved.HLC.String() {
+				t.Fatalf("returned Faststatus-HLC header %q, expected %q", got, saved.HLC)
+			}
+		})
+	}
+}
+
+func TestHandlerGetHLC(t *testing.T) {
+	r := codecTestResource
+	r.HLC = faststatus.HLC{Wall: r.Since.UnixNano(), Logical: 1, Node: 4}
+	s := &rest.Server{Store: &mockStore{
+		getFn: func(faststatus.ID) (faststatus.Resource, error) {
+			return r, nil
+		},
+	}}
+	for _, accept := range []string{"text/plain", "application/json", "application/cbor"} {
+		req := httptest.NewRequest(http.MethodGet, codecTestPath, nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusOK)
+		}
+		if got := w.Header().Get("Faststatus-HLC"); got != r.HLC.String() {
+			t.Fatalf("returned Faststatus-HLC header %q for %s, expected %q", got, accept, r.HLC)
+		}
+	}
+}
+
+func TestHandlerPutSkew(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	body := func(since time.Time) string {
+		return "01234567-89ab-cdef-0123-456789abcdef busy " + since.UTC().Format(time.RFC3339Nano)
+	}
+	testCases := []struct {
+		name      string
+		skew      faststatus.SkewPolicy
+		body      string
+		header    string
+		saveErr   error
+		wantCode  int
+		wantSaved bool
+	}{
+		{"no policy", faststatus.SkewPolicy{}, body(future), "", nil, http.StatusOK, true},
+		{"since rejected", faststatus.SkewPolicy{Max: time.Minute}, body(future), "", nil, http.StatusBadRequest, false},
+		{"since clamped", faststatus.SkewPolicy{Max: time.Minute, Clamp: true}, body(future), "", nil, http.StatusOK, true},
+		{"header rejected",
+			faststatus.SkewPolicy{Max: time.Minute, Clamp: true}, body(time.Now()),
+			faststatus.HLC{Wall: future.UnixNano()}.String(),
+			nil, http.StatusBadRequest, false,
+		},
+		{"store rejected", faststatus.SkewPolicy{}, body(future), "", skewError(true), http.StatusBadRequest, true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var saved *faststatus.Resource
+			clock := faststatus.NewClock(1)
+			s := &rest.Server{
+				Store: &mockStore{
+					saveFn: func(r faststatus.Resource) error {
+						saved = &r
+						return tc.saveErr
+					},
+				},
+				Clock: clock,
+				Skew:  tc.skew,
+			}
+			req := httptest.NewRequest(http.MethodPut, codecTestPath, strings.NewReader(tc.body))
+			if tc.header != "" {
+				req.Header.Set("Faststatus-HLC", tc.header)
+			}
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, req)
+			if w.Code != tc.wantCode {
+				t.Fatalf("returned Status Code %03d, expected %03d", w.Code, tc.wantCode)
+			}
+			if (saved != nil) != tc.wantSaved {
+				t.Fatalf("saved %+v, expected saved? %t", saved, tc.wantSaved)
+			}
+			if tc.skew.Clamp && saved != nil && saved.Since.After(time.Now()) {
+				t.Fatalf("saved Since %s, expected clamped to now", saved.Since)
+			}
+			if next := clock.Now(); next.Wall > time.Now().Add(time.Minute).UnixNano() {
+				t.Fatalf("Clock pushed ahead to %v", next)
+			}
+		})
+	}
+}
+
+type skewError bool
+
+func (e skewError) Error() string {
+	return "a skew error"
+}
+
+func (e skewError) Skew() bool {
+	return bool(e)
+}
@@ -0,0 +1,129 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/internal/watch"
+)
+
+const (
+	listPath  = "/resources"
+	watchPath = "/watch"
+)
+
+// ListStore lists the most recent version of the Resources whose Labels
+// match a Selector. The listing endpoint is only available when the
+// Server's Store also implements ListStore.
+type ListStore interface {
+	List(faststatus.Selector) ([]faststatus.Resource, error)
+}
+
+// WatchStore calls a function with each Resource as it becomes the most
+// recent version. The watch endpoint is only available when the Server's
+// Store also implements WatchStore.
+type WatchStore interface {
+	Watch(func(faststatus.Resource)) (cancel func())
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) error {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	default:
+		return &restError{code: http.StatusMethodNotAllowed}
+	}
+	ls, ok := s.Store.(ListStore)
+	if !ok {
+		return &restError{
+			err:  fmt.Errorf("store does not support listing"),
+			code: http.StatusNotImplemented,
+		}
+	}
+	sel, err := selectorParam(r)
+	if err != nil {
+		return err
+	}
+	resources, err := ls.List(sel)
+	if err != nil {
+		return fmt.Errorf("listing resources from store: %w", err)
+	}
+	txt := make([]byte, 0, 80*len(resources))
+	for _, resource := range resources {
+		if txt, err = resource.AppendText(txt); err != nil {
+			return fmt.Errorf("marshaling listed resource for response: %w", err)
+		}
+		txt = append(txt, '\n')
+	}
+	w.Write(txt)
+	return nil
+}
+
+// handleWatch responds with each Resource matching the selector on its own
+// line, as it becomes the most recent version, until the client goes away.
+// A client that falls too far behind has its response ended.
+func (s *Server) handleWatch(w http.ResponseWriter, r *http.Request) error {
+	switch r.Method {
+	case http.MethodGet:
+	default:
+		return &restError{code: http.StatusMethodNotAllowed}
+	}
+	ws, ok := s.Store.(WatchStore)
+	if !ok {
+		return &restError{
+			err:  fmt.Errorf("store does not support watching"),
+			code: http.StatusNotImplemented,
+		}
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return fmt.Errorf("response writer does not support flushing")
+	}
+	sel, err := selectorParam(r)
+	if err != nil {
+		return err
+	}
+
+	changes, overflow, cancel := watch.Subscribe(ws, func(resource faststatus.Resource) bool {
+		return sel.Matches(resource.Labels)
+	})
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	txt := make([]byte, 0, 80)
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-overflow:
+			return nil
+		case resource := <-changes:
+			if txt, err = resource.AppendText(txt[:0]); err != nil {
+				return nil
+			}
+			if _, err := w.Write(append(txt, '\n')); err != nil {
+				return nil
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// selectorParam parses the selector query parameter, like
+// "?selector=floor%3D3,type!%3Dprinter". It is optional.
+func selectorParam(r *http.Request) (faststatus.Selector, error) {
+	sel, err := faststatus.ParseSelector(r.URL.Query().Get("selector"))
+	if err != nil {
+		return faststatus.Selector{}, &restError{
+			err:  fmt.Errorf("parsing selector: %w", err),
+			code: http.StatusBadRequest,
+		}
+	}
+	return sel, nil
+}
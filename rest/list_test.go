@@ -0,0 +1,194 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/rest"
+)
+
+func TestHandlerList(t *testing.T) {
+	printer, room := faststatus.NewResource(), faststatus.NewResource()
+	printer.Status, printer.Since = faststatus.Busy, time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+	printer.Labels = map[string]string{"floor": "3", "type": "printer"}
+	room.Since = time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+	room.Labels = map[string]string{"floor": "3", "type": "room"}
+	s := &rest.Server{Store: &mockListStore{resources: []faststatus.Resource{printer, room}}}
+
+	tests := []struct {
+		name     string
+		method   string
+		selector string
+		wantCode int
+		want     []faststatus.Resource
+	}{
+		{"everything", http.MethodGet, "", http.StatusOK, []faststatus.Resource{printer, room}},
+		{"selected", http.MethodGet, "floor=3,type!=printer", http.StatusOK, []faststatus.Resource{room}},
+		{"none", http.MethodGet, "floor=4", http.StatusOK, nil},
+		{"head", http.MethodHead, "", http.StatusOK, []faststatus.Resource{printer, room}},
+		{"bad selector", http.MethodGet, "floor in ()", http.StatusBadRequest, nil},
+		{"bad method", http.MethodPost, "", http.StatusMethodNotAllowed, nil},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tc.method, "/resources?selector="+url.QueryEscape(tc.selector), nil)
+			s.ServeHTTP(w, r)
+
+			if w.Code != tc.wantCode {
+				t.Fatalf("returned Status Code %03d, expected %03d", w.Code, tc.wantCode)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+			var want string
+			for _, resource := range tc.want {
+				want += resource.String() + "\n"
+			}
+			if got := w.Body.String(); got != want {
+				t.Fatalf("returned body %q, expected %q", got, want)
+			}
+		})
+	}
+}
+
+func TestHandlerListNotImplemented(t *testing.T) {
+	s := &rest.Server{Store: &mockStore{}}
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/resources", nil))
+	if w.Code != http.StatusNotImplemented {
+		t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusNotImplemented)
+	}
+}
+
+func TestHandlerWatch(t *testing.T) {
+	ms := &mockWatchStore{}
+	srv := httptest.NewServer(&rest.Server{Store: ms})
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/watch?selector="+url.QueryEscape("floor=3,type!=printer"), nil)
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		t.Fatalf("GET /watch = %+v, expected no error", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("returned Status Code %03d, expected %03d", resp.StatusCode, http.StatusOK)
+	}
+	ms.waitForWatchers(t, 1)
+
+	printer, room := faststatus.NewResource(), faststatus.NewResource()
+	printer.Labels = map[string]string{"floor": "3", "type": "printer"}
+	room.Since = time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+	room.Labels = map[string]string{"floor": "3", "type": "room"}
+	ms.notify(printer)
+	ms.notify(room)
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil {
+		t.Fatalf("reading watched line: %+v", err)
+	}
+	if want := room.String() + "\n"; line != want {
+		t.Fatalf("watched %q, expected %q", line, want)
+	}
+
+	cancel()
+	ms.waitForWatchers(t, 0)
+}
+
+func TestHandlerWatchErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		store    rest.Store
+		method   string
+		path     string
+		wantCode int
+	}{
+		{"not implemented", &mockStore{}, http.MethodGet, "/watch", http.StatusNotImplemented},
+		{"bad selector", &mockWatchStore{}, http.MethodGet, "/watch?selector=" + url.QueryEscape("!"), http.StatusBadRequest},
+		{"bad method", &mockWatchStore{}, http.MethodPut, "/watch", http.StatusMethodNotAllowed},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			(&rest.Server{Store: tc.store}).ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, strings.NewReader("")))
+			if w.Code != tc.wantCode {
+				t.Fatalf("returned Status Code %03d, expected %03d", w.Code, tc.wantCode)
+			}
+		})
+	}
+}
+
+type mockListStore struct {
+	mockStore
+	resources []faststatus.Resource
+}
+
+func (s *mockListStore) List(sel faststatus.Selector) ([]faststatus.Resource, error) {
+	var resources []faststatus.Resource
+	for _, r := range s.resources {
+		if sel.Matches(r.Labels) {
+			resources = append(resources, r)
+		}
+	}
+	return resources, nil
+}
+
+type mockWatchStore struct {
+	mockStore
+	mu       sync.Mutex
+	watchers map[int]func(faststatus.Resource)
+	next     int
+}
+
+func (s *mockWatchStore) Watch(fn func(faststatus.Resource)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watchers == nil {
+		s.watchers = make(map[int]func(faststatus.Resource))
+	}
+	id := s.next
+	s.next++
+	s.watchers[id] = fn
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.watchers, id)
+	}
+}
+
+func (s *mockWatchStore) notify(r faststatus.Resource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, fn := range s.watchers {
+		fn(r)
+	}
+}
+
+func (s *mockWatchStore) waitForWatchers(t *testing.T, n int) {
+	for i := 0; i < 100; i++ {
+		s.mu.Lock()
+		got := len(s.watchers)
+		s.mu.Unlock()
+		if got == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("never had %d watchers", n)
+}
@@ -0,0 +1,47 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+const namePrefix = "/by-name/"
+
+// namespaces are the predefined namespaces that may be used by name in a
+// path, rather than as an ID.
+var namespaces = map[string]faststatus.ID{
+	"dns": faststatus.NamespaceDNS,
+	"url": faststatus.NamespaceURL,
+}
+
+// handleName serves the Resource with the ID derived from the namespace and
+// name in the path, just as if it had been requested by ID.
+func (s *Server) handleName(w http.ResponseWriter, r *http.Request) error {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, namePrefix), "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return &restError{code: http.StatusNotFound}
+	}
+	namespace, ok := namespaces[parts[0]]
+	if !ok {
+		if err := (&namespace).UnmarshalText([]byte(parts[0])); err != nil {
+			return &restError{
+				err:  fmt.Errorf("unmarshalling namespace from path: %w", err),
+				code: http.StatusNotFound,
+			}
+		}
+	}
+	id := faststatus.NewIDFromName(namespace, parts[1])
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		return s.getResource(id).serveHTTP(w, r)
+	default:
+		return &restError{code: http.StatusMethodNotAllowed}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/rest"
+)
+
+func TestHandlerGetByName(t *testing.T) {
+	custom, _ := faststatus.NewID()
+	customB, _ := custom.MarshalText()
+
+	testCases := []struct {
+		name     string
+		path     string
+		wantCode int
+		wantID   faststatus.ID
+	}{
+		{"dns",
+			"/by-name/dns/conference-room-3b.example.com",
+			http.StatusOK,
+			faststatus.NewIDFromName(faststatus.NamespaceDNS, "conference-room-3b.example.com"),
+		},
+		{"url",
+			"/by-name/url/https://example.com/rooms/3b",
+			http.StatusOK,
+			faststatus.NewIDFromName(faststatus.NamespaceURL, "https://example.com/rooms/3b"),
+		},
+		{"custom namespace",
+			"/by-name/" + string(customB) + "/conference-room-3b",
+			http.StatusOK,
+			faststatus.NewIDFromName(custom, "conference-room-3b"),
+		},
+		{"unknown namespace", "/by-name/hostname/conference-room-3b", http.StatusNotFound, faststatus.ID{}},
+		{"no name", "/by-name/dns/", http.StatusNotFound, faststatus.ID{}},
+		{"no namespace", "/by-name/", http.StatusNotFound, faststatus.ID{}},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var gotID faststatus.ID
+			store := &mockStore{getFn: func(id faststatus.ID) (faststatus.Resource, error) {
+				gotID = id
+				return faststatus.Resource{ID: id, Status: faststatus.Busy}, nil
+			}}
+			var s = &rest.Server{Store: store}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			s.ServeHTTP(w, r)
+			if w.Code != tc.wantCode {
+				t.Fatalf("returned Status Code %03d, expected %03d", w.Code, tc.wantCode)
+			}
+			if tc.wantCode != http.StatusOK {
+				if store.getCalled != 0 {
+					t.Fatalf("Store Get called %d times, expected none", store.getCalled)
+				}
+				return
+			}
+			if gotID != tc.wantID {
+				t.Fatalf("Store Get called with %s, expected %s", gotID, tc.wantID)
+			}
+			var got faststatus.Resource
+			if err := (&got).UnmarshalText(w.Body.Bytes()); err != nil {
+				t.Fatalf("Response body failed to unmarshal to Resource: %+v", err)
+			}
+			if got.ID != tc.wantID || got.Status != faststatus.Busy {
+				t.Fatalf("Response body unmarshals to %+v, expected busy %s", got, tc.wantID)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+// OccupancyStore tracks the Occupancy of Resources and derives their Status
+// from it. The occupancy endpoints are only available when the Server's
+// Store also implements OccupancyStore.
+type OccupancyStore interface {
+	GetOccupancy(faststatus.ID) (faststatus.Occupancy, error)
+	SetOccupancy(faststatus.ID, faststatus.Occupancy, time.Time) (faststatus.Resource, error)
+	AdjustOccupancy(faststatus.ID, int, time.Time) (faststatus.Resource, error)
+}
+
+func (s *Server) handleOccupancy(id faststatus.ID, w http.ResponseWriter, r *http.Request) error {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		return s.getOccupancy(id).serveHTTP(w, r)
+	case http.MethodPut:
+		return s.putOccupancy(id).serveHTTP(w, r)
+	case http.MethodPost:
+		return s.postOccupancy(id).serveHTTP(w, r)
+	default:
+		return &restError{code: http.StatusMethodNotAllowed}
+	}
+}
+
+func (s *Server) occupancyStore() (OccupancyStore, error) {
+	occ, ok := s.Store.(OccupancyStore)
+	if !ok {
+		return nil, &restError{
+			err:  fmt.Errorf("store does not support occupancy"),
+			code: http.StatusNotImplemented,
+		}
+	}
+	return occ, nil
+}
+
+func (s *Server) getOccupancy(id faststatus.ID) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		occ, err := s.occupancyStore()
+		if err != nil {
+			return err
+		}
+		o, err := occ.GetOccupancy(id)
+		if faststatus.NotFoundError(err) {
+			return &restError{
+				err:  err,
+				code: http.StatusNotFound,
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("getting occupancy from store: %w", err)
+		}
+		ob, err := o.MarshalText()
+		if err != nil {
+			return fmt.Errorf("marshaling occupancy for response: %w", err)
+		}
+		w.Write(ob)
+		return nil
+	}
+}
+
+// putOccupancy replaces the occupancy with the count and capacity in the
+// request body, like "3/10".
+func (s *Server) putOccupancy(id faststatus.ID) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		occ, err := s.occupancyStore()
+		if err != nil {
+			return err
+		}
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return fmt.Errorf("reading from request body: %w", err)
+		}
+		var o faststatus.Occupancy
+		if err := (&o).UnmarshalText(bytes.TrimSpace(b)); err != nil {
+			return &restError{
+				err:  fmt.Errorf("unmarshaling occupancy from request: %w", err),
+				code: http.StatusBadRequest,
+			}
+		}
+		resource, err := occ.SetOccupancy(id, o, time.Now())
+		if err != nil {
+			return fmt.Errorf("setting occupancy in store: %w", err)
+		}
+		return writeResource(w, r, http.StatusOK, resource)
+	}
+}
+
+// postOccupancy adjusts the occupancy count by the signed integer in the
+// request body, like "+1" or "-2".
+func (s *Server) postOccupancy(id faststatus.ID) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		occ, err := s.occupancyStore()
+		if err != nil {
+			return err
+		}
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return fmt.Errorf("reading from request body: %w", err)
+		}
+		delta, err := strconv.Atoi(string(bytes.TrimSpace(b)))
+		if err != nil {
+			return &restError{
+				err:  fmt.Errorf("parsing occupancy delta from request: %w", err),
+				code: http.StatusBadRequest,
+			}
+		}
+		resource, err := occ.AdjustOccupancy(id, delta, time.Now())
+		if err != nil {
+			return fmt.Errorf("adjusting occupancy in store: %w", err)
+		}
+		return writeResource(w, r, http.StatusOK, resource)
+	}
+}
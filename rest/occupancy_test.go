@@ -0,0 +1,174 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/rest"
+)
+
+func TestHandlerOccupancyNotImplemented(t *testing.T) {
+	var s = &rest.Server{Store: &mockStore{}}
+
+	id, _ := faststatus.NewID()
+	idB, _ := id.MarshalText()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/"+string(idB)+"/occupancy", nil)
+	s.ServeHTTP(w, r)
+	if w.Code != http.StatusNotImplemented {
+		t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusNotImplemented)
+	}
+}
+
+func TestHandlerGetOccupancy(t *testing.T) {
+	id, _ := faststatus.NewID()
+	idB, _ := id.MarshalText()
+
+	var s = &rest.Server{Store: &mockOccupancyStore{
+		getOccupancyFn: func(got faststatus.ID) (faststatus.Occupancy, error) {
+			if got != id {
+				t.Fatalf("GetOccupancy called with %s, expected %s", got, id)
+			}
+			return faststatus.Occupancy{Count: 3, Capacity: 10}, nil
+		},
+	}}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/"+string(idB)+"/occupancy", nil)
+	s.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusOK)
+	}
+	if got := w.Body.String(); got != "3/10" {
+		t.Fatalf("responded with %q, expected %q", got, "3/10")
+	}
+}
+
+func TestHandlerGetOccupancyNotFound(t *testing.T) {
+	id, _ := faststatus.NewID()
+	idB, _ := id.MarshalText()
+
+	var s = &rest.Server{Store: &mockOccupancyStore{
+		getOccupancyFn: func(faststatus.ID) (faststatus.Occupancy, error) {
+			return faststatus.Occupancy{}, notFoundError(true)
+		},
+	}}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/"+string(idB)+"/occupancy", nil)
+	s.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandlerPutOccupancy(t *testing.T) {
+	resource := faststatus.NewResource()
+	resource.Status = faststatus.Busy
+	resource.Since = time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+	idB, _ := resource.ID.MarshalText()
+
+	testCases := []struct {
+		name          string
+		body          string
+		wantCode      int
+		wantOccupancy faststatus.Occupancy
+	}{
+		{"good request", "3/10\n", http.StatusOK, faststatus.Occupancy{Count: 3, Capacity: 10}},
+		{"bad request", "three of ten", http.StatusBadRequest, faststatus.Occupancy{}},
+		{"negative", "-3/10", http.StatusBadRequest, faststatus.Occupancy{}},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var gotOccupancy faststatus.Occupancy
+			var s = &rest.Server{Store: &mockOccupancyStore{
+				setOccupancyFn: func(id faststatus.ID, o faststatus.Occupancy, since time.Time) (faststatus.Resource, error) {
+					gotOccupancy = o
+					return resource, nil
+				},
+			}}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, "/"+string(idB)+"/occupancy", strings.NewReader(tc.body))
+			s.ServeHTTP(w, r)
+			if w.Code != tc.wantCode {
+				t.Fatalf("returned Status Code %03d, expected %03d", w.Code, tc.wantCode)
+			}
+			if gotOccupancy != tc.wantOccupancy {
+				t.Fatalf("SetOccupancy called with %+v, expected %+v", gotOccupancy, tc.wantOccupancy)
+			}
+			if tc.wantCode == http.StatusOK && w.Body.String() != resource.String() {
+				t.Fatalf("responded with %q, expected %q", w.Body.String(), resource.String())
+			}
+		})
+	}
+}
+
+func TestHandlerPostOccupancy(t *testing.T) {
+	resource := faststatus.NewResource()
+	resource.Status = faststatus.Busy
+	resource.Since = time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+	idB, _ := resource.ID.MarshalText()
+
+	testCases := []struct {
+		name      string
+		body      string
+		wantCode  int
+		wantDelta int
+	}{
+		{"increment", "+1", http.StatusOK, 1},
+		{"decrement", "-2\n", http.StatusOK, -2},
+		{"unsigned", "3", http.StatusOK, 3},
+		{"bad request", "one", http.StatusBadRequest, 0},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var gotDelta int
+			var s = &rest.Server{Store: &mockOccupancyStore{
+				adjustOccupancyFn: func(id faststatus.ID, delta int, since time.Time) (faststatus.Resource, error) {
+					gotDelta = delta
+					return resource, nil
+				},
+			}}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/"+string(idB)+"/occupancy", strings.NewReader(tc.body))
+			s.ServeHTTP(w, r)
+			if w.Code != tc.wantCode {
+				t.Fatalf("returned Status Code %03d, expected %03d", w.Code, tc.wantCode)
+			}
+			if gotDelta != tc.wantDelta {
+				t.Fatalf("AdjustOccupancy called with %d, expected %d", gotDelta, tc.wantDelta)
+			}
+		})
+	}
+}
+
+type mockOccupancyStore struct {
+	mockStore
+	getOccupancyFn    func(faststatus.ID) (faststatus.Occupancy, error)
+	setOccupancyFn    func(faststatus.ID, faststatus.Occupancy, time.Time) (faststatus.Resource, error)
+	adjustOccupancyFn func(faststatus.ID, int, time.Time) (faststatus.Resource, error)
+}
+
+func (s *mockOccupancyStore) GetOccupancy(id faststatus.ID) (faststatus.Occupancy, error) {
+	return s.getOccupancyFn(id)
+}
+
+func (s *mockOccupancyStore) SetOccupancy(id faststatus.ID, o faststatus.Occupancy, since time.Time) (faststatus.Resource, error) {
+	return s.setOccupancyFn(id, o, since)
+}
+
+func (s *mockOccupancyStore) AdjustOccupancy(id faststatus.ID, delta int, since time.Time) (faststatus.Resource, error) {
+	return s.adjustOccupancyFn(id, delta, since)
+}
@@ -4,9 +4,11 @@
 package rest
 
 import (
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/lazyengineering/faststatus"
 )
@@ -14,9 +16,26 @@ import (
 // Server is a restful http server for Resources.
 type Server struct {
 	Store Store
+	// IDs generates the IDs for new Resources and Groups. If nil,
+	// faststatus.CryptoGenerator is used.
+	IDs faststatus.IDGenerator
+	// VersionIDs generates the IDs for new Resources requested with a
+	// UUID version, like "/new?version=7", by version. Without one for the
+	// version, IDs is used if set, and the request is rejected if it
+	// generates an ID of another version; otherwise
+	// faststatus.CryptoGenerator is used for 4 and faststatus.NewIDv7 for
+	// 7.
+	VersionIDs map[int]faststatus.IDGenerator
+	// Clock stamps every saved Resource with an HLC, after any HLC the
+	// client sent. If nil, only the HLC the client sent is saved.
+	Clock *faststatus.Clock
+	// Skew limits how far ahead of the server clock the Since and HLC of a
+	// PUT Resource may be, before it reaches the Clock or the Store.
+	Skew faststatus.SkewPolicy
 }
 
-// Store gets and saves Resources.
+// Store gets and saves Resources. Get returns an error for which
+// faststatus.NotFoundError is true for a Resource that was never saved.
 type Store interface {
 	Save(faststatus.Resource) error
 	Get(faststatus.ID) (faststatus.Resource, error)
@@ -26,19 +45,39 @@ type Store interface {
 type ServerOpt func(*Server) error
 
 // ServeHTTP implements the http.Handler interface.
+// An invalid Resource in a request is rejected with 400 Bad Request and a
+// body listing each invalid field on its own line, like "Since: zero value".
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	err := s.serveHTTP(w, r)
-	if err != nil {
-		http.Error(w, http.StatusText(errorCode(err)), errorCode(err))
+	if err == nil {
+		return
+	}
+	var verr faststatus.ValidationError
+	if errors.As(err, &verr) {
+		msgs := make([]string, len(verr))
+		for i, fe := range verr {
+			msgs[i] = fe.Error()
+		}
+		http.Error(w, strings.Join(msgs, "\n"), errorCode(err))
+		return
 	}
+	http.Error(w, http.StatusText(errorCode(err)), errorCode(err))
 }
 
 func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) error {
-	switch r.URL.Path {
-	case "/":
+	switch {
+	case r.URL.Path == "/":
 		return &restError{code: http.StatusNotFound}
-	case "/new":
+	case r.URL.Path == "/new":
 		return s.handleNew(w, r)
+	case r.URL.Path == listPath:
+		return s.handleList(w, r)
+	case r.URL.Path == watchPath:
+		return s.handleWatch(w, r)
+	case strings.HasPrefix(r.URL.Path, groupsPrefix):
+		return s.handleGroups(w, r)
+	case strings.HasPrefix(r.URL.Path, namePrefix):
+		return s.handleName(w, r)
 	default:
 		return s.handleResource(w, r)
 	}
@@ -50,23 +89,78 @@ func (s *Server) handleNew(w http.ResponseWriter, r *http.Request) error {
 	default:
 		return &restError{code: http.StatusMethodNotAllowed}
 	}
-	resource := faststatus.NewResource()
-	txt, err := resource.MarshalText()
+	gen := s.ids()
+	if v := r.URL.Query().Get("version"); v != "" {
+		var err error
+		if gen, err = s.versionIDs(v); err != nil {
+			return err
+		}
+	}
+	resource, err := faststatus.NewResourceWith(gen)
 	if err != nil {
-		return fmt.Errorf("marshaling to text: %+v", err)
+		return fmt.Errorf("creating new resource: %w", err)
+	}
+	return writeResource(w, r, http.StatusOK, resource)
+}
+
+func (s *Server) ids() faststatus.IDGenerator {
+	if s.IDs == nil {
+		return faststatus.CryptoGenerator
+	}
+	return s.IDs
+}
+
+// versionIDs returns the generator for the UUID version in txt.
+func (s *Server) versionIDs(txt string) (faststatus.IDGenerator, error) {
+	v, err := strconv.Atoi(txt)
+	if gen, ok := s.VersionIDs[v]; err == nil && ok {
+		return gen, nil
+	}
+	switch {
+	case err != nil || v != 4 && v != 7:
+		return nil, &restError{
+			err:  fmt.Errorf("unsupported ID version %q", txt),
+			code: http.StatusBadRequest,
+		}
+	case s.IDs != nil:
+		return faststatus.IDGeneratorFunc(func() (faststatus.ID, error) {
+			id, err := s.IDs.NewID()
+			if err == nil && id.Version() != v {
+				return faststatus.ID{}, &restError{
+					err:  fmt.Errorf("ID version %d is not available from the configured generator", v),
+					code: http.StatusBadRequest,
+				}
+			}
+			return id, err
+		}), nil
+	case v == 4:
+		return faststatus.CryptoGenerator, nil
+	default:
+		return faststatus.IDGeneratorFunc(faststatus.NewIDv7), nil
 	}
-	w.Write(txt)
-	return nil
 }
 
 func (s *Server) handleResource(w http.ResponseWriter, r *http.Request) error {
+	parts := strings.SplitN(r.URL.Path[1:], "/", 2)
 	var id faststatus.ID
-	if err := (&id).UnmarshalText([]byte(r.URL.Path[1:])); err != nil {
+	if err := (&id).UnmarshalText([]byte(parts[0])); err != nil {
 		return &restError{
-			err:  fmt.Errorf("unmarshalling id from path: %+v", err),
+			err:  fmt.Errorf("unmarshalling id from path: %w", err),
 			code: http.StatusNotFound,
 		}
 	}
+	if len(parts) == 2 {
+		switch parts[1] {
+		case "occupancy":
+			return s.handleOccupancy(id, w, r)
+		case "schedule":
+			return s.handleSchedule(id, w, r)
+		case "heartbeat":
+			return s.handleHeartbeat(id, w, r)
+		default:
+			return &restError{code: http.StatusNotFound}
+		}
+	}
 	switch r.Method {
 	case http.MethodGet, http.MethodHead:
 		return s.getResource(id).serveHTTP(w, r)
@@ -79,61 +173,56 @@ func (s *Server) handleResource(w http.ResponseWriter, r *http.Request) error {
 
 func (s *Server) putResource(id faststatus.ID) handlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
-		b, err := ioutil.ReadAll(r.Body)
+		resource, err := readResource(r)
 		if err != nil {
-			return fmt.Errorf("reading from request body: %+v", err)
+			return err
 		}
-		resource := new(faststatus.Resource)
-		if err := resource.UnmarshalText(b); err != nil {
-			return &restError{
-				err:  fmt.Errorf("unmarshaling resource from request: %+v", err),
-				code: http.StatusBadRequest,
-			}
+		if err := validate(id, resource, s.Skew); err != nil {
+			return err
 		}
-		if resource.Since.IsZero() {
-			return &restError{
-				err:  fmt.Errorf("zero-value Since"),
-				code: http.StatusBadRequest,
-			}
+		if resource, err = s.stamp(r, resource); err != nil {
+			return err
 		}
-		if id != resource.ID {
-			return &restError{
-				err:  fmt.Errorf("resource ID %q does not match path ID %q", resource.ID, id),
-				code: http.StatusBadRequest,
+		save := s.Store.Save
+		if r.URL.Query().Get("ttl") != "" {
+			es, err := s.expiryStore()
+			if err != nil {
+				return err
+			}
+			ttl, fallback, err := expiryParams(r)
+			if err != nil {
+				return err
+			}
+			save = func(resource faststatus.Resource) error {
+				return es.SaveWithTTL(resource, ttl, fallback)
 			}
 		}
-		if err := s.Store.Save(*resource); faststatus.ConflictError(err) {
+		if err := save(resource); faststatus.ConflictError(err) {
 			return &restError{
 				err:  err,
 				code: http.StatusConflict,
 			}
+		} else if faststatus.SkewError(err) {
+			return &restError{
+				err:  err,
+				code: http.StatusBadRequest,
+			}
 		} else if err != nil {
-			return fmt.Errorf("saving resource to store: %+v", err)
+			return fmt.Errorf("saving resource to store: %w", err)
 		}
-		rb, err := resource.MarshalText()
-		if err != nil {
-			return fmt.Errorf("marshaling resource for response: %+v", err)
-		}
-		w.Write(rb)
-		return nil
+		return writeResource(w, r, http.StatusOK, resource)
 	}
 }
 
 func (s *Server) getResource(id faststatus.ID) handlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
 		resource, err := s.Store.Get(id)
-		if err != nil {
-			return fmt.Errorf("getting resource from store: %+v", err)
-		}
-		if resource.Equal(faststatus.Resource{}) {
-			return &restError{code: http.StatusNotFound}
-		}
-		rb, err := resource.MarshalText()
-		if err != nil {
-			return fmt.Errorf("marshaling resource for response: %+v", err)
+		if faststatus.NotFoundError(err) {
+			resource = faststatus.Resource{ID: id, Status: faststatus.Unknown}
+		} else if err != nil {
+			return fmt.Errorf("getting resource from store: %w", err)
 		}
-		w.Write(rb)
-		return nil
+		return writeResource(w, r, http.StatusOK, resource)
 	}
 }
 
@@ -153,6 +153,155 @@ func TestHandlerGetNew(t *testing.T) {
 	}
 }
 
+func TestHandlerGetNewVersion(t *testing.T) {
+	var s = &rest.Server{}
+	testCases := []struct {
+		name        string
+		query       string
+		wantCode    int
+		wantVersion int
+	}{
+		{"default", "", http.StatusOK, 4},
+		{"version 4", "?version=4", http.StatusOK, 4},
+		{"version 7", "?version=7", http.StatusOK, 7},
+		{"unsupported version", "?version=1", http.StatusBadRequest, 0},
+		{"bad version", "?version=latest", http.StatusBadRequest, 0},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/new"+tc.query, nil)
+			s.ServeHTTP(w, r)
+			if w.Code != tc.wantCode {
+				t.Fatalf("returned Status Code %03d, expected %03d", w.Code, tc.wantCode)
+			}
+			if tc.wantCode != http.StatusOK {
+				return
+			}
+			var got faststatus.Resource
+			if err := (&got).UnmarshalText(w.Body.Bytes()); err != nil {
+				t.Fatalf("Response body failed to unmarshal to Resource: %+v", err)
+			}
+			if v := got.ID.Version(); v != tc.wantVersion {
+				t.Fatalf("ID version %d, expected %d", v, tc.wantVersion)
+			}
+		})
+	}
+}
+
+func TestHandlerGetNewGenerator(t *testing.T) {
+	t.Run("configured generator", func(t *testing.T) {
+		var s = &rest.Server{IDs: faststatus.NewSequentialGenerator(faststatus.ID{})}
+		for _, want := range []faststatus.ID{{15: 0x01}, {15: 0x02}} {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/new", nil)
+			s.ServeHTTP(w, r)
+			if w.Code != http.StatusOK {
+				t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusOK)
+			}
+			var got faststatus.Resource
+			if err := (&got).UnmarshalText(w.Body.Bytes()); err != nil {
+				t.Fatalf("Response body failed to unmarshal to Resource: %+v", err)
+			}
+			if got.ID != want {
+				t.Fatalf("new resource ID %x, expected %x", got.ID, want)
+			}
+		}
+	})
+
+	t.Run("configured generator with version", func(t *testing.T) {
+		var s = &rest.Server{IDs: faststatus.NewSequentialGenerator(faststatus.ID{})}
+		for _, query := range []string{"?version=4", "?version=7"} {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/new"+query, nil)
+			s.ServeHTTP(w, r)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("GET /new%s returned Status Code %03d, expected %03d", query, w.Code, http.StatusBadRequest)
+			}
+		}
+	})
+
+	t.Run("configured generator of the version", func(t *testing.T) {
+		var s = &rest.Server{IDs: faststatus.CryptoGenerator}
+		for _, tc := range []struct {
+			query    string
+			wantCode int
+		}{
+			{"?version=4", http.StatusOK},
+			{"?version=7", http.StatusBadRequest},
+		} {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/new"+tc.query, nil)
+			s.ServeHTTP(w, r)
+			if w.Code != tc.wantCode {
+				t.Fatalf("GET /new%s returned Status Code %03d, expected %03d", tc.query, w.Code, tc.wantCode)
+			}
+		}
+	})
+
+	t.Run("configured version generator", func(t *testing.T) {
+		var s = &rest.Server{
+			IDs:        faststatus.CryptoGenerator,
+			VersionIDs: map[int]faststatus.IDGenerator{7: faststatus.NewSequentialGenerator(faststatus.ID{})},
+		}
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/new?version=7", nil)
+		s.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusOK)
+		}
+		var got faststatus.Resource
+		if err := (&got).UnmarshalText(w.Body.Bytes()); err != nil {
+			t.Fatalf("Response body failed to unmarshal to Resource: %+v", err)
+		}
+		if want := (faststatus.ID{15: 0x01}); got.ID != want {
+			t.Fatalf("new resource ID %x, expected %x", got.ID, want)
+		}
+	})
+
+	t.Run("generator error", func(t *testing.T) {
+		var s = &rest.Server{IDs: faststatus.IDGeneratorFunc(func() (faststatus.ID, error) {
+			return faststatus.ID{}, fmt.Errorf("an error")
+		})}
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/new", nil)
+		s.ServeHTTP(w, r)
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusInternalServerError)
+		}
+	})
+}
+
+func TestHandlerIDForms(t *testing.T) {
+	id, _ := faststatus.NewID()
+	idB, _ := id.MarshalText()
+	for _, form := range []string{
+		string(idB),
+		"urn:uuid:" + string(idB),
+		"%7B" + string(idB) + "%7D",
+		id.ShortString(),
+	} {
+		form := form
+		t.Run(form, func(t *testing.T) {
+			var gotID faststatus.ID
+			var s = &rest.Server{Store: &mockStore{getFn: func(id faststatus.ID) (faststatus.Resource, error) {
+				gotID = id
+				return faststatus.Resource{}, notFoundError(true)
+			}}}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/"+form, nil)
+			s.ServeHTTP(w, r)
+			if w.Code != http.StatusOK {
+				t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusOK)
+			}
+			if gotID != id {
+				t.Fatalf("Store Get called with %s, expected %s", gotID, id)
+			}
+		})
+	}
+}
+
 func TestHandlerPutToID(t *testing.T) {
 	//TODO(jesse@jessecarl.com): Content negotiation. For now, everything is text/plain.
 	t.Run("bad requests", func(t *testing.T) {
@@ -346,7 +495,7 @@ func TestHandlerGetFromID(t *testing.T) {
 
 	t.Run("store get not found", func(t *testing.T) {
 		store := &mockStore{getFn: func(faststatus.ID) (faststatus.Resource, error) {
-			return faststatus.Resource{}, nil
+			return faststatus.Resource{}, notFoundError(true)
 		}}
 		var s = &rest.Server{Store: store}
 
@@ -356,12 +505,19 @@ func TestHandlerGetFromID(t *testing.T) {
 		w := httptest.NewRecorder()
 		r := httptest.NewRequest(http.MethodGet, "/"+string(idB), nil)
 		s.ServeHTTP(w, r)
-		if w.Code != http.StatusNotFound {
-			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusNotFound)
+		if w.Code != http.StatusOK {
+			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusOK)
 		}
 		if store.getCalled != 1 {
 			t.Fatalf("Store Get called %d times, expected exactly once", store.getCalled)
 		}
+		var got faststatus.Resource
+		if err := (&got).UnmarshalText(w.Body.Bytes()); err != nil {
+			t.Fatalf("Response body failed to unmarshal to Resource: %+v", err)
+		}
+		if want := (faststatus.Resource{ID: id, Status: faststatus.Unknown}); !got.Equal(want) {
+			t.Fatalf("Response body unmarshals to %+v, expected %+v", got, want)
+		}
 	})
 
 	t.Run("store get resource", func(t *testing.T) {
@@ -425,6 +581,41 @@ func validMethodsByPath(path string) ([]string, bool) {
 	if path == "/new" {
 		return []string{http.MethodGet, http.MethodHead}, true
 	}
+	if path == "/resources" {
+		return []string{http.MethodGet, http.MethodHead}, true
+	}
+	if path == "/watch" {
+		return []string{http.MethodGet}, true
+	}
+	if path == "/groups/" {
+		return []string{http.MethodPost}, true
+	}
+	if strings.HasPrefix(path, "/groups/") {
+		parts := strings.SplitN(strings.TrimPrefix(path, "/groups/"), "/", 2)
+		id := new(faststatus.ID)
+		if err := id.UnmarshalText([]byte(parts[0])); err != nil {
+			return nil, false
+		}
+		if len(parts) == 2 {
+			if parts[1] != "status" {
+				return nil, false
+			}
+			return []string{http.MethodGet, http.MethodHead}, true
+		}
+		return []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete}, true
+	}
+	if strings.HasPrefix(path, "/by-name/") {
+		parts := strings.SplitN(strings.TrimPrefix(path, "/by-name/"), "/", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, false
+		}
+		if parts[0] != "dns" && parts[0] != "url" {
+			if err := new(faststatus.ID).UnmarshalText([]byte(parts[0])); err != nil {
+				return nil, false
+			}
+		}
+		return []string{http.MethodGet, http.MethodHead}, true
+	}
 	parts := strings.SplitN(path, "/", 3)
 	if len(parts) < 2 {
 		return nil, false
@@ -434,21 +625,63 @@ func validMethodsByPath(path string) ([]string, bool) {
 	if err != nil {
 		return nil, false
 	}
-	methods := []string{http.MethodGet, http.MethodHead}
-	if len(parts) < 3 { // no trailing slash (for now)
-		methods = append(methods, http.MethodPut)
+	if len(parts) == 3 {
+		switch parts[2] {
+		case "occupancy":
+			return []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPost}, true
+		case "schedule":
+			return []string{http.MethodGet, http.MethodHead, http.MethodPost}, true
+		case "heartbeat":
+			return []string{http.MethodPost}, true
+		default:
+			return nil, false
+		}
 	}
-	return methods, true
+	return []string{http.MethodGet, http.MethodHead, http.MethodPut}, true
 }
 
 func genValidPath(r *rand.Rand) string {
 	pathFuncs := []func() string{
 		func() string { return "/new" },
+		func() string { return "/resources" },
+		func() string { return "/watch" },
 		func() string { // base ID
 			id, _ := faststatus.NewID()
 			b, _ := id.MarshalText()
 			return "/" + string(b)
 		},
+		func() string { // resource occupancy
+			id, _ := faststatus.NewID()
+			b, _ := id.MarshalText()
+			return "/" + string(b) + "/occupancy"
+		},
+		func() string { // resource schedule
+			id, _ := faststatus.NewID()
+			b, _ := id.MarshalText()
+			return "/" + string(b) + "/schedule"
+		},
+		func() string { // resource heartbeat
+			id, _ := faststatus.NewID()
+			b, _ := id.MarshalText()
+			return "/" + string(b) + "/heartbeat"
+		},
+		func() string { return "/groups/" },
+		func() string { // group ID
+			id, _ := faststatus.NewID()
+			b, _ := id.MarshalText()
+			return "/groups/" + string(b)
+		},
+		func() string { // group status
+			id, _ := faststatus.NewID()
+			b, _ := id.MarshalText()
+			return "/groups/" + string(b) + "/status"
+		},
+		func() string { return "/by-name/dns/conference-room-3b.example.com" },
+		func() string { // custom namespace
+			id, _ := faststatus.NewID()
+			b, _ := id.MarshalText()
+			return "/by-name/" + string(b) + "/conference-room-3b"
+		},
 	}
 	return pathFuncs[r.Intn(len(pathFuncs))]()
 }
@@ -528,6 +761,16 @@ func (s *mockStore) Get(id faststatus.ID) (faststatus.Resource, error) {
 	return s.getFn(id)
 }
 
+type notFoundError bool
+
+func (e notFoundError) Error() string {
+	return "a not-found error"
+}
+
+func (e notFoundError) NotFound() bool {
+	return bool(e)
+}
+
 type conflictError bool
 
 func (e conflictError) Error() string {
@@ -537,3 +780,20 @@ func (e conflictError) Error() string {
 func (e conflictError) Conflict() bool {
 	return bool(e)
 }
+
+func TestHandlerPutInvalidFields(t *testing.T) {
+	s := &rest.Server{Store: &mockStore{saveFn: func(faststatus.Resource) error {
+		t.Fatalf("Store Save called with an invalid resource")
+		return nil
+	}}}
+	body := "fedcba98-7654-3210-fedc-ba9876543210 busy 0001-01-01T00:00:00Z"
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/01234567-89ab-cdef-0123-456789abcdef", strings.NewReader(body)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusBadRequest)
+	}
+	want := "Since: zero value\nID: does not match path ID 01234567-89ab-cdef-0123-456789abcdef\n"
+	if got := w.Body.String(); got != want {
+		t.Fatalf("returned body %q, expected %q", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+// ScheduleStore saves and lists future versions of Resources. The schedule
+// endpoints are only available when the Server's Store also implements
+// ScheduleStore.
+type ScheduleStore interface {
+	Schedule(faststatus.Resource) error
+	Scheduled(faststatus.ID) ([]faststatus.Resource, error)
+}
+
+func (s *Server) handleSchedule(id faststatus.ID, w http.ResponseWriter, r *http.Request) error {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		return s.getSchedule(id).serveHTTP(w, r)
+	case http.MethodPost:
+		return s.postSchedule(id).serveHTTP(w, r)
+	default:
+		return &restError{code: http.StatusMethodNotAllowed}
+	}
+}
+
+func (s *Server) scheduleStore() (ScheduleStore, error) {
+	ss, ok := s.Store.(ScheduleStore)
+	if !ok {
+		return nil, &restError{
+			err:  fmt.Errorf("store does not support schedules"),
+			code: http.StatusNotImplemented,
+		}
+	}
+	return ss, nil
+}
+
+// getSchedule responds with each pending change on its own line, in the
+// order they will take effect.
+func (s *Server) getSchedule(id faststatus.ID) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ss, err := s.scheduleStore()
+		if err != nil {
+			return err
+		}
+		scheduled, err := ss.Scheduled(id)
+		if err != nil {
+			return fmt.Errorf("getting schedule from store: %w", err)
+		}
+		txt := make([]byte, 0, 80*len(scheduled))
+		for _, resource := range scheduled {
+			rb, err := resource.MarshalText()
+			if err != nil {
+				return fmt.Errorf("marshaling scheduled resource for response: %w", err)
+			}
+			txt = append(append(txt, rb...), '\n')
+		}
+		w.Write(txt)
+		return nil
+	}
+}
+
+// postSchedule adds the resource in the request body to the schedule, to
+// take effect at its Since time.
+func (s *Server) postSchedule(id faststatus.ID) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ss, err := s.scheduleStore()
+		if err != nil {
+			return err
+		}
+		resource, err := readResource(r)
+		if err != nil {
+			return err
+		}
+		// a scheduled Since is meant to be ahead of the clock
+		if err := validate(id, resource, faststatus.SkewPolicy{}); err != nil {
+			return err
+		}
+		if err := ss.Schedule(resource); faststatus.ConflictError(err) {
+			return &restError{
+				err:  err,
+				code: http.StatusConflict,
+			}
+		} else if err != nil {
+			return fmt.Errorf("saving scheduled resource to store: %w", err)
+		}
+		return writeResource(w, r, http.StatusCreated, resource)
+	}
+}
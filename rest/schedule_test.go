@@ -0,0 +1,108 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rest_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/rest"
+)
+
+func TestHandlerGetSchedule(t *testing.T) {
+	id, _ := faststatus.NewID()
+	idB, _ := id.MarshalText()
+	scheduled := []faststatus.Resource{
+		{ID: id, Status: faststatus.Occupied, Since: time.Date(2017, 3, 14, 14, 0, 0, 0, time.UTC)},
+		{ID: id, Status: faststatus.Free, Since: time.Date(2017, 3, 14, 15, 0, 0, 0, time.UTC)},
+	}
+
+	var s = &rest.Server{Store: &mockScheduleStore{
+		scheduledFn: func(faststatus.ID) ([]faststatus.Resource, error) {
+			return scheduled, nil
+		},
+	}}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/"+string(idB)+"/schedule", nil)
+	s.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusOK)
+	}
+	want := scheduled[0].String() + "\n" + scheduled[1].String() + "\n"
+	if got := w.Body.String(); got != want {
+		t.Fatalf("responded with %q, expected %q", got, want)
+	}
+}
+
+func TestHandlerPostSchedule(t *testing.T) {
+	resource := faststatus.NewResource()
+	resource.Status = faststatus.Occupied
+	resource.Since = time.Now().Add(time.Hour)
+	idB, _ := resource.ID.MarshalText()
+	body, _ := resource.MarshalText()
+
+	t.Run("good request", func(t *testing.T) {
+		var got faststatus.Resource
+		var s = &rest.Server{Store: &mockScheduleStore{
+			scheduleFn: func(r faststatus.Resource) error {
+				got = r
+				return nil
+			},
+		}}
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/"+string(idB)+"/schedule", bytes.NewReader(body))
+		s.ServeHTTP(w, r)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusCreated)
+		}
+		if !got.Equal(resource) {
+			t.Fatalf("Schedule called with %+v, expected %+v", got, resource)
+		}
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		var s = &rest.Server{Store: &mockScheduleStore{
+			scheduleFn: func(faststatus.Resource) error {
+				return conflictError(true)
+			},
+		}}
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/"+string(idB)+"/schedule", bytes.NewReader(body))
+		s.ServeHTTP(w, r)
+		if w.Code != http.StatusConflict {
+			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusConflict)
+		}
+	})
+
+	t.Run("id does not match", func(t *testing.T) {
+		var s = &rest.Server{Store: &mockScheduleStore{}}
+		other, _ := faststatus.NewID()
+		otherB, _ := other.MarshalText()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/"+string(otherB)+"/schedule", bytes.NewReader(body))
+		s.ServeHTTP(w, r)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("returned Status Code %03d, expected %03d", w.Code, http.StatusBadRequest)
+		}
+	})
+}
+
+type mockScheduleStore struct {
+	mockStore
+	scheduleFn  func(faststatus.Resource) error
+	scheduledFn func(faststatus.ID) ([]faststatus.Resource, error)
+}
+
+func (s *mockScheduleStore) Schedule(r faststatus.Resource) error {
+	return s.scheduleFn(r)
+}
+
+func (s *mockScheduleStore) Scheduled(id faststatus.ID) ([]faststatus.Resource, error) {
+	return s.scheduledFn(id)
+}
@@ -0,0 +1,226 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+// Package rpc serves Resources over gRPC, as the FastStatus service defined
+// in the faststatuspb package.
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/faststatuspb"
+	"github.com/lazyengineering/faststatus/internal/watch"
+	"github.com/lazyengineering/faststatus/rest"
+)
+
+// Server is a gRPC server for Resources. Register it with
+// faststatuspb.RegisterFastStatusServer.
+type Server struct {
+	faststatuspb.UnimplementedFastStatusServer
+	Store rest.Store
+}
+
+// Get returns the most recent version of a Resource. A Resource that has
+// never been saved is returned with the Unknown Status.
+func (s *Server) Get(ctx context.Context, req *faststatuspb.GetRequest) (*faststatuspb.Resource, error) {
+	id, err := requestID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	r, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	return faststatuspb.ToProto(r), nil
+}
+
+// Save stores a Resource, unless a more recent version is already stored.
+func (s *Server) Save(ctx context.Context, req *faststatuspb.SaveRequest) (*faststatuspb.Resource, error) {
+	r, err := faststatuspb.FromProto(req.GetResource())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "converting resource from request: %+v", err)
+	}
+	if err := r.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+	if err := s.Store.Save(r); err != nil {
+		return nil, storeError("saving resource to store", err)
+	}
+	return faststatuspb.ToProto(r), nil
+}
+
+// BatchGet returns the most recent version of each of several Resources,
+// in the order requested.
+func (s *Server) BatchGet(ctx context.Context, req *faststatuspb.BatchGetRequest) (*faststatuspb.BatchGetResponse, error) {
+	resp := &faststatuspb.BatchGetResponse{
+		Resources: make([]*faststatuspb.Resource, 0, len(req.GetIds())),
+	}
+	for _, pid := range req.GetIds() {
+		id, err := requestID(pid)
+		if err != nil {
+			return nil, err
+		}
+		r, err := s.get(id)
+		if err != nil {
+			return nil, err
+		}
+		resp.Resources = append(resp.Resources, faststatuspb.ToProto(r))
+	}
+	return resp, nil
+}
+
+// List returns the most recent version of every Resource matching the
+// label selector. It is only available when the Store also implements
+// rest.ListStore.
+func (s *Server) List(ctx context.Context, req *faststatuspb.ListRequest) (*faststatuspb.ListResponse, error) {
+	ls, ok := s.Store.(rest.ListStore)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "store does not support listing")
+	}
+	sel, err := requestSelector(req.GetSelector())
+	if err != nil {
+		return nil, err
+	}
+	resources, err := ls.List(sel)
+	if err != nil {
+		return nil, storeError("listing resources from store", err)
+	}
+	resp := &faststatuspb.ListResponse{
+		Resources: make([]*faststatuspb.Resource, 0, len(resources)),
+	}
+	for _, r := range resources {
+		resp.Resources = append(resp.Resources, faststatuspb.ToProto(r))
+	}
+	return resp, nil
+}
+
+// Watch streams each Resource as it becomes the most recent version, until
+// the client cancels. A client that falls too far behind has its stream
+// ended with ResourceExhausted. It is only available when the Server's
+// Store also implements rest.WatchStore.
+func (s *Server) Watch(req *faststatuspb.WatchRequest, stream faststatuspb.FastStatus_WatchServer) error {
+	ws, ok := s.Store.(rest.WatchStore)
+	if !ok {
+		return status.Error(codes.Unimplemented, "store does not support watching")
+	}
+	sel, err := requestSelector(req.GetSelector())
+	if err != nil {
+		return err
+	}
+	var ids map[faststatus.ID]struct{}
+	for _, pid := range req.GetIds() {
+		id, err := requestID(pid)
+		if err != nil {
+			return err
+		}
+		if ids == nil {
+			ids = make(map[faststatus.ID]struct{})
+		}
+		ids[id] = struct{}{}
+	}
+
+	changes, overflow, cancel := watch.Subscribe(ws, func(r faststatus.Resource) bool {
+		if _, ok := ids[r.ID]; ids != nil && !ok {
+			return false
+		}
+		return sel.Matches(r.Labels)
+	})
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-overflow:
+			return status.Error(codes.ResourceExhausted, "watch fell too far behind")
+		case r := <-changes:
+			if err := stream.Send(faststatuspb.ToProto(r)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) get(id faststatus.ID) (faststatus.Resource, error) {
+	r, err := s.Store.Get(id)
+	if faststatus.NotFoundError(err) {
+		return faststatus.Resource{ID: id, Status: faststatus.Unknown}, nil
+	}
+	if err != nil {
+		return faststatus.Resource{}, storeError("getting resource from store", err)
+	}
+	return r, nil
+}
+
+func requestID(pid *faststatuspb.ID) (faststatus.ID, error) {
+	id, err := faststatuspb.IDFromProto(pid)
+	if err != nil {
+		return faststatus.ID{}, status.Errorf(codes.InvalidArgument, "converting ID from request: %+v", err)
+	}
+	if id == (faststatus.ID{}) {
+		return faststatus.ID{}, status.Error(codes.InvalidArgument, "zero-value ID")
+	}
+	return id, nil
+}
+
+func requestSelector(s string) (faststatus.Selector, error) {
+	sel, err := faststatus.ParseSelector(s)
+	if err != nil {
+		return faststatus.Selector{}, status.Errorf(codes.InvalidArgument, "parsing selector from request: %+v", err)
+	}
+	return sel, nil
+}
+
+// validationError maps an error from Resource.Validate to an
+// InvalidArgument status error, with a BadRequest detail listing each
+// invalid field.
+func validationError(err error) error {
+	st := status.Newf(codes.InvalidArgument, "validating resource from request: %v", err)
+	var verr faststatus.ValidationError
+	if !errors.As(err, &verr) {
+		return st.Err()
+	}
+	br := &errdetails.BadRequest{}
+	for _, fe := range verr {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fe.Field,
+			Description: fe.Err.Error(),
+		})
+	}
+	if withDetails, err := st.WithDetails(br); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// storeError maps an error from the Store to a gRPC status error: Aborted
+// for conflicts, InvalidArgument for zero-value data or times too far
+// ahead, and Internal otherwise. A ValidationError, like one for a Since
+// too far ahead of the Store clock, keeps its field details.
+func storeError(msg string, err error) error {
+	type zeroValuer interface {
+		ZeroValue() bool
+	}
+	var verr faststatus.ValidationError
+	if errors.As(err, &verr) {
+		return validationError(err)
+	}
+	code := codes.Internal
+	var zv zeroValuer
+	if errors.Is(err, faststatus.ErrZeroValue) || errors.As(err, &zv) && zv.ZeroValue() {
+		code = codes.InvalidArgument
+	}
+	if faststatus.SkewError(err) {
+		code = codes.InvalidArgument
+	}
+	if faststatus.ConflictError(err) {
+		code = codes.Aborted
+	}
+	return status.Errorf(code, "%s: %+v", msg, err)
+}
@@ -0,0 +1,511 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package rpc_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/faststatuspb"
+	"github.com/lazyengineering/faststatus/rest"
+	"github.com/lazyengineering/faststatus/rpc"
+	"github.com/lazyengineering/faststatus/store"
+)
+
+var _ rest.WatchStore = (*store.Store)(nil)
+
+// dial serves the Server in-process and returns a client connected to it.
+func dial(t *testing.T, s *rpc.Server) faststatuspb.FastStatusClient {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	faststatuspb.RegisterFastStatusServer(srv, s)
+	go srv.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dialing in-process server: %+v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		srv.Stop()
+	})
+	return faststatuspb.NewFastStatusClient(conn)
+}
+
+func TestGet(t *testing.T) {
+	id, _ := faststatus.NewID()
+	found := faststatus.Resource{ID: id, Status: faststatus.Busy, Since: time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)}
+
+	testCases := []struct {
+		name         string
+		id           faststatus.ID
+		getFn        func(faststatus.ID) (faststatus.Resource, error)
+		wantCode     codes.Code
+		wantResource faststatus.Resource
+	}{
+		{"zero id", faststatus.ID{}, nil, codes.InvalidArgument, faststatus.Resource{}},
+		{"store error",
+			id,
+			func(faststatus.ID) (faststatus.Resource, error) { return faststatus.Resource{}, fmt.Errorf("an error") },
+			codes.Internal,
+			faststatus.Resource{},
+		},
+		{"not found",
+			id,
+			func(faststatus.ID) (faststatus.Resource, error) { return faststatus.Resource{}, notFoundError(true) },
+			codes.OK,
+			faststatus.Resource{ID: id, Status: faststatus.Unknown},
+		},
+		{"found",
+			id,
+			func(faststatus.ID) (faststatus.Resource, error) { return found, nil },
+			codes.OK,
+			found,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := dial(t, &rpc.Server{Store: &mockStore{getFn: tc.getFn}})
+			resp, err := client.Get(context.Background(), &faststatuspb.GetRequest{Id: faststatuspb.IDToProto(tc.id)})
+			if code := status.Code(err); code != tc.wantCode {
+				t.Fatalf("Get() = %+v, expected code %s", err, tc.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			got, err := faststatuspb.FromProto(resp)
+			if err != nil {
+				t.Fatalf("FromProto() = %+v, expected no error", err)
+			}
+			if !got.Equal(tc.wantResource) {
+				t.Fatalf("Get() = %+v, expected %+v", got, tc.wantResource)
+			}
+		})
+	}
+}
+
+func TestSave(t *testing.T) {
+	id, _ := faststatus.NewID()
+	since := time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		resource faststatus.Resource
+		saveErr  error
+		wantCode codes.Code
+		wantSave bool
+	}{
+		{"zero id", faststatus.Resource{Status: faststatus.Busy, Since: since}, nil, codes.InvalidArgument, false},
+		{"zero since", faststatus.Resource{ID: id, Status: faststatus.Busy}, nil, codes.InvalidArgument, false},
+		{"conflict", faststatus.Resource{ID: id, Status: faststatus.Busy, Since: since}, conflictError(true), codes.Aborted, true},
+		{"zero value", faststatus.Resource{ID: id, Status: faststatus.Busy, Since: since}, zeroValueError(true), codes.InvalidArgument, true},
+		{"skew", faststatus.Resource{ID: id, Status: faststatus.Busy, Since: since}, skewError(true), codes.InvalidArgument, true},
+		{"store error", faststatus.Resource{ID: id, Status: faststatus.Busy, Since: since}, fmt.Errorf("an error"), codes.Internal, true},
+		{"saved", faststatus.Resource{ID: id, Status: faststatus.Busy, Since: since}, nil, codes.OK, true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var saved faststatus.Resource
+			ms := &mockStore{saveFn: func(r faststatus.Resource) error {
+				saved = r
+				return tc.saveErr
+			}}
+			client := dial(t, &rpc.Server{Store: ms})
+			resp, err := client.Save(context.Background(), &faststatuspb.SaveRequest{Resource: faststatuspb.ToProto(tc.resource)})
+			if code := status.Code(err); code != tc.wantCode {
+				t.Fatalf("Save() = %+v, expected code %s", err, tc.wantCode)
+			}
+			if called := ms.saveCalled == 1; called != tc.wantSave {
+				t.Fatalf("Store Save called %d times, expected called? %t", ms.saveCalled, tc.wantSave)
+			}
+			if err != nil {
+				return
+			}
+			if !saved.Equal(tc.resource) {
+				t.Fatalf("saved %+v, expected %+v", saved, tc.resource)
+			}
+			if got, _ := faststatuspb.FromProto(resp); !got.Equal(tc.resource) {
+				t.Fatalf("Save() = %+v, expected %+v", got, tc.resource)
+			}
+		})
+	}
+}
+
+func TestSaveFieldViolations(t *testing.T) {
+	id, _ := faststatus.NewID()
+	since := time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		resource   *faststatuspb.Resource
+		saveErr    error
+		wantFields []string
+	}{
+		{"request", &faststatuspb.Resource{}, nil, []string{"ID", "Since"}},
+		{"message and until",
+			faststatuspb.ToProto(faststatus.Resource{ID: id, Since: since, Message: "back\x00soon", Until: since.Add(-time.Hour)}),
+			nil,
+			[]string{"Message", "Until"},
+		},
+		{"store",
+			faststatuspb.ToProto(faststatus.Resource{ID: id, Status: faststatus.Busy, Since: since}),
+			faststatus.ValidationError{{Field: "Since", Err: skewError(true)}},
+			[]string{"Since"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := dial(t, &rpc.Server{Store: &mockStore{saveFn: func(faststatus.Resource) error {
+				return tc.saveErr
+			}}})
+			_, err := client.Save(context.Background(), &faststatuspb.SaveRequest{Resource: tc.resource})
+			st := status.Convert(err)
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("Save() = %+v, expected code %s", err, codes.InvalidArgument)
+			}
+			var fields []string
+			for _, d := range st.Details() {
+				if br, ok := d.(*errdetails.BadRequest); ok {
+					for _, v := range br.GetFieldViolations() {
+						fields = append(fields, v.GetField())
+					}
+				}
+			}
+			if strings.Join(fields, ",") != strings.Join(tc.wantFields, ",") {
+				t.Fatalf("Save() field violations = %v, expected %v", fields, tc.wantFields)
+			}
+		})
+	}
+}
+
+func TestBatchGet(t *testing.T) {
+	resources := map[faststatus.ID]faststatus.Resource{}
+	var ids []*faststatuspb.ID
+	var want []faststatus.Resource
+	for _, s := range []faststatus.Status{faststatus.Occupied, faststatus.Free, faststatus.Busy} {
+		r := faststatus.NewResource()
+		r.Status = s
+		r.Since = time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+		resources[r.ID] = r
+		ids = append(ids, faststatuspb.IDToProto(r.ID))
+		want = append(want, r)
+	}
+	missing, _ := faststatus.NewID()
+	ids = append(ids, faststatuspb.IDToProto(missing))
+	want = append(want, faststatus.Resource{ID: missing, Status: faststatus.Unknown})
+
+	client := dial(t, &rpc.Server{Store: &mockStore{getFn: func(id faststatus.ID) (faststatus.Resource, error) {
+		if r, ok := resources[id]; ok {
+			return r, nil
+		}
+		return faststatus.Resource{}, notFoundError(true)
+	}}})
+
+	resp, err := client.BatchGet(context.Background(), &faststatuspb.BatchGetRequest{Ids: ids})
+	if err != nil {
+		t.Fatalf("BatchGet() = %+v, expected no error", err)
+	}
+	if len(resp.GetResources()) != len(want) {
+		t.Fatalf("BatchGet() returned %d resources, expected %d", len(resp.GetResources()), len(want))
+	}
+	for i, p := range resp.GetResources() {
+		if got, _ := faststatuspb.FromProto(p); !got.Equal(want[i]) {
+			t.Fatalf("BatchGet()[%d] = %+v, expected %+v", i, got, want[i])
+		}
+	}
+
+	_, err = client.BatchGet(context.Background(), &faststatuspb.BatchGetRequest{
+		Ids: append(ids, faststatuspb.IDToProto(faststatus.ID{})),
+	})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Fatalf("BatchGet() with a zero id = %+v, expected code %s", err, codes.InvalidArgument)
+	}
+}
+
+func TestWatchNotImplemented(t *testing.T) {
+	client := dial(t, &rpc.Server{Store: &mockStore{}})
+	stream, err := client.Watch(context.Background(), &faststatuspb.WatchRequest{})
+	if err != nil {
+		t.Fatalf("Watch() = %+v, expected no error", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("Recv() = %+v, expected code %s", err, codes.Unimplemented)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	ms := &mockWatchStore{}
+	client := dial(t, &rpc.Server{Store: ms})
+
+	watched, other := faststatus.NewResource(), faststatus.NewResource()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.Watch(ctx, &faststatuspb.WatchRequest{Ids: []*faststatuspb.ID{faststatuspb.IDToProto(watched.ID)}})
+	if err != nil {
+		t.Fatalf("Watch() = %+v, expected no error", err)
+	}
+	ms.waitForWatchers(t, 1)
+
+	for i, s := range []faststatus.Status{faststatus.Busy, faststatus.Occupied} {
+		other.Status, other.Since = s, time.Date(2017, 3, 14, 15, i, 0, 0, time.UTC)
+		ms.notify(other)
+		watched.Status, watched.Since = s, time.Date(2017, 3, 14, 15, i, 0, 0, time.UTC)
+		ms.notify(watched)
+	}
+	for i, s := range []faststatus.Status{faststatus.Busy, faststatus.Occupied} {
+		p, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv() = %+v, expected no error", err)
+		}
+		got, _ := faststatuspb.FromProto(p)
+		want := faststatus.Resource{ID: watched.ID, Status: s, Since: time.Date(2017, 3, 14, 15, i, 0, 0, time.UTC)}
+		if !got.Equal(want) {
+			t.Fatalf("Recv() = %+v, expected %+v", got, want)
+		}
+	}
+
+	cancel()
+	if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+		t.Fatalf("Recv() after cancel = %+v, expected code %s", err, codes.Canceled)
+	}
+	ms.waitForWatchers(t, 0)
+}
+
+func TestWatchFallsBehind(t *testing.T) {
+	ms := &mockWatchStore{}
+	client := dial(t, &rpc.Server{Store: ms})
+
+	stream, err := client.Watch(context.Background(), &faststatuspb.WatchRequest{})
+	if err != nil {
+		t.Fatalf("Watch() = %+v, expected no error", err)
+	}
+	ms.waitForWatchers(t, 1)
+	// notifications arrive far faster than the stream can send them
+	for i := 0; i < 1000; i++ {
+		ms.notify(faststatus.NewResource())
+	}
+	for {
+		if _, err = stream.Recv(); err != nil {
+			break
+		}
+	}
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Recv() = %+v, expected code %s", err, codes.ResourceExhausted)
+	}
+}
+
+func TestWatchSelector(t *testing.T) {
+	ms := &mockWatchStore{}
+	client := dial(t, &rpc.Server{Store: ms})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.Watch(ctx, &faststatuspb.WatchRequest{Selector: "floor=3,type!=printer"})
+	if err != nil {
+		t.Fatalf("Watch() = %+v, expected no error", err)
+	}
+	ms.waitForWatchers(t, 1)
+
+	printer, room := faststatus.NewResource(), faststatus.NewResource()
+	printer.Labels = map[string]string{"floor": "3", "type": "printer"}
+	room.Labels = map[string]string{"floor": "3", "type": "room"}
+	ms.notify(printer)
+	ms.notify(room)
+
+	p, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv() = %+v, expected no error", err)
+	}
+	if got, _ := faststatuspb.FromProto(p); !got.Equal(room) {
+		t.Fatalf("Recv() = %+v, expected %+v", got, room)
+	}
+}
+
+func TestWatchBadSelector(t *testing.T) {
+	client := dial(t, &rpc.Server{Store: &mockWatchStore{}})
+	stream, err := client.Watch(context.Background(), &faststatuspb.WatchRequest{Selector: "floor in ()"})
+	if err != nil {
+		t.Fatalf("Watch() = %+v, expected no error", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Recv() = %+v, expected code %s", err, codes.InvalidArgument)
+	}
+}
+
+func TestList(t *testing.T) {
+	printer, room := faststatus.NewResource(), faststatus.NewResource()
+	printer.Labels = map[string]string{"floor": "3", "type": "printer"}
+	room.Labels = map[string]string{"floor": "3", "type": "room"}
+	ms := &mockListStore{resources: []faststatus.Resource{printer, room}}
+	client := dial(t, &rpc.Server{Store: ms})
+
+	tests := []struct {
+		name     string
+		selector string
+		want     []faststatus.Resource
+		wantCode codes.Code
+	}{
+		{"everything", "", []faststatus.Resource{printer, room}, codes.OK},
+		{"some", "type=room", []faststatus.Resource{room}, codes.OK},
+		{"none", "floor=4", nil, codes.OK},
+		{"bad selector", "floor=(", nil, codes.InvalidArgument},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := client.List(context.Background(), &faststatuspb.ListRequest{Selector: tc.selector})
+			if code := status.Code(err); code != tc.wantCode {
+				t.Fatalf("List(%q) = %+v, expected code %s", tc.selector, err, tc.wantCode)
+			}
+			if len(resp.GetResources()) != len(tc.want) {
+				t.Fatalf("List(%q) = %v, expected %+v", tc.selector, resp.GetResources(), tc.want)
+			}
+			for i, p := range resp.GetResources() {
+				if got, _ := faststatuspb.FromProto(p); !got.Equal(tc.want[i]) {
+					t.Fatalf("List(%q)[%d] = %+v, expected %+v", tc.selector, i, got, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestListNotImplemented(t *testing.T) {
+	client := dial(t, &rpc.Server{Store: &mockStore{}})
+	if _, err := client.List(context.Background(), &faststatuspb.ListRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("List() = %+v, expected code %s", err, codes.Unimplemented)
+	}
+}
+
+type mockStore struct {
+	saveCalled int
+	saveFn     func(faststatus.Resource) error
+	getFn      func(faststatus.ID) (faststatus.Resource, error)
+}
+
+func (s *mockStore) Save(r faststatus.Resource) error {
+	s.saveCalled++
+	return s.saveFn(r)
+}
+
+func (s *mockStore) Get(id faststatus.ID) (faststatus.Resource, error) {
+	return s.getFn(id)
+}
+
+type mockListStore struct {
+	mockStore
+	resources []faststatus.Resource
+}
+
+func (s *mockListStore) List(sel faststatus.Selector) ([]faststatus.Resource, error) {
+	var resources []faststatus.Resource
+	for _, r := range s.resources {
+		if sel.Matches(r.Labels) {
+			resources = append(resources, r)
+		}
+	}
+	return resources, nil
+}
+
+type mockWatchStore struct {
+	mockStore
+	mu       sync.Mutex
+	watchers map[int]func(faststatus.Resource)
+	next     int
+}
+
+func (s *mockWatchStore) Watch(fn func(faststatus.Resource)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watchers == nil {
+		s.watchers = make(map[int]func(faststatus.Resource))
+	}
+	id := s.next
+	s.next++
+	s.watchers[id] = fn
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.watchers, id)
+	}
+}
+
+func (s *mockWatchStore) notify(r faststatus.Resource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, fn := range s.watchers {
+		fn(r)
+	}
+}
+
+func (s *mockWatchStore) waitForWatchers(t *testing.T, n int) {
+	for i := 0; i < 100; i++ {
+		s.mu.Lock()
+		got := len(s.watchers)
+		s.mu.Unlock()
+		if got == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("never had %d watchers", n)
+}
+
+type conflictError bool
+
+func (e conflictError) Error() string {
+	return "a conflict error"
+}
+
+func (e conflictError) Conflict() bool {
+	return bool(e)
+}
+
+type zeroValueError bool
+
+func (e zeroValueError) Error() string {
+	return "a zero-value error"
+}
+
+func (e zeroValueError) ZeroValue() bool {
+	return bool(e)
+}
+
+type skewError bool
+
+func (e skewError) Error() string {
+	return "a skew error"
+}
+
+func (e skewError) Skew() bool {
+	return bool(e)
+}
+
+type notFoundError bool
+
+func (e notFoundError) Error() string {
+	return "a not-found error"
+}
+
+func (e notFoundError) NotFound() bool {
+	return bool(e)
+}
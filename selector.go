@@ -0,0 +1,232 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A Selector matches Resources by their Labels. The zero value matches
+// every Resource.
+type Selector struct {
+	requirements []requirement
+}
+
+type selectorOp int
+
+const (
+	opEquals selectorOp = iota
+	opNotEquals
+	opIn
+	opNotIn
+	opExists
+	opDoesNotExist
+)
+
+type requirement struct {
+	key    string
+	op     selectorOp
+	values []string
+}
+
+// ParseSelector parses a selector in the form of a Kubernetes label
+// selector: requirements separated by commas, all of which must match.
+// Each requirement is one of:
+//   key=value    key==value    key!=value
+//   key in (value1,value2)     key notin (value1,value2)
+//   key          !key
+// Where a bare key requires the label to exist, and !key requires it not
+// to. A label that does not exist matches != and notin. Keys may hold
+// letters, digits, and the characters "-_./"; values the same, except for
+// "/", and may be empty. An empty selector matches everything.
+func ParseSelector(s string) (Selector, error) {
+	if strings.TrimSpace(s) == "" {
+		return Selector{}, nil
+	}
+	var sel Selector
+	for _, part := range splitSelector(s) {
+		req, err := parseRequirement(strings.TrimSpace(part))
+		if err != nil {
+			return Selector{}, fmt.Errorf("parsing selector requirement %q: %w", part, err)
+		}
+		sel.requirements = append(sel.requirements, req)
+	}
+	return sel, nil
+}
+
+// splitSelector splits on the commas that are not within a set of values.
+func splitSelector(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func parseRequirement(part string) (requirement, error) {
+	switch {
+	case part == "":
+		return requirement{}, fmt.Errorf("empty requirement")
+	case part[0] == '!':
+		key := strings.TrimSpace(part[1:])
+		if !validLabelKey(key) {
+			return requirement{}, fmt.Errorf("invalid label key %q", key)
+		}
+		return requirement{key: key, op: opDoesNotExist}, nil
+	case strings.ContainsAny(part, "!="):
+		i := strings.IndexAny(part, "!=")
+		req := requirement{key: strings.TrimSpace(part[:i])}
+		rest := part[i:]
+		switch {
+		case strings.HasPrefix(rest, "!="):
+			req.op, rest = opNotEquals, rest[2:]
+		case strings.HasPrefix(rest, "=="):
+			req.op, rest = opEquals, rest[2:]
+		case strings.HasPrefix(rest, "="):
+			req.op, rest = opEquals, rest[1:]
+		default:
+			return requirement{}, fmt.Errorf("invalid operator")
+		}
+		value := strings.TrimSpace(rest)
+		if !validLabelKey(req.key) {
+			return requirement{}, fmt.Errorf("invalid label key %q", req.key)
+		}
+		if !validLabelValue(value) {
+			return requirement{}, fmt.Errorf("invalid label value %q", value)
+		}
+		req.values = []string{value}
+		return req, nil
+	case strings.ContainsRune(part, '('):
+		i := strings.IndexByte(part, '(')
+		head := strings.Fields(part[:i])
+		if len(head) != 2 || !strings.HasSuffix(part, ")") {
+			return requirement{}, fmt.Errorf("invalid set requirement")
+		}
+		req := requirement{key: head[0]}
+		switch head[1] {
+		case "in":
+			req.op = opIn
+		case "notin":
+			req.op = opNotIn
+		default:
+			return requirement{}, fmt.Errorf("invalid set operator %q", head[1])
+		}
+		if !validLabelKey(req.key) {
+			return requirement{}, fmt.Errorf("invalid label key %q", req.key)
+		}
+		inner := part[i+1 : len(part)-1]
+		if strings.TrimSpace(inner) == "" {
+			return requirement{}, fmt.Errorf("empty set of values")
+		}
+		for _, value := range strings.Split(inner, ",") {
+			value = strings.TrimSpace(value)
+			if !validLabelValue(value) {
+				return requirement{}, fmt.Errorf("invalid label value %q", value)
+			}
+			req.values = append(req.values, value)
+		}
+		sort.Strings(req.values)
+		return req, nil
+	default:
+		if !validLabelKey(part) {
+			return requirement{}, fmt.Errorf("invalid label key %q", part)
+		}
+		return requirement{key: part, op: opExists}, nil
+	}
+}
+
+func validLabelKey(key string) bool {
+	return key != "" && validLabelChars(key, true)
+}
+
+func validLabelValue(value string) bool {
+	return validLabelChars(value, false)
+}
+
+func validLabelChars(s string, slash bool) bool {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.':
+		case c == '/' && slash:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Matches reports whether the labels meet every requirement of the Selector.
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, req := range s.requirements {
+		if !req.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+func (req requirement) matches(labels map[string]string) bool {
+	value, ok := labels[req.key]
+	switch req.op {
+	case opEquals:
+		return ok && value == req.values[0]
+	case opNotEquals:
+		return !ok || value != req.values[0]
+	case opIn:
+		return ok && req.hasValue(value)
+	case opNotIn:
+		return !ok || !req.hasValue(value)
+	case opExists:
+		return ok
+	default:
+		return !ok
+	}
+}
+
+func (req requirement) hasValue(value string) bool {
+	i := sort.SearchStrings(req.values, value)
+	return i < len(req.values) && req.values[i] == value
+}
+
+// Empty reports whether the Selector has no requirements, and so matches
+// everything.
+func (s Selector) Empty() bool {
+	return len(s.requirements) == 0
+}
+
+// String returns the Selector in the form accepted by ParseSelector.
+func (s Selector) String() string {
+	parts := make([]string, len(s.requirements))
+	for i, req := range s.requirements {
+		switch req.op {
+		case opEquals:
+			parts[i] = req.key + "=" + req.values[0]
+		case opNotEquals:
+			parts[i] = req.key + "!=" + req.values[0]
+		case opIn:
+			parts[i] = req.key + " in (" + strings.Join(req.values, ",") + ")"
+		case opNotIn:
+			parts[i] = req.key + " notin (" + strings.Join(req.values, ",") + ")"
+		case opExists:
+			parts[i] = req.key
+		default:
+			parts[i] = "!" + req.key
+		}
+	}
+	return strings.Join(parts, ",")
+}
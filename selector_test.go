@@ -0,0 +1,111 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus_test
+
+import (
+	"testing"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		name       string
+		selector   string
+		wantString string
+		wantError  bool
+	}{
+		{"empty", "", "", false},
+		{"whitespace", "  ", "", false},
+		{"equals", "floor=3", "floor=3", false},
+		{"double equals", "floor==3", "floor=3", false},
+		{"not equals", "type!=printer", "type!=printer", false},
+		{"exists", "floor", "floor", false},
+		{"does not exist", "!floor", "!floor", false},
+		{"in", "type in (room, printer)", "type in (printer,room)", false},
+		{"notin", "type notin (printer)", "type notin (printer)", false},
+		{"several", "floor=3, type!=printer,!broken", "floor=3,type!=printer,!broken", false},
+		{"set among others", "floor in (2,3),type=room", "floor in (2,3),type=room", false},
+		{"empty value", "note=", "note=", false},
+		{"prefixed key", "example.com/owner=it", "example.com/owner=it", false},
+		{"empty requirement", "floor=3,", "", true},
+		{"empty key", "=3", "", true},
+		{"bad key", "fl oor=3", "", true},
+		{"bad value", "floor=3/4", "", true},
+		{"bad operator", "floor=!3", "", true},
+		{"empty set", "type in ()", "", true},
+		{"unknown set operator", "type within (room)", "", true},
+		{"unclosed set", "type in (room", "", true},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			sel, err := faststatus.ParseSelector(tc.selector)
+			if (err != nil) != tc.wantError {
+				t.Fatalf("ParseSelector(%q) = %+v, expected error: %t", tc.selector, err, tc.wantError)
+			}
+			if got := sel.String(); got != tc.wantString {
+				t.Fatalf("ParseSelector(%q).String() = %q, expected %q", tc.selector, got, tc.wantString)
+			}
+			if err != nil {
+				return
+			}
+			again, err := faststatus.ParseSelector(sel.String())
+			if err != nil || again.String() != sel.String() {
+				t.Fatalf("ParseSelector(%q) = %q, %+v, expected %q", sel.String(), again.String(), err, sel.String())
+			}
+		})
+	}
+}
+
+func TestSelectorMatches(t *testing.T) {
+	printer := map[string]string{"floor": "3", "type": "printer"}
+	tests := []struct {
+		selector string
+		labels   map[string]string
+		want     bool
+	}{
+		{"", nil, true},
+		{"", printer, true},
+		{"floor=3", printer, true},
+		{"floor=4", printer, false},
+		{"floor=3", nil, false},
+		{"type!=printer", printer, false},
+		{"type!=room", printer, true},
+		{"type!=room", nil, true},
+		{"floor=3,type!=printer", printer, false},
+		{"floor=3,type!=room", printer, true},
+		{"type in (room,printer)", printer, true},
+		{"type in (room,desk)", printer, false},
+		{"type in (room)", nil, false},
+		{"type notin (room,printer)", printer, false},
+		{"type notin (room)", printer, true},
+		{"type notin (room)", nil, true},
+		{"floor", printer, true},
+		{"broken", printer, false},
+		{"!broken", printer, true},
+		{"!floor", printer, false},
+		{"note=", map[string]string{"note": ""}, true},
+		{"note=", nil, false},
+	}
+	for _, tc := range tests {
+		sel, err := faststatus.ParseSelector(tc.selector)
+		if err != nil {
+			t.Fatalf("ParseSelector(%q) = %+v, expected no error", tc.selector, err)
+		}
+		if got := sel.Matches(tc.labels); got != tc.want {
+			t.Errorf("ParseSelector(%q).Matches(%v) = %t, expected %t", tc.selector, tc.labels, got, tc.want)
+		}
+	}
+}
+
+func TestSelectorEmpty(t *testing.T) {
+	if !(faststatus.Selector{}).Empty() {
+		t.Fatalf("zero-value Selector is not empty")
+	}
+	sel, _ := faststatus.ParseSelector("floor=3")
+	if sel.Empty() {
+		t.Fatalf("ParseSelector(%q).Empty() = true, expected false", "floor=3")
+	}
+}
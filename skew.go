@@ -0,0 +1,70 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus
+
+import (
+	"fmt"
+	"time"
+)
+
+// A SkewPolicy protects against times too far ahead of a clock, like a
+// Since in the year 2099, which would make every later version of a
+// Resource look old. The zero value allows any time.
+type SkewPolicy struct {
+	// Max is how far ahead of the clock the Since or HLC of a Resource
+	// may be. Zero allows any time.
+	Max time.Duration
+	// Clamp replaces a Since too far ahead with the time of the clock,
+	// instead of rejecting the Resource. An HLC too far ahead is always
+	// rejected, since clamping it would reorder it.
+	Clamp bool
+}
+
+// Limit returns the latest time the policy allows as of now, or false if
+// it allows any time.
+func (p SkewPolicy) Limit(now time.Time) (time.Time, bool) {
+	if p.Max <= 0 {
+		return time.Time{}, false
+	}
+	return now.Add(p.Max), true
+}
+
+// Check returns the Resource as allowed by the policy as of now, or an
+// error for which SkewError is true.
+func (p SkewPolicy) Check(r Resource, now time.Time) (Resource, error) {
+	limit, ok := p.Limit(now)
+	if !ok {
+		return r, nil
+	}
+	if !r.HLC.IsZero() && r.HLC.Wall > limit.UnixNano() {
+		return Resource{}, skewError{field: "HLC", t: time.Unix(0, r.HLC.Wall), max: p.Max}
+	}
+	switch {
+	case !r.Since.After(limit):
+		return r, nil
+	case p.Clamp:
+		r.Since = now
+		return r, nil
+	default:
+		return Resource{}, skewError{field: "Since", t: r.Since, max: p.Max}
+	}
+}
+
+type skewError struct {
+	field string
+	t     time.Time
+	max   time.Duration
+}
+
+func (e skewError) Error() string {
+	return fmt.Sprintf("%s %s is more than %s ahead of the clock", e.field, e.t.Format(time.RFC3339Nano), e.max)
+}
+
+func (e skewError) Skew() bool {
+	return true
+}
+
+func (e skewError) Is(target error) bool {
+	return target == ErrSkew
+}
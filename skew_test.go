@@ -0,0 +1,71 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+func TestSkewPolicyCheck(t *testing.T) {
+	now := time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		policy   faststatus.SkewPolicy
+		input    faststatus.Resource
+		wantSkew bool
+		want     faststatus.Resource
+	}{
+		{"zero policy",
+			faststatus.SkewPolicy{},
+			faststatus.Resource{Since: now.AddDate(80, 0, 0), HLC: faststatus.HLC{Wall: now.AddDate(80, 0, 0).UnixNano()}},
+			false,
+			faststatus.Resource{Since: now.AddDate(80, 0, 0), HLC: faststatus.HLC{Wall: now.AddDate(80, 0, 0).UnixNano()}},
+		},
+		{"in the past",
+			faststatus.SkewPolicy{Max: time.Minute},
+			faststatus.Resource{Since: now.Add(-time.Hour)},
+			false,
+			faststatus.Resource{Since: now.Add(-time.Hour)},
+		},
+		{"at the limit",
+			faststatus.SkewPolicy{Max: time.Minute},
+			faststatus.Resource{Since: now.Add(time.Minute), HLC: faststatus.HLC{Wall: now.Add(time.Minute).UnixNano()}},
+			false,
+			faststatus.Resource{Since: now.Add(time.Minute), HLC: faststatus.HLC{Wall: now.Add(time.Minute).UnixNano()}},
+		},
+		{"since rejected",
+			faststatus.SkewPolicy{Max: time.Minute},
+			faststatus.Resource{Since: now.Add(time.Minute + 1)},
+			true,
+			faststatus.Resource{},
+		},
+		{"since clamped",
+			faststatus.SkewPolicy{Max: time.Minute, Clamp: true},
+			faststatus.Resource{Status: faststatus.Busy, Since: now.Add(time.Hour), Until: now.Add(2 * time.Hour)},
+			false,
+			faststatus.Resource{Status: faststatus.Busy, Since: now, Until: now.Add(2 * time.Hour)},
+		},
+		{"hlc rejected",
+			faststatus.SkewPolicy{Max: time.Minute, Clamp: true},
+			faststatus.Resource{Since: now, HLC: faststatus.HLC{Wall: now.Add(time.Hour).UnixNano()}},
+			true,
+			faststatus.Resource{},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.policy.Check(tc.input, now)
+			if faststatus.SkewError(err) != tc.wantSkew || (err != nil) != tc.wantSkew {
+				t.Fatalf("%+v.Check(%+v) = %+v, expected skew error? %t", tc.policy, tc.input, err, tc.wantSkew)
+			}
+			if !got.Equal(tc.want) {
+				t.Fatalf("%+v.Check(%+v) = %+v, expected %+v", tc.policy, tc.input, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Value implements the driver.Valuer interface, storing an ID as a 16 byte
+// blob.
+func (id ID) Value() (driver.Value, error) {
+	return id.MarshalBinary()
+}
+
+// Scan implements the sql.Scanner interface. An ID may be scanned from a
+// 16 byte blob, or from any text accepted by `UnmarshalText`. NULL scans
+// as the zero-value ID.
+func (id *ID) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*id = ID{}
+		return nil
+	case []byte:
+		if len(src) == 16 {
+			return id.UnmarshalBinary(src)
+		}
+		return id.UnmarshalText(src)
+	case string:
+		return id.UnmarshalText([]byte(src))
+	default:
+		return fmt.Errorf("cannot scan %T into ID", src)
+	}
+}
+
+// Value implements the driver.Valuer interface, storing a Status as an
+// integer.
+func (s Status) Value() (driver.Value, error) {
+	if s > Unknown {
+		return nil, errOutOfRange
+	}
+	return int64(s), nil
+}
+
+// Scan implements the sql.Scanner interface. A Status may be scanned from
+// an integer, or from any text accepted by `UnmarshalText`. Integers are
+// held to the same range as `UnmarshalBinary`. NULL scans as the
+// zero-value Status.
+func (s *Status) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*s = 0
+		return nil
+	case int64:
+		if src < 0 || src > int64(Unknown) {
+			return errOutOfRange
+		}
+		*s = Status(src)
+		return nil
+	case []byte:
+		return s.UnmarshalText(src)
+	case string:
+		return s.UnmarshalText([]byte(src))
+	default:
+		return fmt.Errorf("cannot scan %T into Status", src)
+	}
+}
+
+// Value implements the driver.Valuer interface, storing a Resource as a
+// blob in the binary format from `MarshalBinary`.
+func (r Resource) Value() (driver.Value, error) {
+	return r.MarshalBinary()
+}
+
+// Scan implements the sql.Scanner interface. A Resource may be scanned from
+// a blob in the binary format from `MarshalBinary`. NULL scans as the
+// zero-value Resource.
+func (r *Resource) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*r = Resource{}
+		return nil
+	case []byte:
+		return r.UnmarshalBinary(src)
+	default:
+		return fmt.Errorf("cannot scan %T into Resource", src)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+var (
+	_ sql.Scanner   = new(faststatus.ID)
+	_ driver.Valuer = faststatus.ID{}
+	_ sql.Scanner   = new(faststatus.Status)
+	_ driver.Valuer = faststatus.Status(0)
+	_ sql.Scanner   = new(faststatus.Resource)
+	_ driver.Valuer = faststatus.Resource{}
+)
+
+func TestIDValueScan(t *testing.T) {
+	f := func(id faststatus.ID) bool {
+		v, err := id.Value()
+		if err != nil || !driver.IsValue(v) {
+			return false
+		}
+		got := new(faststatus.ID)
+		return got.Scan(v) == nil && *got == id
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIDScan(t *testing.T) {
+	want := faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	testCases := []struct {
+		name      string
+		src       interface{}
+		wantError bool
+		wantID    faststatus.ID
+	}{
+		{"null", nil, false, faststatus.ID{}},
+		{"blob", want[:], false, want},
+		{"text bytes", []byte("01234567-89ab-cdef-0123-456789abcdef"), false, want},
+		{"text string", "01234567-89ab-cdef-0123-456789abcdef", false, want},
+		{"short string", want.ShortString(), false, want},
+		{"bad blob", []byte{0x01, 0x23}, true, faststatus.ID{}},
+		{"bad text", "not an id", true, faststatus.ID{}},
+		{"integer", int64(1), true, faststatus.ID{}},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			id := faststatus.ID{0xff}
+			err := id.Scan(tc.src)
+			if (err != nil) != tc.wantError {
+				t.Fatalf("Scan(%v) = %+v, expected error? %t", tc.src, err, tc.wantError)
+			}
+			if !tc.wantError && id != tc.wantID {
+				t.Fatalf("Scan(%v) = %x, expected %x", tc.src, id, tc.wantID)
+			}
+		})
+	}
+}
+
+func TestStatusValueScan(t *testing.T) {
+	f := func(s faststatus.Status) bool {
+		v, err := s.Value()
+		if err != nil || !driver.IsValue(v) {
+			return false
+		}
+		got := new(faststatus.Status)
+		return got.Scan(v) == nil && *got == s
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (faststatus.Unknown + 1).Value(); !faststatus.IsOutOfRange(err) {
+		t.Fatalf("Value() = %+v, expected out of range error", err)
+	}
+}
+
+func TestStatusScan(t *testing.T) {
+	testCases := []struct {
+		name           string
+		src            interface{}
+		wantError      bool
+		wantOutOfRange bool
+		wantStatus     faststatus.Status
+	}{
+		{"integer", int64(2), false, false, faststatus.Occupied},
+		{"unknown integer", int64(3), false, false, faststatus.Unknown},
+		{"text bytes", []byte("busy"), false, false, faststatus.Busy},
+		{"text string", "Free", false, false, faststatus.Free},
+		{"number string", "1", false, false, faststatus.Busy},
+		{"negative", int64(-1), true, true, faststatus.Free},
+		{"too large", int64(4), true, true, faststatus.Free},
+		{"bad text", "sleepy", true, false, faststatus.Free},
+		{"null", nil, false, false, faststatus.Free},
+		{"float", 1.0, true, false, faststatus.Free},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var s faststatus.Status
+			if tc.src == nil {
+				s = faststatus.Unknown
+			}
+			err := s.Scan(tc.src)
+			if (err != nil) != tc.wantError {
+				t.Fatalf("Scan(%v) = %+v, expected error? %t", tc.src, err, tc.wantError)
+			}
+			if faststatus.IsOutOfRange(err) != tc.wantOutOfRange {
+				t.Fatalf("Scan(%v) = %+v, expected out of range? %t", tc.src, err, tc.wantOutOfRange)
+			}
+			if s != tc.wantStatus {
+				t.Fatalf("Scan(%v) = %s, expected %s", tc.src, s, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestResourceValueScan(t *testing.T) {
+	f := func(r faststatus.Resource) bool {
+		v, err := r.Value()
+		if err != nil || !driver.IsValue(v) {
+			return false
+		}
+		got := new(faststatus.Resource)
+		return got.Scan(v) == nil && got.Equal(r)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResourceScan(t *testing.T) {
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Busy,
+		Since:  time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC),
+	}
+	blob, _ := r.MarshalBinary()
+	testCases := []struct {
+		name         string
+		src          interface{}
+		wantError    bool
+		wantResource faststatus.Resource
+	}{
+		{"null", nil, false, faststatus.Resource{}},
+		{"blob", blob, false, r},
+		{"short blob", blob[:20], true, faststatus.Resource{}},
+		{"text", r.String(), true, faststatus.Resource{}},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := faststatus.Resource{Status: faststatus.Occupied}
+			err := got.Scan(tc.src)
+			if (err != nil) != tc.wantError {
+				t.Fatalf("Scan(%v) = %+v, expected error? %t", tc.src, err, tc.wantError)
+			}
+			if !tc.wantError && !got.Equal(tc.wantResource) {
+				t.Fatalf("Scan(%v) = %+v, expected %+v", tc.src, got, tc.wantResource)
+			}
+		})
+	}
+}
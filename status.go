@@ -4,7 +4,6 @@
 package faststatus
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 )
@@ -13,7 +12,8 @@ import (
 // where 0 (Free) is a completely unoccupied resource, 2 (Occupied) is
 // completely occupied, and 1 (Busy) is anything between. The simplicity
 // and flexibility of this scheme allows this to be used for any number
-// of applications.
+// of applications. Outside of that scale, 3 (Unknown) is a resource we
+// cannot say anything about.
 type Status uint8
 
 // The following predefined Status values are the only valid status values
@@ -21,15 +21,22 @@ const (
 	Free     Status = iota // a completely unutilized resource
 	Busy                   // a resource that is being utilized, but not to capacity
 	Occupied               // a resource that is being utilized to capacity
+	Unknown                // a resource that has not reported, or whose report has expired
 )
-const statusText = "freebusyoccupied"
-const statusNumbers = "012"
+const statusText = "freebusyoccupiedunknown"
+const statusNumbers = "0123"
 
-var statusTextIdx = [...]uint8{0, 4, 8, 16}
+var statusTextIdx = [...]uint8{0, 4, 8, 16, 23}
 
 // MarshalBinary encodes a Status to a single byte in a slice
 func (s Status) MarshalBinary() ([]byte, error) {
-	return append([]byte{}, byte(s)), nil
+	return s.AppendBinary(nil)
+}
+
+// AppendBinary appends the single byte encoding of a Status to dst,
+// implementing the encoding.BinaryAppender interface.
+func (s Status) AppendBinary(dst []byte) ([]byte, error) {
+	return append(dst, byte(s)), nil
 }
 
 // UnmarshalBinary decodes a Status from a single byte
@@ -38,7 +45,7 @@ func (s *Status) UnmarshalBinary(b []byte) error {
 		return fmt.Errorf("status must be one byte")
 	}
 	tmp := Status(b[0])
-	if tmp > Occupied {
+	if tmp > Unknown {
 		return errOutOfRange
 	}
 	*s = tmp
@@ -46,17 +53,23 @@ func (s *Status) UnmarshalBinary(b []byte) error {
 }
 
 // MarshalText encodes a Status to the text representation. For readable
-// messages, this will be of the form "free|busy|occupied".
+// messages, this will be of the form "free|busy|occupied|unknown".
 func (s Status) MarshalText() ([]byte, error) {
+	return s.AppendText(nil)
+}
+
+// AppendText appends the text representation of a Status to dst,
+// implementing the encoding.TextAppender interface.
+func (s Status) AppendText(dst []byte) ([]byte, error) {
 	if s < 0 || s >= Status(len(statusTextIdx)-1) {
 		return nil, errOutOfRange
 	}
-	return []byte(statusText)[statusTextIdx[s]:statusTextIdx[s+1]], nil
+	return append(dst, statusText[statusTextIdx[s]:statusTextIdx[s+1]]...), nil
 }
 
 // UnmarshalText decodes a Status from a text representation.
 // This can include an integer as text or a case-insensitive name
-// like "Free|BUSY|occupied"
+// like "Free|BUSY|occupied|Unknown"
 func (s *Status) UnmarshalText(txt []byte) error {
 	if len(txt) == 0 {
 		return fmt.Errorf("status must be non-empty byte slice")
@@ -70,7 +83,7 @@ func (s *Status) UnmarshalText(txt []byte) error {
 		}
 	}
 	for i := range statusTextIdx[1:] {
-		if bytes.EqualFold(txt, []byte(statusText)[statusTextIdx[i]:statusTextIdx[i+1]]) {
+		if equalFold(txt, statusText[statusTextIdx[i]:statusTextIdx[i+1]]) {
 			*s = Status(i)
 			return nil
 		}
@@ -78,11 +91,25 @@ func (s *Status) UnmarshalText(txt []byte) error {
 	return fmt.Errorf("not a valid status value")
 }
 
+// equalFold reports whether the ASCII text and name are equal under case
+// folding, without converting either one.
+func equalFold(txt []byte, name string) bool {
+	if len(txt) != len(name) {
+		return false
+	}
+	for i := range txt {
+		if txt[i]|0x20 != name[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // String returns a simple text representation of the Status.
-// Out of range status values will be returned as "Free".
+// Out of range status values will be returned as "unknown".
 func (s Status) String() string {
 	if s < 0 || s >= Status(len(statusTextIdx)-1) {
-		s = Free
+		s = Unknown
 	}
 	txt, _ := s.MarshalText()
 	return string(txt)
@@ -105,13 +132,18 @@ func (e *statusError) OutOfRange() bool {
 	return e.isOutOfRange
 }
 
-// IsOutOfRange returns true for an error indicating that the `Status` is out of range
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+// IsOutOfRange returns true for an error (or any error it wraps) indicating
+// that the `Status` is out of range. Such errors also match ErrOutOfRange.
 func IsOutOfRange(e error) bool {
-	or, ok := e.(outOfRanger)
-	return ok && or.OutOfRange()
+	var or outOfRanger
+	return errors.Is(e, ErrOutOfRange) || errors.As(e, &or) && or.OutOfRange()
 }
 
 var errOutOfRange = &statusError{
-	errors.New("Status not in valid range"),
+	ErrOutOfRange,
 	true,
 }
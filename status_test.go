@@ -27,7 +27,7 @@ func TestStatusUnmarshalBinary(t *testing.T) {
 		s := new(faststatus.Status)
 		err := s.UnmarshalBinary(b)
 		if len(b) == 1 {
-			return (err != nil) == (b[0] > byte(faststatus.Occupied))
+			return (err != nil) == (b[0] > byte(faststatus.Unknown))
 		}
 		return err != nil
 	}
@@ -88,8 +88,14 @@ func TestStatusMarshalText(t *testing.T) {
 			false,
 			false,
 		},
+		{"unknown",
+			faststatus.Unknown,
+			[]byte("unknown"),
+			false,
+			false,
+		},
 		{"out of range",
-			faststatus.Occupied + 1,
+			faststatus.Unknown + 1,
 			nil,
 			true,
 			true,
@@ -186,6 +192,22 @@ func TestStatusUnmarshalText(t *testing.T) {
 			false,
 			faststatus.Occupied,
 		},
+		{[]byte("unknown"),
+			false,
+			faststatus.Unknown,
+		},
+		{[]byte("UNKNOWN"),
+			false,
+			faststatus.Unknown,
+		},
+		{[]byte("3"),
+			false,
+			faststatus.Unknown,
+		},
+		{[]byte("4"),
+			true,
+			faststatus.Free,
+		},
 		{[]byte("foo"),
 			true,
 			faststatus.Free,
@@ -256,9 +278,13 @@ func TestStatusString(t *testing.T) {
 			expected: "occupied",
 			status:   faststatus.Occupied,
 		},
+		{name: "Unknown",
+			expected: "unknown",
+			status:   faststatus.Unknown,
+		},
 		{name: "Out of Range",
-			expected: "free",
-			status:   faststatus.Occupied + 1,
+			expected: "unknown",
+			status:   faststatus.Unknown + 1,
 		},
 	}
 	for _, tc := range testCases {
@@ -270,3 +296,26 @@ func TestStatusString(t *testing.T) {
 		})
 	}
 }
+
+func TestStatusAppendText(t *testing.T) {
+	f := func(prefix []byte, s faststatus.Status) bool {
+		want, _ := s.MarshalText()
+		got, err := s.AppendText(append([]byte{}, prefix...))
+		return err == nil && bytes.Equal(got, append(append([]byte{}, prefix...), want...))
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (faststatus.Unknown + 1).AppendText(nil); !faststatus.IsOutOfRange(err) {
+		t.Fatalf("AppendText() = %+v, expected out of range error", err)
+	}
+}
+
+func TestStatusUnmarshalTextAllocs(t *testing.T) {
+	for _, in := range []string{"free", "BUSY", "Occupied", "3"} {
+		txt := []byte(in)
+		if allocs := testing.AllocsPerRun(100, func() { new(faststatus.Status).UnmarshalText(txt) }); allocs != 0 {
+			t.Fatalf("UnmarshalText(%s) allocates %v times per run, expected none", in, allocs)
+		}
+	}
+}
@@ -4,14 +4,21 @@
 package store
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 
-	"github.com/pkg/errors"
+	"github.com/lazyengineering/faststatus"
 )
 
+// ErrNewerFormat is matched by the error from Open for a file written by a
+// newer version of this package, which this version would misread.
+var ErrNewerFormat = errors.New("store written by a newer format version")
+
 type dataError struct {
-	old  bool
-	noID bool
+	old   bool
+	noID  bool
+	noTTL bool
 }
 
 func (e dataError) Error() string {
@@ -22,21 +29,63 @@ func (e dataError) Error() string {
 	if e.noID {
 		reasons = append(reasons, "resource ID cannot be zero-value")
 	}
+	if e.noTTL {
+		reasons = append(reasons, "resource has no ttl to renew")
+	}
 	return strings.Join(reasons, ", ")
 }
 
+// Conflict is true when the data conflicts with what is already stored:
+// either a more recent version exists, or there is no ttl to renew.
 func (e dataError) Conflict() bool {
-	return e.old
+	return e.old || e.noTTL
 }
 
 func (e dataError) ZeroValue() bool {
 	return e.noID
 }
 
-// ZeroValueError checks to see if the error (or its Cause) is a result of zero-value
-// data where non-zero data is required.
-//
-// An error value may be a zero-value data error if it implements this interface:
+// Is matches faststatus.ErrConflict for conflicts and faststatus.ErrZeroValue
+// for zero-value data, for use with errors.Is.
+func (e dataError) Is(target error) bool {
+	switch target {
+	case faststatus.ErrConflict:
+		return e.Conflict()
+	case faststatus.ErrZeroValue:
+		return e.ZeroValue()
+	default:
+		return false
+	}
+}
+
+// notFoundError is returned for a Resource, or another record such as a
+// Group, that is not in the Store. An empty kind means a Resource.
+type notFoundError struct {
+	id   faststatus.ID
+	kind string
+}
+
+func (e notFoundError) Error() string {
+	kind := e.kind
+	if kind == "" {
+		kind = "resource"
+	}
+	txt, _ := e.id.MarshalText()
+	return fmt.Sprintf("%s %s not found", kind, txt)
+}
+
+// NotFound is always true, so that faststatus.NotFoundError reports it.
+func (e notFoundError) NotFound() bool {
+	return true
+}
+
+func (e notFoundError) Is(target error) bool {
+	return target == faststatus.ErrNotFound
+}
+
+// ZeroValueError checks to see if the error (or any error it wraps) is a
+// result of zero-value data where non-zero data is required: either it
+// matches faststatus.ErrZeroValue, or it implements this interface:
 //
 //    type zerovaluer interface {
 //      ZeroValue() bool
@@ -47,11 +96,6 @@ func ZeroValueError(e error) bool {
 	type zeroValuer interface {
 		ZeroValue() bool
 	}
-	if e, ok := e.(zeroValuer); ok {
-		return e.ZeroValue()
-	}
-	if e, ok := errors.Cause(e).(zeroValuer); ok {
-		return e.ZeroValue()
-	}
-	return false
+	var zv zeroValuer
+	return errors.Is(e, faststatus.ErrZeroValue) || errors.As(e, &zv) && zv.ZeroValue()
 }
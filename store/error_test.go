@@ -5,8 +5,11 @@ package store
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
+	pkgerrors "github.com/pkg/errors"
+
 	"github.com/lazyengineering/faststatus"
 )
 
@@ -70,6 +73,17 @@ func TestDataError(t *testing.T) {
 	if !faststatus.ConflictError(allTrue1) {
 		t.Fatalf("faststatus.ConflictError(%+v) = false, expected true", allTrue1)
 	}
+
+	var noTTLOnly error = &dataError{noTTL: true}
+	if noTTLOnly.Error() == allFalse1.Error() {
+		t.Fatalf("expected %s != %s", noTTLOnly.Error(), allFalse1.Error())
+	}
+	if noTTLOnly.Error() == oldOnly1.Error() {
+		t.Fatalf("expected %s != %s", noTTLOnly.Error(), oldOnly1.Error())
+	}
+	if !faststatus.ConflictError(noTTLOnly) {
+		t.Fatalf("faststatus.ConflictError(%+v) = false, expected true", noTTLOnly)
+	}
 }
 
 func TestZeroValueError(t *testing.T) {
@@ -114,3 +128,49 @@ func TestZeroValueError(t *testing.T) {
 		})
 	}
 }
+
+func TestNotFoundError(t *testing.T) {
+	var err error = notFoundError{id: faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}}
+	if !faststatus.NotFoundError(err) {
+		t.Fatalf("faststatus.NotFoundError(%+v) = false, expected true", err)
+	}
+	if faststatus.ConflictError(err) || ZeroValueError(err) {
+		t.Fatalf("%+v is a conflict or zero-value error, expected neither", err)
+	}
+	if want := "resource 01234567-89ab-cdef-0123-456789abcdef not found"; err.Error() != want {
+		t.Fatalf("Error() = %q, expected %q", err.Error(), want)
+	}
+}
+
+func TestDataErrorIs(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		wantConflict bool
+		wantZero     bool
+		wantNotFound bool
+	}{
+		{"old", dataError{old: true}, true, false, false},
+		{"no ttl", dataError{noTTL: true}, true, false, false},
+		{"no id", dataError{noID: true}, false, true, false},
+		{"not found", notFoundError{}, false, false, true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", pkgerrors.Wrap(tc.err, "saving"))
+			if got := errors.Is(err, faststatus.ErrConflict); got != tc.wantConflict {
+				t.Fatalf("errors.Is(%+v, ErrConflict) = %t, expected %t", err, got, tc.wantConflict)
+			}
+			if got := errors.Is(err, faststatus.ErrZeroValue); got != tc.wantZero {
+				t.Fatalf("errors.Is(%+v, ErrZeroValue) = %t, expected %t", err, got, tc.wantZero)
+			}
+			if got := ZeroValueError(err); got != tc.wantZero {
+				t.Fatalf("ZeroValueError(%+v) = %t, expected %t", err, got, tc.wantZero)
+			}
+			if got := errors.Is(err, faststatus.ErrNotFound); got != tc.wantNotFound {
+				t.Fatalf("errors.Is(%+v, ErrNotFound) = %t, expected %t", err, got, tc.wantNotFound)
+			}
+		})
+	}
+}
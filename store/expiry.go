@@ -0,0 +1,281 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+// SaveWithTTL persists a Resource to the Store iff it is the most recent,
+// and sets how long any version of the Resource remains current without a
+// new version or a heartbeat. Once that time has passed the Resource
+// reports the fallback Status, as of the expiry time. A ttl of zero removes
+// the expiry.
+func (s *Store) SaveWithTTL(r faststatus.Resource, ttl time.Duration, fallback faststatus.Status) error {
+	if s == nil {
+		return errorStoreNotInitialized
+	}
+	if s.DB == nil {
+		return errorDBNotInitialized
+	}
+	now := time.Now()
+	if err := r.ValidateAt(now, s.Skew); err != nil {
+		return err
+	}
+	if fallback > faststatus.Unknown {
+		return errors.Wrap(faststatus.ErrOutOfRange, "fallback status")
+	}
+	key, err := r.ID.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "marshaling binary key from resource ID")
+	}
+	if r, err = s.Skew.Check(r, now); err != nil {
+		return err
+	}
+
+	err = s.DB.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(s.buckets().expiry)
+		if err != nil {
+			return errors.Wrap(err, "creating bucket")
+		}
+		if ttl <= 0 {
+			if err := b.Delete(key); err != nil {
+				return errors.Wrap(err, "deleting expiry from bucket")
+			}
+		} else if err := s.putExpiry(tx, key, expiry{TTL: ttl, Fallback: fallback}); err != nil {
+			return err
+		}
+		r, err = s.save(tx, key, r, now)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "updating database with resource")
+	}
+	s.notify(r)
+	return nil
+}
+
+// Heartbeat extends the expiry of the Resource with the given valid ID
+// without changing its Status, and returns the new expiry time. A ttl of
+// zero keeps the TTL from the last call to SaveWithTTL or Heartbeat. A
+// Resource that has already expired keeps its fallback Status. A Resource
+// that was never saved has nothing to renew, so is not found.
+func (s *Store) Heartbeat(id faststatus.ID, ttl time.Duration) (time.Time, error) {
+	if s == nil {
+		return time.Time{}, errorStoreNotInitialized
+	}
+	if s.DB == nil {
+		return time.Time{}, errorDBNotInitialized
+	}
+	if id == (faststatus.ID{}) {
+		return time.Time{}, dataError{noID: true}
+	}
+	key, err := id.MarshalBinary()
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to marshal key from id")
+	}
+
+	var (
+		expired faststatus.Resource
+		at      time.Time
+	)
+	now := time.Now()
+	err = s.DB.Update(func(tx *bolt.Tx) error {
+		stored, err := s.stored(tx, key)
+		if err != nil {
+			return err
+		}
+		if stored.ID != id {
+			return notFoundError{id: id}
+		}
+		e, ok, err := s.expiryOf(tx, key)
+		if err != nil {
+			return err
+		}
+		if !ok && ttl <= 0 {
+			return dataError{noTTL: true}
+		}
+		if ttl > 0 {
+			e.TTL = ttl
+		}
+		if ok && !e.At.IsZero() && !now.Before(e.At) {
+			if expired, err = s.expire(tx, key, id, e); err != nil {
+				return err
+			}
+		}
+		e.At = now.Add(e.TTL)
+		at = e.At
+		return s.putExpiry(tx, key, e)
+	})
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "updating database with heartbeat")
+	}
+	if expired.ID == id {
+		s.notify(expired)
+	}
+	return at, nil
+}
+
+// ApplyExpired saves the fallback version of every Resource that has
+// expired as of now, and notifies watchers. The fallback versions are
+// returned.
+func (s *Store) ApplyExpired(now time.Time) ([]faststatus.Resource, error) {
+	if s == nil {
+		return nil, errorStoreNotInitialized
+	}
+	if s.DB == nil {
+		return nil, errorDBNotInitialized
+	}
+
+	var applied []faststatus.Resource
+	err := s.DB.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.buckets().expiry)
+		if b == nil {
+			return nil
+		}
+		expiries := make(map[faststatus.ID]expiry)
+		err := b.ForEach(func(k, v []byte) error {
+			var e expiry
+			if err := (&e).UnmarshalBinary(v); err != nil {
+				return errors.Wrap(err, "unmarshaling expiry from stored value")
+			}
+			if e.At.IsZero() || now.Before(e.At) {
+				return nil
+			}
+			var id faststatus.ID
+			if err := (&id).UnmarshalBinary(k); err != nil {
+				return errors.Wrap(err, "unmarshaling id from expiry key")
+			}
+			expiries[id] = e
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for id, e := range expiries {
+			key, err := id.MarshalBinary()
+			if err != nil {
+				return errors.Wrap(err, "marshaling binary key from resource ID")
+			}
+			r, err := s.expire(tx, key, id, e)
+			if err != nil {
+				return err
+			}
+			if r.ID == id {
+				applied = append(applied, r)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "updating database with expired resources")
+	}
+	for _, r := range applied {
+		s.notify(r)
+	}
+	return applied, nil
+}
+
+// expire saves the fallback version of the Resource as of the expiry time,
+// unless a more recent version supersedes it, and disarms the expiry until
+// the next version or heartbeat. The saved fallback version is returned.
+func (s *Store) expire(tx *bolt.Tx, key []byte, id faststatus.ID, e expiry) (faststatus.Resource, error) {
+	current, err := s.latest(tx, key, e.At)
+	if err != nil {
+		return faststatus.Resource{}, err
+	}
+	r, err := s.save(tx, key, derive(current, id, e.Fallback, e.At), e.At)
+	if faststatus.ConflictError(err) {
+		r = faststatus.Resource{}
+	} else if err != nil {
+		return faststatus.Resource{}, err
+	}
+	e.At = time.Time{}
+	return r, s.putExpiry(tx, key, e)
+}
+
+// expiry records how long a Resource remains current, and what it falls
+// back to once it is not. A zero At means the expiry is not armed.
+type expiry struct {
+	At       time.Time
+	TTL      time.Duration
+	Fallback faststatus.Status
+}
+
+// expired returns the fallback version of the current Resource r if it has
+// expired as of now.
+func (e expiry) expired(id faststatus.ID, r faststatus.Resource, now time.Time) (faststatus.Resource, bool) {
+	if e.At.IsZero() || now.Before(e.At) {
+		return faststatus.Resource{}, false
+	}
+	return derive(r, id, e.Fallback, e.At), true
+}
+
+func (e expiry) MarshalBinary() ([]byte, error) {
+	at, err := e.At.MarshalBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling expiry time to binary")
+	}
+	b := make([]byte, 9, 9+len(at))
+	binary.BigEndian.PutUint64(b[0:8], uint64(e.TTL))
+	b[8] = byte(e.Fallback)
+	return append(b, at...), nil
+}
+
+func (e *expiry) UnmarshalBinary(b []byte) error {
+	if len(b) < 9 {
+		return fmt.Errorf("input binary data too short")
+	}
+	tmp := expiry{
+		TTL:      time.Duration(binary.BigEndian.Uint64(b[0:8])),
+		Fallback: faststatus.Status(b[8]),
+	}
+	if tmp.Fallback > faststatus.Unknown {
+		return errors.Wrap(faststatus.ErrOutOfRange, "unmarshaling expiry fallback from binary")
+	}
+	if err := (&tmp.At).UnmarshalBinary(b[9:]); err != nil {
+		return errors.Wrap(err, "unmarshaling expiry time from binary")
+	}
+	*e = tmp
+	return nil
+}
+
+// expiryOf returns the stored expiry of the Resource, if it has one.
+func (s *Store) expiryOf(tx *bolt.Tx, key []byte) (expiry, bool, error) {
+	b := tx.Bucket(s.buckets().expiry)
+	if b == nil {
+		return expiry{}, false, nil
+	}
+	raw := b.Get(key)
+	if len(raw) == 0 {
+		return expiry{}, false, nil
+	}
+	var e expiry
+	if err := (&e).UnmarshalBinary(raw); err != nil {
+		return expiry{}, false, errors.Wrap(err, "unmarshaling expiry from stored value")
+	}
+	return e, true, nil
+}
+
+func (s *Store) putExpiry(tx *bolt.Tx, key []byte, e expiry) error {
+	b, err := tx.CreateBucketIfNotExists(s.buckets().expiry)
+	if err != nil {
+		return errors.Wrap(err, "creating bucket")
+	}
+	payload, err := e.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "marshaling binary for expiry payload")
+	}
+	if err := b.Put(key, payload); err != nil {
+		return errors.Wrap(err, "putting expiry in bucket")
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/store"
+)
+
+func TestSaveWithTTLExpires(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Occupied,
+		Since:  time.Now(),
+	}
+	if err := s.SaveWithTTL(r, 100*time.Millisecond, faststatus.Free); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+	got, err := s.Get(r.ID)
+	if err != nil {
+		t.Fatalf("unexpected error getting resource: %+v", err)
+	}
+	if !got.Equal(r) {
+		t.Fatalf("getting resource before expiry: got %+v, expected %+v", got, r)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	got, err = s.Get(r.ID)
+	if err != nil {
+		t.Fatalf("unexpected error getting resource: %+v", err)
+	}
+	if got.ID != r.ID || got.Status != faststatus.Free || !got.Since.After(r.Since) {
+		t.Fatalf("getting resource after expiry: got %+v, expected free since expiry", got)
+	}
+
+	var notified []faststatus.Resource
+	cancel := s.Watch(func(r faststatus.Resource) {
+		notified = append(notified, r)
+	})
+	defer cancel()
+	applied, err := s.ApplyExpired(time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error applying expiry: %+v", err)
+	}
+	if len(applied) != 1 || !applied[0].Equal(got) {
+		t.Fatalf("applying expiry: got %+v, expected only %+v", applied, got)
+	}
+	if len(notified) != 1 || !notified[0].Equal(got) {
+		t.Fatalf("watching expiry: got %+v, expected only %+v", notified, got)
+	}
+	applied, err = s.ApplyExpired(time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error applying expiry: %+v", err)
+	}
+	if len(applied) != 0 {
+		t.Fatalf("applying expiry twice: got %+v, expected nothing", applied)
+	}
+
+	// a new version renews the ttl
+	r.Since = time.Now()
+	if err := s.Save(r); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+	got, err = s.Get(r.ID)
+	if err != nil {
+		t.Fatalf("unexpected error getting resource: %+v", err)
+	}
+	if !got.Equal(r) {
+		t.Fatalf("getting renewed resource: got %+v, expected %+v", got, r)
+	}
+	time.Sleep(150 * time.Millisecond)
+	got, err = s.Get(r.ID)
+	if err != nil {
+		t.Fatalf("unexpected error getting resource: %+v", err)
+	}
+	if got.Status != faststatus.Free {
+		t.Fatalf("getting renewed resource after expiry: got %+v, expected free", got)
+	}
+}
+
+func TestSaveWithTTLFallbackKeepsFields(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	now := time.Now()
+	r := faststatus.Resource{
+		ID:      faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status:  faststatus.Occupied,
+		Since:   now,
+		Labels:  map[string]string{"floor": "3", "type": "room"},
+		Message: "booked for standup",
+		Until:   now.Add(time.Hour),
+	}
+	if err := s.SaveWithTTL(r, 50*time.Millisecond, faststatus.Free); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	got, err := s.Get(r.ID)
+	if err != nil {
+		t.Fatalf("unexpected error getting resource: %+v", err)
+	}
+	want := r
+	want.Status, want.Since = faststatus.Free, got.Since
+	if !got.Equal(want) || !got.Since.After(r.Since) {
+		t.Fatalf("getting resource after expiry: got %+v, expected %+v since expiry", got, want)
+	}
+
+	applied, err := s.ApplyExpired(time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error applying expiry: %+v", err)
+	}
+	if len(applied) != 1 || !applied[0].Equal(want) {
+		t.Fatalf("applying expiry: got %+v, expected only %+v", applied, want)
+	}
+}
+
+func TestSaveWithTTLFallbackOrdersByHLC(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	now := time.Now()
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Occupied,
+		Since:  now,
+		HLC:    faststatus.HLC{Wall: now.UnixNano(), Node: 1},
+	}
+	if err := s.SaveWithTTL(r, 50*time.Millisecond, faststatus.Free); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	got, err := s.Get(r.ID)
+	if err != nil {
+		t.Fatalf("unexpected error getting resource: %+v", err)
+	}
+	if got.Status != faststatus.Free || got.HLC != (faststatus.HLC{Wall: got.Since.UnixNano()}) || !got.HLC.After(r.HLC) {
+		t.Fatalf("getting resource after expiry: got %+v, expected free with an HLC as of expiry", got)
+	}
+
+	applied, err := s.ApplyExpired(time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error applying expiry: %+v", err)
+	}
+	if len(applied) != 1 || !applied[0].Equal(got) {
+		t.Fatalf("applying expiry: got %+v, expected only %+v", applied, got)
+	}
+
+	// a write stamped before the expiry took effect is older than the fallback
+	stale := r
+	stale.Since = now.Add(10 * time.Millisecond)
+	stale.HLC = faststatus.HLC{Wall: stale.Since.UnixNano(), Node: 1}
+	if err := s.Save(stale); !faststatus.ConflictError(err) {
+		t.Fatalf("saving a resource with an HLC before the fallback = %+v, expected a conflict error", err)
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Occupied,
+		Since:  time.Now(),
+	}
+
+	if err := s.Save(r); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+	if _, err := s.Heartbeat(r.ID, 0); !faststatus.ConflictError(err) {
+		t.Fatalf("heartbeat without a ttl: got %+v, expected a conflict", err)
+	}
+	if _, err := s.Heartbeat(faststatus.ID{}, time.Minute); !store.ZeroValueError(err) {
+		t.Fatalf("heartbeat with zero-value ID: got %+v, expected a zero-value error", err)
+	}
+	unsaved := faststatus.ID{0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01}
+	if _, err := s.Heartbeat(unsaved, time.Minute); !faststatus.NotFoundError(err) {
+		t.Fatalf("heartbeat for a resource never saved: got %+v, expected a not-found error", err)
+	}
+	if _, err := s.Heartbeat(unsaved, 0); !faststatus.NotFoundError(err) {
+		t.Fatalf("heartbeat without a ttl for a resource never saved: got %+v, expected a not-found error", err)
+	}
+
+	if err := s.SaveWithTTL(r, 200*time.Millisecond, faststatus.Busy); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+	for i := 0; i < 4; i++ {
+		time.Sleep(100 * time.Millisecond)
+		before := time.Now()
+		at, err := s.Heartbeat(r.ID, 0)
+		if err != nil {
+			t.Fatalf("unexpected error renewing expiry: %+v", err)
+		}
+		if at.Before(before.Add(200 * time.Millisecond)) {
+			t.Fatalf("heartbeat expires at %s, expected after %s", at, before.Add(200*time.Millisecond))
+		}
+		got, err := s.Get(r.ID)
+		if err != nil {
+			t.Fatalf("unexpected error getting resource: %+v", err)
+		}
+		if !got.Equal(r) {
+			t.Fatalf("getting resource with heartbeat: got %+v, expected %+v", got, r)
+		}
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	if _, err := s.Heartbeat(r.ID, time.Minute); err != nil {
+		t.Fatalf("unexpected error renewing expiry: %+v", err)
+	}
+	got, err := s.Get(r.ID)
+	if err != nil {
+		t.Fatalf("unexpected error getting resource: %+v", err)
+	}
+	if got.Status != faststatus.Busy || !got.Since.After(r.Since) {
+		t.Fatalf("getting resource with a late heartbeat: got %+v, expected busy since expiry", got)
+	}
+}
+
+func TestExpiryFallbackOutOfRange(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Occupied,
+		Since:  time.Now(),
+	}
+	if err := s.SaveWithTTL(r, time.Minute, faststatus.Unknown+1); !faststatus.IsOutOfRange(err) {
+		t.Fatalf("saving with an out of range fallback: got %+v, expected an out-of-range error", err)
+	}
+	if err := s.SaveWithTTL(r, time.Minute, faststatus.Free); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+
+	err := db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte("faststatus/expiry"))
+		v := append([]byte{}, b.Get(r.ID[:])...)
+		v[8] = byte(faststatus.Unknown + 1)
+		return b.Put(r.ID[:], v)
+	})
+	if err != nil {
+		t.Fatalf("corrupting stored expiry: %+v", err)
+	}
+	if got, err := s.Get(r.ID); !faststatus.IsOutOfRange(err) {
+		t.Fatalf("getting resource with a corrupt expiry: got %+v, %+v, expected an out-of-range error", got, err)
+	}
+	if _, err := s.ApplyExpired(time.Now().Add(time.Hour)); !faststatus.IsOutOfRange(err) {
+		t.Fatalf("applying a corrupt expiry: got %+v, expected an out-of-range error", err)
+	}
+}
+
+func TestSaveWithZeroTTLRemovesExpiry(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Occupied,
+		Since:  time.Now(),
+	}
+	if err := s.SaveWithTTL(r, 50*time.Millisecond, faststatus.Free); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+	r.Since = r.Since.Add(time.Millisecond)
+	if err := s.SaveWithTTL(r, 0, faststatus.Free); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	got, err := s.Get(r.ID)
+	if err != nil {
+		t.Fatalf("unexpected error getting resource: %+v", err)
+	}
+	if !got.Equal(r) {
+		t.Fatalf("getting resource without expiry: got %+v, expected %+v", got, r)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package store
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+// SaveGroup persists a Group to the Store, replacing any existing Group
+// with the same ID.
+func (s *Store) SaveGroup(g faststatus.Group) error {
+	if s == nil {
+		return errorStoreNotInitialized
+	}
+	if s.DB == nil {
+		return errorDBNotInitialized
+	}
+	if g.ID == (faststatus.ID{}) {
+		return dataError{noID: true}
+	}
+	key, err := g.ID.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "marshaling binary key from group ID")
+	}
+
+	err = s.DB.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(s.buckets().groups)
+		if err != nil {
+			return errors.Wrap(err, "creating bucket")
+		}
+		payload, err := g.MarshalBinary()
+		if err != nil {
+			return errors.Wrap(err, "marshaling binary for group payload")
+		}
+		if err := b.Put(key, payload); err != nil {
+			return errors.Wrap(err, "putting group in bucket")
+		}
+		return nil
+	})
+	return errors.Wrap(err, "updating database with group")
+}
+
+// GetGroup returns the Group with the given valid ID. If it does not exist
+// in the Store, faststatus.NotFoundError reports the returned error.
+func (s *Store) GetGroup(id faststatus.ID) (faststatus.Group, error) {
+	if s == nil {
+		return faststatus.Group{}, errorStoreNotInitialized
+	}
+	if s.DB == nil {
+		return faststatus.Group{}, errorDBNotInitialized
+	}
+	if id == (faststatus.ID{}) {
+		return faststatus.Group{}, dataError{noID: true}
+	}
+	key, err := id.MarshalBinary()
+	if err != nil {
+		return faststatus.Group{}, errors.Wrap(err, "failed to marshal key from id")
+	}
+
+	g := new(faststatus.Group)
+	err = s.DB.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.buckets().groups)
+		if b == nil {
+			return notFoundError{id: id, kind: "group"}
+		}
+		raw := b.Get(key)
+		if len(raw) == 0 {
+			return notFoundError{id: id, kind: "group"}
+		}
+		if err := g.UnmarshalBinary(raw); err != nil {
+			return errors.Wrap(err, "unmarshaling group from stored value")
+		}
+		return nil
+	})
+	if faststatus.NotFoundError(err) {
+		return faststatus.Group{}, err
+	}
+	if err != nil {
+		return faststatus.Group{}, errors.Wrap(err, "viewing database with group")
+	}
+	return *g, nil
+}
+
+// DeleteGroup removes the Group with the given valid ID from the Store.
+// Deleting a Group that does not exist is not an error. Member Resources
+// are not affected.
+func (s *Store) DeleteGroup(id faststatus.ID) error {
+	if s == nil {
+		return errorStoreNotInitialized
+	}
+	if s.DB == nil {
+		return errorDBNotInitialized
+	}
+	if id == (faststatus.ID{}) {
+		return dataError{noID: true}
+	}
+	key, err := id.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal key from id")
+	}
+
+	err = s.DB.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.buckets().groups)
+		if b == nil {
+			return nil
+		}
+		if err := b.Delete(key); err != nil {
+			return errors.Wrap(err, "deleting group from bucket")
+		}
+		return nil
+	})
+	return errors.Wrap(err, "updating database without group")
+}
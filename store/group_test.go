@@ -0,0 +1,103 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package store_test
+
+import (
+	"testing"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/store"
+)
+
+func TestSaveGetDeleteGroup(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+
+	g := faststatus.Group{
+		ID:   faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Name: "Floor 3",
+		Members: []faststatus.ID{
+			{0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01},
+			{0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23},
+		},
+	}
+
+	if _, err := s.GetGroup(g.ID); !faststatus.NotFoundError(err) {
+		t.Fatalf("getting missing group: got error %+v, expected not found", err)
+	}
+
+	if err := s.SaveGroup(g); err != nil {
+		t.Fatalf("unexpected error saving group: %+v", err)
+	}
+	got, err := s.GetGroup(g.ID)
+	if err != nil {
+		t.Fatalf("unexpected error getting group: %+v", err)
+	}
+	if !got.Equal(g) {
+		t.Fatalf("getting group: got %+v, expected %+v", got, g)
+	}
+
+	g.Members = g.Members[1:]
+	if err := s.SaveGroup(g); err != nil {
+		t.Fatalf("unexpected error replacing group: %+v", err)
+	}
+	got, err = s.GetGroup(g.ID)
+	if err != nil {
+		t.Fatalf("unexpected error getting replaced group: %+v", err)
+	}
+	if !got.Equal(g) {
+		t.Fatalf("getting replaced group: got %+v, expected %+v", got, g)
+	}
+
+	if err := s.DeleteGroup(g.ID); err != nil {
+		t.Fatalf("unexpected error deleting group: %+v", err)
+	}
+	if _, err := s.GetGroup(g.ID); !faststatus.NotFoundError(err) {
+		t.Fatalf("getting deleted group: got error %+v, expected not found", err)
+	}
+}
+
+func TestGroupErrors(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	testCases := []struct {
+		name      string
+		store     *store.Store
+		id        faststatus.ID
+		wantError func(error) bool
+	}{
+		{"nil store",
+			nil,
+			faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+			func(e error) bool { return e != nil },
+		},
+		{"database not initialized",
+			&store.Store{},
+			faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+			func(e error) bool { return e != nil },
+		},
+		{"zero-value ID",
+			&store.Store{DB: db},
+			faststatus.ID{},
+			store.ZeroValueError,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.store.SaveGroup(faststatus.Group{ID: tc.id}); !tc.wantError(err) {
+				t.Fatalf("%+v.SaveGroup(%+v) error checks false, expected true from %+v", tc.store, tc.id, err)
+			}
+			if _, err := tc.store.GetGroup(tc.id); !tc.wantError(err) {
+				t.Fatalf("%+v.GetGroup(%+v) error checks false, expected true from %+v", tc.store, tc.id, err)
+			}
+			if err := tc.store.DeleteGroup(tc.id); !tc.wantError(err) {
+				t.Fatalf("%+v.DeleteGroup(%+v) error checks false, expected true from %+v", tc.store, tc.id, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package store
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+// List returns the most recent version of every saved Resource whose
+// Labels match the Selector, in order of ID.
+func (s *Store) List(sel faststatus.Selector) ([]faststatus.Resource, error) {
+	if s == nil {
+		return nil, errorStoreNotInitialized
+	}
+	if s.DB == nil {
+		return nil, errorDBNotInitialized
+	}
+
+	var resources []faststatus.Resource
+	now := time.Now()
+	err := s.DB.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.buckets().resources)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(key, _ []byte) error {
+			r, err := s.latest(tx, key, now)
+			if err != nil {
+				return err
+			}
+			if sel.Matches(r.Labels) {
+				resources = append(resources, r)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "viewing database with resources")
+	}
+	return resources, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/store"
+)
+
+func TestList(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	since := time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+	printer := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Busy,
+		Since:  since,
+		Labels: map[string]string{"floor": "3", "type": "printer"},
+	}
+	room := faststatus.Resource{
+		ID:     faststatus.ID{0x02, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Free,
+		Since:  since,
+		Labels: map[string]string{"floor": "3", "type": "room"},
+	}
+	unlabeled := faststatus.Resource{
+		ID:     faststatus.ID{0x03, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Occupied,
+		Since:  since,
+	}
+
+	if _, err := s.List(faststatus.Selector{}); err != nil {
+		t.Fatalf("listing an empty store: %+v, expected no error", err)
+	}
+	for _, r := range []faststatus.Resource{printer, room, unlabeled} {
+		if err := s.Save(r); err != nil {
+			t.Fatalf("unexpected error saving resource: %+v", err)
+		}
+	}
+
+	tests := []struct {
+		selector string
+		want     []faststatus.Resource
+	}{
+		{"", []faststatus.Resource{printer, room, unlabeled}},
+		{"floor=3", []faststatus.Resource{printer, room}},
+		{"floor=3,type!=printer", []faststatus.Resource{room}},
+		{"type!=printer", []faststatus.Resource{room, unlabeled}},
+		{"!floor", []faststatus.Resource{unlabeled}},
+		{"floor=4", nil},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.selector, func(t *testing.T) {
+			sel, err := faststatus.ParseSelector(tc.selector)
+			if err != nil {
+				t.Fatalf("ParseSelector(%q) = %+v, expected no error", tc.selector, err)
+			}
+			got, err := s.List(sel)
+			if err != nil {
+				t.Fatalf("List(%q) = %+v, expected no error", tc.selector, err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("List(%q) = %+v, expected %+v", tc.selector, got, tc.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tc.want[i]) {
+					t.Fatalf("List(%q) = %+v, expected %+v", tc.selector, got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestListNotInitialized(t *testing.T) {
+	if _, err := (*store.Store)(nil).List(faststatus.Selector{}); err == nil {
+		t.Fatalf("List on a nil store returned no error")
+	}
+	if _, err := (&store.Store{}).List(faststatus.Selector{}); err == nil {
+		t.Fatalf("List on a store without a database returned no error")
+	}
+}
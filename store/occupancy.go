@@ -0,0 +1,161 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package store
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+// GetOccupancy returns the Occupancy of the Resource with the given valid
+// ID. If none has been recorded in the Store, faststatus.NotFoundError
+// reports the returned error.
+func (s *Store) GetOccupancy(id faststatus.ID) (faststatus.Occupancy, error) {
+	if s == nil {
+		return faststatus.Occupancy{}, errorStoreNotInitialized
+	}
+	if s.DB == nil {
+		return faststatus.Occupancy{}, errorDBNotInitialized
+	}
+	if id == (faststatus.ID{}) {
+		return faststatus.Occupancy{}, dataError{noID: true}
+	}
+	key, err := id.MarshalBinary()
+	if err != nil {
+		return faststatus.Occupancy{}, errors.Wrap(err, "failed to marshal key from id")
+	}
+
+	var o faststatus.Occupancy
+	err = s.DB.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.buckets().occupancy)
+		if b == nil || len(b.Get(key)) == 0 {
+			return notFoundError{id: id, kind: "occupancy"}
+		}
+		o, err = s.occupancy(tx, key)
+		return err
+	})
+	if faststatus.NotFoundError(err) {
+		return faststatus.Occupancy{}, err
+	}
+	if err != nil {
+		return faststatus.Occupancy{}, errors.Wrap(err, "viewing database with occupancy")
+	}
+	return o, nil
+}
+
+// SetOccupancy replaces the Occupancy of the Resource with the given valid
+// ID. See AdjustOccupancy for how the Resource Status is derived.
+func (s *Store) SetOccupancy(id faststatus.ID, o faststatus.Occupancy, since time.Time) (faststatus.Resource, error) {
+	return s.updateOccupancy(id, since, func(faststatus.Occupancy) faststatus.Occupancy {
+		return o
+	})
+}
+
+// AdjustOccupancy adds delta to the Occupancy count of the Resource with the
+// given valid ID. When the Status derived from the new Occupancy differs
+// from the most recent version of the Resource, a new version is saved as
+// of since (or as of the most recent version, if that is later). The
+// returned Resource is the most recent version after the adjustment.
+func (s *Store) AdjustOccupancy(id faststatus.ID, delta int, since time.Time) (faststatus.Resource, error) {
+	return s.updateOccupancy(id, since, func(o faststatus.Occupancy) faststatus.Occupancy {
+		return o.Add(delta)
+	})
+}
+
+func (s *Store) updateOccupancy(id faststatus.ID, since time.Time, fn func(faststatus.Occupancy) faststatus.Occupancy) (faststatus.Resource, error) {
+	if s == nil {
+		return faststatus.Resource{}, errorStoreNotInitialized
+	}
+	if s.DB == nil {
+		return faststatus.Resource{}, errorDBNotInitialized
+	}
+	if id == (faststatus.ID{}) {
+		return faststatus.Resource{}, dataError{noID: true}
+	}
+	key, err := id.MarshalBinary()
+	if err != nil {
+		return faststatus.Resource{}, errors.Wrap(err, "failed to marshal key from id")
+	}
+	now := time.Now()
+	checked, err := s.Skew.Check(faststatus.Resource{Since: since}, now)
+	if err != nil {
+		return faststatus.Resource{}, err
+	}
+	since = checked.Since
+
+	var (
+		r       faststatus.Resource
+		changed bool
+	)
+	err = s.DB.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(s.buckets().occupancy)
+		if err != nil {
+			return errors.Wrap(err, "creating bucket")
+		}
+		o, err := s.occupancy(tx, key)
+		if err != nil {
+			return err
+		}
+		o = fn(o)
+		payload, err := o.MarshalBinary()
+		if err != nil {
+			return errors.Wrap(err, "marshaling binary for occupancy payload")
+		}
+		if err := b.Put(key, payload); err != nil {
+			return errors.Wrap(err, "putting occupancy in bucket")
+		}
+
+		latestResource, err := s.latest(tx, key, now)
+		if err != nil {
+			return err
+		}
+		if latestResource.ID == id && latestResource.Status == o.Status() {
+			r = latestResource
+			return nil
+		}
+		if latestResource.Since.After(since) {
+			since = latestResource.Since
+		}
+		r = derive(latestResource, id, o.Status(), since)
+		if !r.HLC.IsZero() {
+			// the change is written now, on top of the latest version
+			r.HLC = hlcAt(latestResource.HLC, now)
+			if !r.HLC.After(latestResource.HLC) {
+				r.HLC = faststatus.HLC{Wall: latestResource.HLC.Wall, Logical: latestResource.HLC.Logical + 1}
+			}
+		}
+		changed = true
+		r, err = s.save(tx, key, r, now)
+		return err
+	})
+	if err != nil {
+		return faststatus.Resource{}, errors.Wrap(err, "updating database with occupancy")
+	}
+	if changed {
+		s.notify(r)
+	}
+	return r, nil
+}
+
+// occupancy returns the stored Occupancy of the Resource, or a zero-value
+// Occupancy if none is stored.
+func (s *Store) occupancy(tx *bolt.Tx, key []byte) (faststatus.Occupancy, error) {
+	b := tx.Bucket(s.buckets().occupancy)
+	if b == nil {
+		return faststatus.Occupancy{}, nil
+	}
+	raw := b.Get(key)
+	if len(raw) == 0 {
+		return faststatus.Occupancy{}, nil
+	}
+	var o faststatus.Occupancy
+	if err := (&o).UnmarshalBinary(raw); err != nil {
+		return faststatus.Occupancy{}, errors.Wrap(err, "unmarshaling occupancy from stored value")
+	}
+	return o, nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/store"
+)
+
+func TestAdjustOccupancyDerivesStatus(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	id := faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	start := time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+
+	if _, err := s.GetOccupancy(id); !faststatus.NotFoundError(err) {
+		t.Fatalf("getting missing occupancy: got error %+v, expected not found", err)
+	}
+
+	got, err := s.SetOccupancy(id, faststatus.Occupancy{Capacity: 2}, start)
+	if err != nil {
+		t.Fatalf("unexpected error setting occupancy: %+v", err)
+	}
+	if want := (faststatus.Resource{ID: id, Status: faststatus.Free, Since: start}); !got.Equal(want) {
+		t.Fatalf("setting occupancy: got %+v, expected %+v", got, want)
+	}
+
+	// order of steps matters here (these are not stateless)
+	steps := []struct {
+		delta         int
+		wantOccupancy faststatus.Occupancy
+		wantStatus    faststatus.Status
+		wantSince     time.Time
+	}{
+		{1, faststatus.Occupancy{Count: 1, Capacity: 2}, faststatus.Busy, start.Add(1 * time.Minute)},
+		{1, faststatus.Occupancy{Count: 2, Capacity: 2}, faststatus.Occupied, start.Add(2 * time.Minute)},
+		{1, faststatus.Occupancy{Count: 3, Capacity: 2}, faststatus.Occupied, start.Add(2 * time.Minute)},
+		{-2, faststatus.Occupancy{Count: 1, Capacity: 2}, faststatus.Busy, start.Add(4 * time.Minute)},
+		{-5, faststatus.Occupancy{Count: 0, Capacity: 2}, faststatus.Free, start.Add(5 * time.Minute)},
+	}
+	for i, step := range steps {
+		got, err := s.AdjustOccupancy(id, step.delta, start.Add(time.Duration(i+1)*time.Minute))
+		if err != nil {
+			t.Fatalf("step %d: unexpected error adjusting occupancy: %+v", i, err)
+		}
+		want := faststatus.Resource{ID: id, Status: step.wantStatus, Since: step.wantSince}
+		if !got.Equal(want) {
+			t.Fatalf("step %d: adjusting occupancy returned %+v, expected %+v", i, got, want)
+		}
+		stored, err := s.Get(id)
+		if err != nil {
+			t.Fatalf("step %d: unexpected error getting resource: %+v", i, err)
+		}
+		if !stored.Equal(want) {
+			t.Fatalf("step %d: getting resource returned %+v, expected %+v", i, stored, want)
+		}
+		o, err := s.GetOccupancy(id)
+		if err != nil {
+			t.Fatalf("step %d: unexpected error getting occupancy: %+v", i, err)
+		}
+		if o != step.wantOccupancy {
+			t.Fatalf("step %d: getting occupancy returned %+v, expected %+v", i, o, step.wantOccupancy)
+		}
+	}
+}
+
+func TestAdjustOccupancyKeepsLatestSince(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Free,
+		Since:  time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC),
+	}
+	if err := s.Save(r); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+	got, err := s.AdjustOccupancy(r.ID, 1, r.Since.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error adjusting occupancy: %+v", err)
+	}
+	want := faststatus.Resource{ID: r.ID, Status: faststatus.Busy, Since: r.Since}
+	if !got.Equal(want) {
+		t.Fatalf("adjusting occupancy returned %+v, expected %+v", got, want)
+	}
+}
+
+func TestAdjustOccupancyKeepsFields(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	since := time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+	r := faststatus.Resource{
+		ID:      faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status:  faststatus.Free,
+		Since:   since,
+		Labels:  map[string]string{"floor": "3", "type": "room"},
+		Message: "booked for standup",
+		Until:   since.Add(time.Hour),
+	}
+	if err := s.Save(r); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+	got, err := s.AdjustOccupancy(r.ID, 1, since.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error adjusting occupancy: %+v", err)
+	}
+	want := r
+	want.Status, want.Since = faststatus.Busy, since.Add(time.Minute)
+	if !got.Equal(want) {
+		t.Fatalf("adjusting occupancy returned %+v, expected %+v", got, want)
+	}
+
+	got, err = s.AdjustOccupancy(r.ID, -1, since.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error adjusting occupancy: %+v", err)
+	}
+	want.Status, want.Since, want.Until = faststatus.Free, since.Add(2*time.Hour), time.Time{}
+	if !got.Equal(want) {
+		t.Fatalf("adjusting occupancy past Until returned %+v, expected %+v", got, want)
+	}
+}
+
+func TestAdjustOccupancyOrdersByHLC(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	since := time.Now().Add(-time.Minute)
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Free,
+		Since:  since,
+		HLC:    faststatus.HLC{Wall: time.Now().UnixNano(), Logical: 3, Node: 1},
+	}
+	if err := s.Save(r); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+	got, err := s.AdjustOccupancy(r.ID, 1, since)
+	if err != nil {
+		t.Fatalf("unexpected error adjusting occupancy: %+v", err)
+	}
+	if got.Status != faststatus.Busy || !got.HLC.After(r.HLC) {
+		t.Fatalf("adjusting occupancy returned %+v, expected busy with an HLC after %+v", got, r.HLC)
+	}
+}
+
+func TestOccupancyErrors(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	testCases := []struct {
+		name      string
+		store     *store.Store
+		id        faststatus.ID
+		wantError func(error) bool
+	}{
+		{"nil store",
+			nil,
+			faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+			func(e error) bool { return e != nil },
+		},
+		{"database not initialized",
+			&store.Store{},
+			faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+			func(e error) bool { return e != nil },
+		},
+		{"zero-value ID",
+			&store.Store{DB: db},
+			faststatus.ID{},
+			store.ZeroValueError,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.store.GetOccupancy(tc.id); !tc.wantError(err) {
+				t.Fatalf("%+v.GetOccupancy(%+v) error checks false, expected true from %+v", tc.store, tc.id, err)
+			}
+			if _, err := tc.store.SetOccupancy(tc.id, faststatus.Occupancy{}, time.Now()); !tc.wantError(err) {
+				t.Fatalf("%+v.SetOccupancy(%+v) error checks false, expected true from %+v", tc.store, tc.id, err)
+			}
+			if _, err := tc.store.AdjustOccupancy(tc.id, 1, time.Now()); !tc.wantError(err) {
+				t.Fatalf("%+v.AdjustOccupancy(%+v) error checks false, expected true from %+v", tc.store, tc.id, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+)
+
+// formatVersion is the version of the layout of the buckets of a Store.
+// It is recorded in the meta bucket, so that a Store refuses to open a
+// file written by a newer layout that it would misread.
+const formatVersion = 1
+
+var versionKey = []byte("version")
+
+// Opt configures a Store opened with Open.
+type Opt func(*options) error
+
+type options struct {
+	prefix   string
+	mode     os.FileMode
+	timeout  time.Duration
+	readOnly bool
+	noSync   bool
+}
+
+// WithBucketPrefix names the buckets of the Store prefix+"/store",
+// prefix+"/groups" and so on, so that several Stores can share one file.
+// The default prefix is "faststatus".
+func WithBucketPrefix(prefix string) Opt {
+	return func(o *options) error {
+		if prefix == "" {
+			return fmt.Errorf("empty bucket prefix")
+		}
+		o.prefix = prefix
+		return nil
+	}
+}
+
+// WithFileMode sets the permissions of the file, if Open creates it. The
+// default is 0600.
+func WithFileMode(mode os.FileMode) Opt {
+	return func(o *options) error {
+		o.mode = mode
+		return nil
+	}
+}
+
+// WithTimeout sets how long Open waits for the lock on a file that another
+// process has open. The default is one second, and zero waits forever.
+func WithTimeout(timeout time.Duration) Opt {
+	return func(o *options) error {
+		if timeout < 0 {
+			return fmt.Errorf("negative timeout %s", timeout)
+		}
+		o.timeout = timeout
+		return nil
+	}
+}
+
+// ReadOnly opens the file with a shared lock, so that other read-only
+// processes may open it too. Every write to the Store fails.
+func ReadOnly() Opt {
+	return func(o *options) error {
+		o.readOnly = true
+		return nil
+	}
+}
+
+// NoSync skips the fsync after each write, which is faster but may lose
+// the most recent writes, or corrupt the file, on a crash.
+func NoSync() Opt {
+	return func(o *options) error {
+		o.noSync = true
+		return nil
+	}
+}
+
+// Open opens the bolt database at path, creating it if needed, and returns
+// a Store ready to use, with all of its buckets created. It fails for a
+// file written by a newer version of this package. Close the Store when
+// done with it.
+func Open(path string, opts ...Opt) (*Store, error) {
+	o := options{
+		prefix:  defaultBucketPrefix,
+		mode:    0600,
+		timeout: time.Second,
+	}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, errors.Wrap(err, "configuring store")
+		}
+	}
+
+	db, err := bolt.Open(path, o.mode, &bolt.Options{Timeout: o.timeout, ReadOnly: o.readOnly})
+	if err != nil {
+		return nil, errors.Wrap(err, "opening bolt database")
+	}
+	db.NoSync = o.noSync
+
+	s := &Store{DB: db, names: newBuckets(o.prefix)}
+	if o.readOnly {
+		err = db.View(s.checkVersion)
+	} else {
+		err = db.Update(s.init)
+	}
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// init creates every bucket of the Store, and records the format version
+// in a new file.
+func (s *Store) init(tx *bolt.Tx) error {
+	for _, name := range s.buckets().all() {
+		if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+			return errors.Wrapf(err, "creating bucket %s", name)
+		}
+	}
+	if err := s.checkVersion(tx); err != nil {
+		return err
+	}
+	b := tx.Bucket(s.buckets().meta)
+	if b.Get(versionKey) != nil {
+		return nil
+	}
+	if err := b.Put(versionKey, binary.AppendUvarint(nil, formatVersion)); err != nil {
+		return errors.Wrap(err, "putting format version in bucket")
+	}
+	return nil
+}
+
+// checkVersion returns an error matching ErrNewerFormat if the file was
+// written by a newer layout.
+func (s *Store) checkVersion(tx *bolt.Tx) error {
+	b := tx.Bucket(s.buckets().meta)
+	if b == nil {
+		return nil
+	}
+	raw := b.Get(versionKey)
+	if raw == nil {
+		return nil
+	}
+	v, n := binary.Uvarint(raw)
+	if n <= 0 {
+		return fmt.Errorf("invalid format version %x", raw)
+	}
+	if v > formatVersion {
+		return fmt.Errorf("%w: version %d, expected at most %d", ErrNewerFormat, v, formatVersion)
+	}
+	return nil
+}
+
+// Close closes the bolt database of the Store. The Store cannot be used
+// after.
+func (s *Store) Close() error {
+	if s == nil {
+		return errorStoreNotInitialized
+	}
+	if s.DB == nil {
+		return errorDBNotInitialized
+	}
+	return errors.Wrap(s.DB.Close(), "closing bolt database")
+}
@@ -0,0 +1,202 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package store_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/store"
+)
+
+var openTestResource = faststatus.Resource{
+	ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+	Status: faststatus.Busy,
+	Since:  time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC),
+}
+
+func TestOpenCreatesBuckets(t *testing.T) {
+	path, cleanup := tempfile(t)
+	defer cleanup()
+
+	s, err := store.Open(path)
+	if err != nil {
+		t.Fatalf("Open() = %+v, expected no error", err)
+	}
+	defer s.Close()
+	err = s.DB.View(func(tx *bolt.Tx) error {
+		for _, name := range []string{
+			"faststatus/store",
+			"faststatus/groups",
+			"faststatus/occupancy",
+			"faststatus/schedule",
+			"faststatus/expiry",
+			"faststatus/meta",
+		} {
+			if tx.Bucket([]byte(name)) == nil {
+				t.Errorf("bucket %q not created", name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("viewing database: %+v", err)
+	}
+}
+
+func TestOpenSaveCloseReopen(t *testing.T) {
+	path, cleanup := tempfile(t)
+	defer cleanup()
+
+	s, err := store.Open(path, store.WithFileMode(0640), store.NoSync())
+	if err != nil {
+		t.Fatalf("Open() = %+v, expected no error", err)
+	}
+	if !s.DB.NoSync {
+		t.Fatalf("Open() with NoSync() has NoSync false, expected true")
+	}
+	if err := s.Save(openTestResource); err != nil {
+		t.Fatalf("Save() = %+v, expected no error", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() = %+v, expected no error", err)
+	}
+
+	s, err = store.Open(path, store.ReadOnly())
+	if err != nil {
+		t.Fatalf("Open() read-only = %+v, expected no error", err)
+	}
+	defer s.Close()
+	got, err := s.Get(openTestResource.ID)
+	if err != nil || !got.Equal(openTestResource) {
+		t.Fatalf("Get() after reopening = %+v, %+v, expected %+v", got, err, openTestResource)
+	}
+	next := openTestResource
+	next.Since = next.Since.Add(time.Minute)
+	if err := s.Save(next); err == nil {
+		t.Fatalf("Save() on a read-only Store = <nil>, expected error")
+	}
+}
+
+func TestOpenFileMode(t *testing.T) {
+	path, cleanup := tempfile(t)
+	cleanup() // Open creates the file
+	defer cleanup()
+
+	s, err := store.Open(path, store.WithFileMode(0640))
+	if err != nil {
+		t.Fatalf("Open() = %+v, expected no error", err)
+	}
+	defer s.Close()
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat of store file: %+v", err)
+	}
+	if mode := fi.Mode().Perm(); mode&^0640 != 0 {
+		t.Fatalf("store file mode %s, expected at most %s", mode, os.FileMode(0640))
+	}
+}
+
+func TestOpenBucketPrefix(t *testing.T) {
+	path, cleanup := tempfile(t)
+	defer cleanup()
+
+	a, err := store.Open(path, store.WithBucketPrefix("a"))
+	if err != nil {
+		t.Fatalf("Open() = %+v, expected no error", err)
+	}
+	if err := a.Save(openTestResource); err != nil {
+		t.Fatalf("Save() = %+v, expected no error", err)
+	}
+	a.Close()
+
+	b, err := store.Open(path, store.WithBucketPrefix("b"))
+	if err != nil {
+		t.Fatalf("Open() = %+v, expected no error", err)
+	}
+	defer b.Close()
+	if _, err := b.Get(openTestResource.ID); !faststatus.NotFoundError(err) {
+		t.Fatalf("Get() from another prefix = %+v, expected not found", err)
+	}
+	err = b.DB.View(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte("a/store")) == nil || tx.Bucket([]byte("b/store")) == nil {
+			t.Errorf("expected buckets for both prefixes")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("viewing database: %+v", err)
+	}
+}
+
+func TestOpenTimeout(t *testing.T) {
+	path, cleanup := tempfile(t)
+	defer cleanup()
+
+	s, err := store.Open(path)
+	if err != nil {
+		t.Fatalf("Open() = %+v, expected no error", err)
+	}
+	defer s.Close()
+	start := time.Now()
+	if _, err := store.Open(path, store.WithTimeout(50*time.Millisecond)); err == nil {
+		t.Fatalf("Open() of a locked file = <nil>, expected error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Open() of a locked file took %s, expected to time out sooner", elapsed)
+	}
+}
+
+func TestOpenNewerFormat(t *testing.T) {
+	path, cleanup := tempfile(t)
+	defer cleanup()
+
+	s, err := store.Open(path)
+	if err != nil {
+		t.Fatalf("Open() = %+v, expected no error", err)
+	}
+	err = s.DB.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte("faststatus/meta")).Put([]byte("version"), binary.AppendUvarint(nil, 99))
+	})
+	if err != nil {
+		t.Fatalf("writing a newer version: %+v", err)
+	}
+	s.Close()
+
+	for _, opts := range [][]store.Opt{nil, {store.ReadOnly()}} {
+		if _, err := store.Open(path, opts...); !errors.Is(err, store.ErrNewerFormat) {
+			t.Fatalf("Open() of a newer format = %+v, expected ErrNewerFormat", err)
+		}
+	}
+}
+
+func TestOpenBadOptions(t *testing.T) {
+	path, cleanup := tempfile(t)
+	defer cleanup()
+
+	for name, opt := range map[string]store.Opt{
+		"empty prefix":     store.WithBucketPrefix(""),
+		"negative timeout": store.WithTimeout(-time.Second),
+	} {
+		if _, err := store.Open(path, opt); err == nil {
+			t.Errorf("Open() with %s = <nil>, expected error", name)
+		}
+	}
+}
+
+func TestCloseNotInitialized(t *testing.T) {
+	var s *store.Store
+	if err := s.Close(); err == nil {
+		t.Fatalf("Close() on nil Store = <nil>, expected error")
+	}
+	if err := (&store.Store{}).Close(); err == nil {
+		t.Fatalf("Close() without a DB = <nil>, expected error")
+	}
+}
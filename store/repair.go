@@ -0,0 +1,78 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package store
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+// RepairSkew saves, as of now, every stored Resource whose Since is more
+// than the Skew Max ahead of now, and clears any HLC that is, so that the
+// Resource accepts new versions again. It is for Resources saved before
+// the Skew was set, or by a clock that has since been fixed. The repaired
+// versions are returned, and watchers are notified.
+func (s *Store) RepairSkew(now time.Time) ([]faststatus.Resource, error) {
+	if s == nil {
+		return nil, errorStoreNotInitialized
+	}
+	if s.DB == nil {
+		return nil, errorDBNotInitialized
+	}
+
+	limit := now.Add(s.Skew.Max)
+	var repaired []faststatus.Resource
+	err := s.DB.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.buckets().resources)
+		if b == nil {
+			return nil
+		}
+		err := b.ForEach(func(_, v []byte) error {
+			var r faststatus.Resource
+			if err := (&r).UnmarshalBinary(v); err != nil {
+				return errors.Wrap(err, "unmarshaling resource from stored value")
+			}
+			skewed := false
+			if r.Since.After(limit) {
+				r.Since, skewed = now, true
+			}
+			if !r.HLC.IsZero() && r.HLC.Wall > limit.UnixNano() {
+				r.HLC, skewed = faststatus.HLC{}, true
+			}
+			if skewed {
+				repaired = append(repaired, r)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// the bucket cannot be changed while iterating over it
+		for _, r := range repaired {
+			key, err := r.ID.MarshalBinary()
+			if err != nil {
+				return errors.Wrap(err, "marshaling binary key from resource ID")
+			}
+			payload, err := r.MarshalBinary()
+			if err != nil {
+				return errors.Wrap(err, "marshaling binary for resource payload")
+			}
+			if err := b.Put(key, payload); err != nil {
+				return errors.Wrap(err, "putting resource in bucket")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "updating database with repaired resources")
+	}
+	for _, r := range repaired {
+		s.notify(r)
+	}
+	return repaired, nil
+}
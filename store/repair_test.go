@@ -0,0 +1,94 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/store"
+)
+
+func TestRepairSkew(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	now := time.Now()
+	poisoned := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Busy,
+		Since:  time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	hlcPoisoned := faststatus.Resource{
+		ID:     faststatus.ID{0x02, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Busy,
+		Since:  now.Add(-time.Minute),
+		HLC:    faststatus.HLC{Wall: now.Add(24 * time.Hour).UnixNano(), Node: 1},
+	}
+	healthy := faststatus.Resource{
+		ID:     faststatus.ID{0x03, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Free,
+		Since:  now.Add(time.Second),
+		HLC:    faststatus.HLC{Wall: now.UnixNano(), Node: 1},
+	}
+	s := &store.Store{DB: db}
+	for _, r := range []faststatus.Resource{poisoned, hlcPoisoned, healthy} {
+		if err := s.Save(r); err != nil {
+			t.Fatalf("unexpected error saving resource: %+v", err)
+		}
+	}
+
+	s.Skew.Max = time.Minute
+	update := poisoned
+	update.Status = faststatus.Free
+	update.Since = now
+	if err := s.Save(update); !faststatus.ConflictError(err) {
+		t.Fatalf("Save(%+v) before repair = %+v, expected a conflict error", update, err)
+	}
+
+	var notified []faststatus.Resource
+	cancel := s.Watch(func(r faststatus.Resource) {
+		notified = append(notified, r)
+	})
+	defer cancel()
+	repaired, err := s.RepairSkew(now)
+	if err != nil {
+		t.Fatalf("RepairSkew() = %+v, expected no error", err)
+	}
+	wantPoisoned := poisoned
+	wantPoisoned.Since = now
+	wantHLCPoisoned := hlcPoisoned
+	wantHLCPoisoned.HLC = faststatus.HLC{}
+	if len(repaired) != 2 || !repaired[0].Equal(wantPoisoned) || !repaired[1].Equal(wantHLCPoisoned) {
+		t.Fatalf("RepairSkew() = %+v, expected %+v and %+v", repaired, wantPoisoned, wantHLCPoisoned)
+	}
+	if len(notified) != 2 {
+		t.Fatalf("watching repair: got %+v, expected %d notifications", notified, 2)
+	}
+
+	update.Since = now.Add(time.Second)
+	if err := s.Save(update); err != nil {
+		t.Fatalf("Save(%+v) after repair = %+v, expected no error", update, err)
+	}
+	got, err := s.Get(healthy.ID)
+	if err != nil || !got.Equal(healthy) {
+		t.Fatalf("Get() = %+v, %+v after repair, expected unchanged %+v", got, err, healthy)
+	}
+
+	repaired, err = s.RepairSkew(now)
+	if err != nil || len(repaired) != 0 {
+		t.Fatalf("RepairSkew() again = %+v, %+v, expected nothing to repair", repaired, err)
+	}
+}
+
+func TestRepairSkewNotInitialized(t *testing.T) {
+	var s *store.Store
+	if _, err := s.RepairSkew(time.Now()); err == nil {
+		t.Fatalf("RepairSkew() on nil Store = <nil>, expected error")
+	}
+	if _, err := (&store.Store{}).RepairSkew(time.Now()); err == nil {
+		t.Fatalf("RepairSkew() without a DB = <nil>, expected error")
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+// Schedule persists a future version of a Resource, which takes effect once
+// its Since time has passed. A Resource may have any number of scheduled
+// changes, but each must be more recent than the current version.
+func (s *Store) Schedule(r faststatus.Resource) error {
+	if s == nil {
+		return errorStoreNotInitialized
+	}
+	if s.DB == nil {
+		return errorDBNotInitialized
+	}
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	key, err := r.ID.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "marshaling binary key from resource ID")
+	}
+
+	err = s.DB.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(s.buckets().schedule)
+		if err != nil {
+			return errors.Wrap(err, "creating bucket")
+		}
+		latestResource, err := s.latest(tx, key, time.Now())
+		if err != nil {
+			return err
+		}
+		due := r
+		if due.HLC.IsZero() {
+			due.HLC = hlcAt(latestResource.HLC, r.Since)
+		}
+		if !supersedes(due, latestResource) {
+			return dataError{old: true}
+		}
+		payload, err := r.MarshalBinary()
+		if err != nil {
+			return errors.Wrap(err, "marshaling binary for scheduled resource payload")
+		}
+		if err := b.Put(scheduleKey(key, r.Since), payload); err != nil {
+			return errors.Wrap(err, "putting scheduled resource in bucket")
+		}
+		return nil
+	})
+	return errors.Wrap(err, "updating database with scheduled resource")
+}
+
+// Scheduled returns the changes to the Resource with the given valid ID
+// that have not yet taken effect, in the order they will take effect.
+func (s *Store) Scheduled(id faststatus.ID) ([]faststatus.Resource, error) {
+	if s == nil {
+		return nil, errorStoreNotInitialized
+	}
+	if s.DB == nil {
+		return nil, errorDBNotInitialized
+	}
+	if id == (faststatus.ID{}) {
+		return nil, dataError{noID: true}
+	}
+	key, err := id.MarshalBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal key from id")
+	}
+
+	var scheduled []faststatus.Resource
+	now := time.Now()
+	err = s.DB.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.buckets().schedule)
+		if b == nil {
+			return nil
+		}
+		c := b.Cursor()
+		for k, v := c.Seek(scheduleKey(key, now)); k != nil && bytes.HasPrefix(k, key); k, v = c.Next() {
+			var r faststatus.Resource
+			if err := (&r).UnmarshalBinary(v); err != nil {
+				return errors.Wrap(err, "unmarshaling scheduled resource from stored value")
+			}
+			if r.Since.After(now) {
+				scheduled = append(scheduled, r)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "viewing database with scheduled resources")
+	}
+	return scheduled, nil
+}
+
+// ApplyScheduled saves every scheduled change that is due as of now as the
+// most recent version of its Resource, and notifies watchers. Changes that
+// were superseded before they were applied are discarded. The applied
+// changes are returned.
+func (s *Store) ApplyScheduled(now time.Time) ([]faststatus.Resource, error) {
+	if s == nil {
+		return nil, errorStoreNotInitialized
+	}
+	if s.DB == nil {
+		return nil, errorDBNotInitialized
+	}
+
+	var applied []faststatus.Resource
+	err := s.DB.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.buckets().schedule)
+		if b == nil {
+			return nil
+		}
+		var due [][]byte
+		c := b.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var r faststatus.Resource
+			if err := (&r).UnmarshalBinary(v); err != nil {
+				return errors.Wrap(err, "unmarshaling scheduled resource from stored value")
+			}
+			if r.Since.After(now) {
+				continue
+			}
+			due = append(due, k)
+			if r.HLC.IsZero() {
+				stored, err := s.stored(tx, k[:16])
+				if err != nil {
+					return err
+				}
+				r.HLC = hlcAt(stored.HLC, r.Since)
+			}
+			r, err := s.save(tx, k[:16], r, r.Since)
+			if faststatus.ConflictError(err) {
+				continue
+			} else if err != nil {
+				return err
+			}
+			applied = append(applied, r)
+		}
+		for _, k := range due {
+			if err := b.Delete(k); err != nil {
+				return errors.Wrap(err, "deleting applied scheduled resource from bucket")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "updating database with scheduled resources")
+	}
+	for _, r := range applied {
+		s.notify(r)
+	}
+	return applied, nil
+}
+
+// RunScheduler applies scheduled changes and expiries as they become due,
+// checking at the given interval, until the context is done. The interval
+// must be positive.
+func (s *Store) RunScheduler(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("scheduler interval %s is not positive", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		now := time.Now()
+		if _, err := s.ApplyScheduled(now); err != nil {
+			return err
+		}
+		if _, err := s.ApplyExpired(now); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// lastDue returns the most recent scheduled version of the Resource that
+// is due as of now, or a zero-value Resource if none is due.
+func (s *Store) lastDue(tx *bolt.Tx, key []byte, now time.Time) (faststatus.Resource, error) {
+	b := tx.Bucket(s.buckets().schedule)
+	if b == nil {
+		return faststatus.Resource{}, nil
+	}
+	var raw []byte
+	c := b.Cursor()
+	for k, v := c.Seek(key); k != nil && bytes.HasPrefix(k, key); k, v = c.Next() {
+		if bytes.Compare(k, scheduleKey(key, now)) > 0 {
+			break
+		}
+		raw = v
+	}
+	if len(raw) == 0 {
+		return faststatus.Resource{}, nil
+	}
+	var r faststatus.Resource
+	if err := (&r).UnmarshalBinary(raw); err != nil {
+		return faststatus.Resource{}, errors.Wrap(err, "unmarshaling scheduled resource from stored value")
+	}
+	return r, nil
+}
+
+// scheduleKey orders scheduled changes by Resource and then by time.
+func scheduleKey(key []byte, since time.Time) []byte {
+	k := make([]byte, len(key)+8)
+	copy(k, key)
+	binary.BigEndian.PutUint64(k[len(key):], uint64(since.UnixNano())^(1<<63))
+	return k
+}
@@ -0,0 +1,240 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/store"
+)
+
+func TestScheduleTakesEffect(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	now := time.Now()
+	id := faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+
+	current := faststatus.Resource{ID: id, Status: faststatus.Free, Since: now.Add(-time.Hour)}
+	if err := s.Save(current); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+	due := faststatus.Resource{ID: id, Status: faststatus.Occupied, Since: now.Add(-time.Minute)}
+	pending := faststatus.Resource{ID: id, Status: faststatus.Free, Since: now.Add(time.Hour)}
+	for _, r := range []faststatus.Resource{pending, due} {
+		if err := s.Schedule(r); err != nil {
+			t.Fatalf("unexpected error scheduling resource %+v: %+v", r, err)
+		}
+	}
+
+	got, err := s.Get(id)
+	if err != nil {
+		t.Fatalf("unexpected error getting resource: %+v", err)
+	}
+	if !got.Equal(due) {
+		t.Fatalf("getting resource with a due change: got %+v, expected %+v", got, due)
+	}
+
+	scheduled, err := s.Scheduled(id)
+	if err != nil {
+		t.Fatalf("unexpected error getting schedule: %+v", err)
+	}
+	if len(scheduled) != 1 || !scheduled[0].Equal(pending) {
+		t.Fatalf("getting schedule: got %+v, expected only %+v", scheduled, pending)
+	}
+
+	if err := s.Save(faststatus.Resource{ID: id, Status: faststatus.Busy, Since: now.Add(-30 * time.Minute)}); !faststatus.ConflictError(err) {
+		t.Fatalf("saving a resource older than a due change: got %+v, expected a conflict", err)
+	}
+}
+
+func TestScheduleRejectsOldChanges(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Free,
+		Since:  time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC),
+	}
+	if err := s.Save(r); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+	r.Status = faststatus.Busy
+	if err := s.Schedule(r); !faststatus.ConflictError(err) {
+		t.Fatalf("scheduling a change as of the current version: got %+v, expected a conflict", err)
+	}
+	if err := s.Schedule(faststatus.Resource{}); !store.ZeroValueError(err) {
+		t.Fatalf("scheduling a zero-value resource: got %+v, expected a zero-value error", err)
+	}
+}
+
+func TestApplyScheduled(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	start := time.Date(2017, 3, 14, 14, 0, 0, 0, time.UTC)
+	id := faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	other := faststatus.ID{0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01}
+
+	meeting := faststatus.Resource{ID: id, Status: faststatus.Occupied, Since: start}
+	afterMeeting := faststatus.Resource{ID: id, Status: faststatus.Free, Since: start.Add(time.Hour)}
+	superseded := faststatus.Resource{ID: other, Status: faststatus.Busy, Since: start}
+	override := faststatus.Resource{ID: other, Status: faststatus.Free, Since: start.Add(time.Minute)}
+	for _, r := range []faststatus.Resource{meeting, afterMeeting, superseded} {
+		if err := s.Schedule(r); err != nil {
+			t.Fatalf("unexpected error scheduling resource %+v: %+v", r, err)
+		}
+	}
+	if err := s.Save(override); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+
+	var notified []faststatus.Resource
+	cancel := s.Watch(func(r faststatus.Resource) {
+		notified = append(notified, r)
+	})
+	defer cancel()
+
+	applied, err := s.ApplyScheduled(start.Add(30 * time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error applying schedule: %+v", err)
+	}
+	if len(applied) != 1 || !applied[0].Equal(meeting) {
+		t.Fatalf("applying schedule: got %+v, expected only %+v", applied, meeting)
+	}
+	if len(notified) != 1 || !notified[0].Equal(meeting) {
+		t.Fatalf("watching schedule: got %+v, expected only %+v", notified, meeting)
+	}
+
+	got, err := s.Get(other)
+	if err != nil {
+		t.Fatalf("unexpected error getting resource: %+v", err)
+	}
+	if !got.Equal(override) {
+		t.Fatalf("getting resource with superseded change: got %+v, expected %+v", got, override)
+	}
+
+	applied, err = s.ApplyScheduled(start.Add(2 * time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error applying schedule: %+v", err)
+	}
+	if len(applied) != 1 || !applied[0].Equal(afterMeeting) {
+		t.Fatalf("applying schedule: got %+v, expected only %+v", applied, afterMeeting)
+	}
+	applied, err = s.ApplyScheduled(start.Add(2 * time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error applying schedule: %+v", err)
+	}
+	if len(applied) != 0 {
+		t.Fatalf("applying schedule twice: got %+v, expected nothing", applied)
+	}
+}
+
+func TestApplyScheduledOrdersByHLC(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	start := time.Date(2017, 3, 14, 14, 0, 0, 0, time.UTC)
+	id := faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+
+	// written a minute after its Since, by a node with a slow wall clock
+	meeting := faststatus.Resource{
+		ID:     id,
+		Status: faststatus.Occupied,
+		Since:  start,
+		HLC:    faststatus.HLC{Wall: start.Add(time.Minute).UnixNano(), Node: 1},
+	}
+	if err := s.Save(meeting); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+	early := faststatus.Resource{ID: id, Status: faststatus.Busy, Since: start.Add(30 * time.Second)}
+	if err := s.Schedule(early); !faststatus.ConflictError(err) {
+		t.Fatalf("scheduling a change before the HLC of the current version = %+v, expected a conflict error", err)
+	}
+	afterMeeting := faststatus.Resource{ID: id, Status: faststatus.Free, Since: start.Add(time.Hour)}
+	if err := s.Schedule(afterMeeting); err != nil {
+		t.Fatalf("unexpected error scheduling resource %+v: %+v", afterMeeting, err)
+	}
+
+	// due changes are ordered by HLC as of when they take effect
+	got, err := s.Get(id)
+	if err != nil {
+		t.Fatalf("unexpected error getting resource: %+v", err)
+	}
+	want := afterMeeting
+	want.HLC = faststatus.HLC{Wall: afterMeeting.Since.UnixNano()}
+	if !got.Equal(want) {
+		t.Fatalf("getting resource with due changes: got %+v, expected %+v", got, want)
+	}
+	applied, err := s.ApplyScheduled(start.Add(2 * time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error applying schedule: %+v", err)
+	}
+	if len(applied) != 1 || !applied[0].Equal(want) {
+		t.Fatalf("applying schedule: got %+v, expected only %+v", applied, want)
+	}
+}
+
+func TestRunSchedulerStopsWithContext(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Busy,
+		Since:  time.Now().Add(20 * time.Millisecond),
+	}
+	if err := s.Schedule(r); err != nil {
+		t.Fatalf("unexpected error scheduling resource: %+v", err)
+	}
+
+	applied := make(chan faststatus.Resource, 1)
+	cancelWatch := s.Watch(func(r faststatus.Resource) {
+		applied <- r
+	})
+	defer cancelWatch()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- s.RunScheduler(ctx, 5*time.Millisecond)
+	}()
+
+	select {
+	case got := <-applied:
+		if !got.Equal(r) {
+			t.Fatalf("scheduler applied %+v, expected %+v", got, r)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("scheduler did not apply scheduled change")
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("scheduler returned %+v, expected %+v", err, context.Canceled)
+	}
+}
+
+func TestRunSchedulerInterval(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	for _, interval := range []time.Duration{0, -time.Second} {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err := s.RunScheduler(ctx, interval)
+		cancel()
+		if err == nil || err == context.DeadlineExceeded {
+			t.Fatalf("RunScheduler(%s) = %+v, expected an interval error", interval, err)
+		}
+	}
+}
@@ -5,6 +5,8 @@ package store
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/pkg/errors"
@@ -12,12 +14,29 @@ import (
 	"github.com/lazyengineering/faststatus"
 )
 
-// Store persists the most recent version of Resources by ID
+// Store persists the most recent version of Resources by ID. Use Open for a
+// Store with its own bolt database, or set DB to one opened elsewhere.
 type Store struct {
 	DB *bolt.DB
+	// Skew limits how far ahead of the clock of the Store a saved
+	// Resource may be.
+	Skew faststatus.SkewPolicy
+
+	names *buckets
+
+	mu       sync.Mutex
+	watchers map[int]func(faststatus.Resource)
+	watchID  int
 }
 
-// Save persists a Resource to the Store iff it is the most recent
+// Save persists a Resource to the Store iff it is the most recent: by HLC
+// when both it and the stored version have one, otherwise by Since. A TTL set
+// by SaveWithTTL is renewed with every new version. An invalid Resource is
+// rejected with the error from its ValidateAt method, as of the clock of
+// the Store and with its Skew. A Resource without
+// Labels keeps the Labels of the stored version, so formats that cannot
+// carry them, such as text, do not erase them; an empty, non-nil map clears
+// them.
 func (s *Store) Save(r faststatus.Resource) error {
 	if s == nil {
 		return errorStoreNotInitialized
@@ -25,44 +44,34 @@ func (s *Store) Save(r faststatus.Resource) error {
 	if s.DB == nil {
 		return errorDBNotInitialized
 	}
-	if r.ID == (faststatus.ID{}) {
-		return dataError{noID: true}
+	now := time.Now()
+	if err := r.ValidateAt(now, s.Skew); err != nil {
+		return err
 	}
 	key, err := r.ID.MarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "marshaling binary key from resource ID")
 	}
 
+	if r, err = s.Skew.Check(r, now); err != nil {
+		return err
+	}
+
 	err = s.DB.Update(func(tx *bolt.Tx) error {
-		b, err := tx.CreateBucketIfNotExists(bucketName)
-		if err != nil {
-			return errors.Wrap(err, "creating bucket")
-		}
-
-		latest := b.Get(key)
-		if len(latest) > 0 {
-			latestResource := new(faststatus.Resource)
-			if err := latestResource.UnmarshalBinary(latest); err != nil {
-				return errors.Wrap(err, "unmarshaling latest stored resource")
-			}
-			if latestResource.Since.After(r.Since) {
-				return dataError{old: true}
-			}
-		}
-		payload, err := r.MarshalBinary()
-		if err != nil {
-			return errors.Wrap(err, "marshaling text for resource payload")
-		}
-		if err := b.Put(key, payload); err != nil {
-			return errors.Wrap(err, "putting resource in bucket")
-		}
-		return nil
+		r, err = s.save(tx, key, r, now)
+		return err
 	})
-	return errors.Wrap(err, "updating database with resource")
+	if err != nil {
+		return errors.Wrap(err, "updating database with resource")
+	}
+	s.notify(r)
+	return nil
 }
 
-// Get returns the most recent state of the Resource with the given valid ID
-// or a zero-value Resource if it does not exist in the Store.
+// Get returns the most recent state of the Resource with the given valid ID,
+// or an error for which faststatus.NotFoundError is true if it does not
+// exist in the Store. Scheduled changes that are due take effect even
+// before they are applied.
 func (s *Store) Get(id faststatus.ID) (faststatus.Resource, error) {
 	if s == nil {
 		return faststatus.Resource{}, errorStoreNotInitialized
@@ -78,25 +87,135 @@ func (s *Store) Get(id faststatus.ID) (faststatus.Resource, error) {
 		return faststatus.Resource{}, errors.Wrap(err, "failed to marshal key from id")
 	}
 
-	r := new(faststatus.Resource)
+	var r faststatus.Resource
 	err = s.DB.View(func(tx *bolt.Tx) error {
-		b := tx.Bucket(bucketName)
-		if b == nil {
-			return nil
-		}
-		raw := b.Get(key)
-		if len(raw) == 0 {
-			return nil
-		}
-		if err := r.UnmarshalBinary(raw); err != nil {
-			return errors.Wrap(err, "unmarshaling resource from stored value")
-		}
-		return nil
+		r, err = s.latest(tx, key, time.Now())
+		return err
 	})
 	if err != nil {
 		return faststatus.Resource{}, errors.Wrap(err, "viewing database with resource")
 	}
-	return *r, nil
+	if r.ID == (faststatus.ID{}) {
+		return faststatus.Resource{}, notFoundError{id: id}
+	}
+	return r, nil
+}
+
+// save puts the Resource in the bucket iff it is the most recent version as of now.
+func (s *Store) save(tx *bolt.Tx, key []byte, r faststatus.Resource, now time.Time) (faststatus.Resource, error) {
+	b, err := tx.CreateBucketIfNotExists(s.buckets().resources)
+	if err != nil {
+		return faststatus.Resource{}, errors.Wrap(err, "creating bucket")
+	}
+
+	latestResource, err := s.latest(tx, key, now)
+	if err != nil {
+		return faststatus.Resource{}, err
+	}
+	if supersedes(latestResource, r) {
+		return faststatus.Resource{}, dataError{old: true}
+	}
+	if r.Labels == nil {
+		r.Labels = latestResource.Labels
+	}
+	payload, err := r.MarshalBinary()
+	if err != nil {
+		return faststatus.Resource{}, errors.Wrap(err, "marshaling text for resource payload")
+	}
+	if err := b.Put(key, payload); err != nil {
+		return faststatus.Resource{}, errors.Wrap(err, "putting resource in bucket")
+	}
+
+	e, ok, err := s.expiryOf(tx, key)
+	if err != nil || !ok {
+		return r, err
+	}
+	e.At = now.Add(e.TTL)
+	return r, s.putExpiry(tx, key, e)
+}
+
+// supersedes reports whether a was written after b. When both have an
+// HLC, it orders them, so that skewed wall clocks cannot reorder writes;
+// otherwise they are ordered by Since.
+func supersedes(a, b faststatus.Resource) bool {
+	if !a.HLC.IsZero() && !b.HLC.IsZero() {
+		return a.HLC.After(b.HLC)
+	}
+	return a.Since.After(b.Since)
+}
+
+// derive returns the version of r with the given Status and Since that
+// occupancy changes and expiry fallbacks write, so the Labels and Message
+// of r carry over. Until is dropped once it is no longer after Since.
+func derive(r faststatus.Resource, id faststatus.ID, status faststatus.Status, since time.Time) faststatus.Resource {
+	r.ID, r.Status, r.Since, r.HLC = id, status, since, hlcAt(r.HLC, since)
+	if !r.Until.After(since) {
+		r.Until = time.Time{}
+	}
+	return r
+}
+
+// hlcAt returns the HLC of a version that the Store writes itself, taking
+// effect at t, to order it against a version with the HLC h: t as the Wall,
+// or no HLC when h has none, so that both are ordered by Since as before.
+func hlcAt(h faststatus.HLC, t time.Time) faststatus.HLC {
+	if h.IsZero() {
+		return faststatus.HLC{}
+	}
+	return faststatus.HLC{Wall: t.UnixNano()}
+}
+
+// latest returns the most recent version of the Resource as of now, which
+// may be a scheduled change that is due or the fallback for an expired
+// Resource, or a zero-value Resource if none is stored. Both are ordered
+// against the stored version as supersedes orders writes, with an HLC as of
+// when they take effect if the stored version has one.
+func (s *Store) latest(tx *bolt.Tx, key []byte, now time.Time) (faststatus.Resource, error) {
+	r, err := s.stored(tx, key)
+	if err != nil {
+		return faststatus.Resource{}, err
+	}
+	due, err := s.lastDue(tx, key, now)
+	if err != nil {
+		return faststatus.Resource{}, err
+	}
+	if due.HLC.IsZero() && !due.Since.IsZero() {
+		due.HLC = hlcAt(r.HLC, due.Since)
+	}
+	if supersedes(due, r) {
+		r = due
+	}
+	e, ok, err := s.expiryOf(tx, key)
+	if err != nil {
+		return faststatus.Resource{}, err
+	}
+	if !ok {
+		return r, nil
+	}
+	var id faststatus.ID
+	copy(id[:], key)
+	if fallback, expired := e.expired(id, r, now); expired && supersedes(fallback, r) {
+		return fallback, nil
+	}
+	return r, nil
+}
+
+// stored returns the most recent stored version of the Resource, or a
+// zero-value Resource if none is stored.
+func (s *Store) stored(tx *bolt.Tx, key []byte) (faststatus.Resource, error) {
+	b := tx.Bucket(s.buckets().resources)
+	if b == nil {
+		return faststatus.Resource{}, nil
+	}
+	raw := b.Get(key)
+	if len(raw) == 0 {
+		return faststatus.Resource{}, nil
+	}
+	var r faststatus.Resource
+	if err := (&r).UnmarshalBinary(raw); err != nil {
+		return faststatus.Resource{}, errors.Wrap(err, "unmarshaling resource from stored value")
+	}
+	return r, nil
 }
 
 var (
@@ -104,6 +223,42 @@ var (
 	errorDBNotInitialized    = fmt.Errorf("no bolt database for store")
 )
 
-var (
-	bucketName = []byte("faststatus/store")
-)
+// buckets names the bolt buckets of a Store.
+type buckets struct {
+	resources []byte
+	groups    []byte
+	occupancy []byte
+	schedule  []byte
+	expiry    []byte
+	meta      []byte
+}
+
+// newBuckets returns the names of the buckets of a Store under a prefix.
+func newBuckets(prefix string) *buckets {
+	return &buckets{
+		resources: []byte(prefix + "/store"),
+		groups:    []byte(prefix + "/groups"),
+		occupancy: []byte(prefix + "/occupancy"),
+		schedule:  []byte(prefix + "/schedule"),
+		expiry:    []byte(prefix + "/expiry"),
+		meta:      []byte(prefix + "/meta"),
+	}
+}
+
+// all returns the name of every bucket.
+func (b *buckets) all() [][]byte {
+	return [][]byte{b.resources, b.groups, b.occupancy, b.schedule, b.expiry, b.meta}
+}
+
+var defaultBuckets = newBuckets(defaultBucketPrefix)
+
+const defaultBucketPrefix = "faststatus"
+
+// buckets returns the names of the buckets of the Store: the default ones
+// unless it was opened with WithBucketPrefix.
+func (s *Store) buckets() *buckets {
+	if s.names == nil {
+		return defaultBuckets
+	}
+	return s.names
+}
@@ -4,6 +4,7 @@
 package store_test
 
 import (
+	"errors"
 	"io/ioutil"
 	"os"
 	"sync"
@@ -373,10 +374,10 @@ func TestGet(t *testing.T) {
 			func(e error) bool { return e == nil },
 			stockResources["valid"],
 		},
-		{"Get should return a zero-value Resource when none found",
+		{"Get should return a not-found error when none found",
 			&store.Store{DB: db},
 			stockResources["not-found"].ID,
-			func(e error) bool { return e == nil },
+			faststatus.NotFoundError,
 			faststatus.Resource{},
 		},
 	}
@@ -528,3 +529,227 @@ func tempfile(t *testing.T) (string, func()) {
 		os.Remove(fileName)
 	}
 }
+
+func TestSaveGetMessageUntil(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	since := time.Date(2017, 3, 14, 15, 9, 0, 0, time.UTC)
+	r := faststatus.Resource{
+		ID:      faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status:  faststatus.Busy,
+		Since:   since,
+		Message: "in a 1:1, back at 15:30",
+		Until:   since.Add(21 * time.Minute),
+	}
+	if err := s.Save(r); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+	got, err := s.Get(r.ID)
+	if err != nil || !got.Equal(r) {
+		t.Fatalf("Get() = %+v, %+v, expected %+v", got, err, r)
+	}
+
+	r.Until = since
+	if err := s.Save(r); err == nil {
+		t.Fatalf("saving a resource with Until not after Since returned no error")
+	}
+}
+
+func TestSaveKeepsLabels(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	labelled := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Free,
+		Since:  time.Date(2017, 3, 14, 15, 9, 0, 0, time.UTC),
+		Labels: map[string]string{"floor": "3", "type": "room"},
+	}
+	if err := s.Save(labelled); err != nil {
+		t.Fatalf("unexpected error saving labelled resource: %+v", err)
+	}
+
+	var update faststatus.Resource
+	txt := "0123456789abcdef0123456789abcdef busy 2017-03-14T15:10:00Z"
+	if err := (&update).UnmarshalText([]byte(txt)); err != nil {
+		t.Fatalf("unexpected error unmarshaling %q: %+v", txt, err)
+	}
+	if err := s.Save(update); err != nil {
+		t.Fatalf("unexpected error saving text update: %+v", err)
+	}
+	want := update
+	want.Labels = labelled.Labels
+	if got, err := s.Get(update.ID); err != nil || !got.Equal(want) {
+		t.Fatalf("Get() = %+v, %+v, expected %+v", got, err, want)
+	}
+
+	cleared := update
+	cleared.Since = cleared.Since.Add(time.Minute)
+	cleared.Labels = map[string]string{}
+	if err := s.Save(cleared); err != nil {
+		t.Fatalf("unexpected error saving resource with empty Labels: %+v", err)
+	}
+	if got, err := s.Get(update.ID); err != nil || len(got.Labels) != 0 {
+		t.Fatalf("Get() = %+v, %+v, expected no Labels", got, err)
+	}
+}
+
+func TestSaveOrdersByHLC(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	since := time.Date(2017, 3, 14, 15, 9, 0, 0, time.UTC)
+	first := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Busy,
+		Since:  since,
+		HLC:    faststatus.HLC{Wall: since.UnixNano(), Node: 1},
+	}
+	if err := s.Save(first); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+
+	// a node with a slow wall clock writes after the first
+	second := first
+	second.Status = faststatus.Free
+	second.Since = since.Add(-time.Minute)
+	second.HLC = faststatus.HLC{Wall: since.UnixNano(), Logical: 1, Node: 2}
+	if err := s.Save(second); err != nil {
+		t.Fatalf("saving a resource with a later HLC and earlier Since = %+v, expected no error", err)
+	}
+
+	// a node with a fast wall clock wrote before the second
+	stale := first
+	stale.Status = faststatus.Occupied
+	stale.Since = since.Add(time.Minute)
+	stale.HLC = faststatus.HLC{Wall: since.UnixNano(), Logical: 0, Node: 3}
+	if err := s.Save(stale); !faststatus.ConflictError(err) {
+		t.Fatalf("saving a resource with an earlier HLC = %+v, expected a conflict error", err)
+	}
+
+	got, err := s.Get(first.ID)
+	if err != nil || !got.Equal(second) {
+		t.Fatalf("Get() = %+v, %+v, expected %+v", got, err, second)
+	}
+
+	// without an HLC, Since orders the writes
+	later := second
+	later.HLC = faststatus.HLC{}
+	later.Since = since
+	if err := s.Save(later); err != nil {
+		t.Fatalf("saving a resource without an HLC and a later Since = %+v, expected no error", err)
+	}
+}
+
+func TestSaveSkew(t *testing.T) {
+	id := faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	future := time.Now().Add(time.Hour)
+	testCases := []struct {
+		name      string
+		maxSkew   time.Duration
+		clamp     bool
+		input     faststatus.Resource
+		wantSkew  bool
+		wantSince func(time.Time) bool
+	}{
+		{"no max skew",
+			0, false,
+			faststatus.Resource{ID: id, Status: faststatus.Busy, Since: future},
+			false,
+			func(since time.Time) bool { return since.Equal(future) },
+		},
+		{"within max skew",
+			2 * time.Hour, false,
+			faststatus.Resource{ID: id, Status: faststatus.Busy, Since: future},
+			false,
+			func(since time.Time) bool { return since.Equal(future) },
+		},
+		{"rejected",
+			time.Minute, false,
+			faststatus.Resource{ID: id, Status: faststatus.Busy, Since: future},
+			true,
+			nil,
+		},
+		{"clamped",
+			time.Minute, true,
+			faststatus.Resource{ID: id, Status: faststatus.Busy, Since: future},
+			false,
+			func(since time.Time) bool { return since.Before(future) && !since.After(time.Now()) },
+		},
+		{"clamped keeps until",
+			time.Minute, true,
+			faststatus.Resource{ID: id, Status: faststatus.Busy, Since: future, Until: future.Add(time.Hour)},
+			false,
+			func(since time.Time) bool { return since.Before(future) },
+		},
+		{"hlc is never clamped",
+			time.Minute, true,
+			faststatus.Resource{ID: id, Status: faststatus.Busy, Since: time.Now(), HLC: faststatus.HLC{Wall: future.UnixNano()}},
+			true,
+			nil,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			db, cleanup := newEmptyDB(t)
+			defer cleanup()
+
+			s := &store.Store{DB: db, Skew: faststatus.SkewPolicy{Max: tc.maxSkew, Clamp: tc.clamp}}
+			err := s.Save(tc.input)
+			if faststatus.SkewError(err) != tc.wantSkew {
+				t.Fatalf("Save(%+v) = %+v, expected skew error? %t", tc.input, err, tc.wantSkew)
+			}
+			if tc.wantSkew {
+				if faststatus.ConflictError(err) {
+					t.Fatalf("Save(%+v) = %+v, expected not a conflict error", tc.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Save(%+v) = %+v, expected no error", tc.input, err)
+			}
+			got, err := s.Get(id)
+			if err != nil {
+				t.Fatalf("unexpected error getting resource: %+v", err)
+			}
+			if got.Status != tc.input.Status || !got.Until.Equal(tc.input.Until) || !tc.wantSince(got.Since) {
+				t.Fatalf("Get() = %+v after saving %+v, not expected", got, tc.input)
+			}
+		})
+	}
+}
+
+func TestSaveValidates(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Labels: map[string]string{"floor": "3/4"},
+	}
+	err := s.Save(r)
+	var verr faststatus.ValidationError
+	if !errors.As(err, &verr) || len(verr) != 2 {
+		t.Fatalf("Save(%+v) = %+v, expected a ValidationError for Since and Labels", r, err)
+	}
+	if _, err := s.Get(r.ID); !faststatus.NotFoundError(err) {
+		t.Fatalf("Get() after an invalid Save = %+v, expected not found", err)
+	}
+
+	s.Skew.Max = time.Minute
+	future := faststatus.Resource{Status: faststatus.Busy, Since: time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)}
+	err = s.Save(future)
+	verr = nil
+	if !errors.As(err, &verr) || len(verr) != 2 || verr[0].Field != "ID" || verr[1].Field != "Since" {
+		t.Fatalf("Save(%+v) = %+v, expected a ValidationError for ID and Since", future, err)
+	}
+	if !faststatus.SkewError(err) {
+		t.Fatalf("SkewError(%+v) = false, expected true", err)
+	}
+}
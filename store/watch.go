@@ -0,0 +1,41 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package store
+
+import (
+	"github.com/lazyengineering/faststatus"
+)
+
+// Watch registers fn to be called with each Resource as it becomes the most
+// recent version in the Store, whether it was saved directly or applied by
+// the scheduler. Calls are made after the change is committed, from the
+// goroutine that made it, so fn should not block. The returned function
+// removes the registration.
+func (s *Store) Watch(fn func(faststatus.Resource)) (cancel func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watchers == nil {
+		s.watchers = make(map[int]func(faststatus.Resource))
+	}
+	id := s.watchID
+	s.watchID++
+	s.watchers[id] = fn
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.watchers, id)
+	}
+}
+
+func (s *Store) notify(r faststatus.Resource) {
+	s.mu.Lock()
+	watchers := make([]func(faststatus.Resource), 0, len(s.watchers))
+	for _, fn := range s.watchers {
+		watchers = append(watchers, fn)
+	}
+	s.mu.Unlock()
+	for _, fn := range watchers {
+		fn(r)
+	}
+}
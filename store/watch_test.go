@@ -0,0 +1,50 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+	"github.com/lazyengineering/faststatus/store"
+)
+
+func TestWatch(t *testing.T) {
+	db, cleanup := newEmptyDB(t)
+	defer cleanup()
+
+	s := &store.Store{DB: db}
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Busy,
+		Since:  time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC),
+	}
+
+	var notified []faststatus.Resource
+	cancel := s.Watch(func(r faststatus.Resource) {
+		notified = append(notified, r)
+	})
+
+	if err := s.Save(r); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+	old := r
+	old.Since = r.Since.Add(-time.Minute)
+	if err := s.Save(old); !faststatus.ConflictError(err) {
+		t.Fatalf("saving old resource: got %+v, expected a conflict", err)
+	}
+	if len(notified) != 1 || !notified[0].Equal(r) {
+		t.Fatalf("watching saves: got %+v, expected only %+v", notified, r)
+	}
+
+	cancel()
+	r.Since = r.Since.Add(time.Minute)
+	if err := s.Save(r); err != nil {
+		t.Fatalf("unexpected error saving resource: %+v", err)
+	}
+	if len(notified) != 1 {
+		t.Fatalf("watching after cancel: got %+v, expected no more notifications", notified)
+	}
+}
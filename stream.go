@@ -0,0 +1,71 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// An Encoder writes Resources to a stream in the text representation, one
+// per line.
+type Encoder struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewEncoder returns a new Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes the text representation of the Resource to the stream,
+// followed by a newline.
+func (enc *Encoder) Encode(r Resource) error {
+	txt, err := r.MarshalText()
+	if err != nil {
+		return fmt.Errorf("marshaling resource to text: %w", err)
+	}
+	enc.buf = append(append(enc.buf[:0], txt...), '\n')
+	if _, err := enc.w.Write(enc.buf); err != nil {
+		return fmt.Errorf("writing resource to stream: %w", err)
+	}
+	return nil
+}
+
+// A Decoder reads Resources from a stream of lines in the text
+// representation. Blank lines and lines starting with '#' are skipped, and
+// a trailing carriage return is ignored.
+type Decoder struct {
+	s    *bufio.Scanner
+	line int
+}
+
+// NewDecoder returns a new Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{s: bufio.NewScanner(r)}
+}
+
+// Decode reads the next Resource from the stream. At the end of the stream
+// it returns io.EOF. Errors parsing a line report the line number, and
+// decoding may continue with the next line.
+func (dec *Decoder) Decode(r *Resource) error {
+	for dec.s.Scan() {
+		dec.line++
+		line := bytes.TrimSuffix(dec.s.Bytes(), []byte("\r"))
+		if len(bytes.TrimSpace(line)) == 0 || line[0] == '#' {
+			continue
+		}
+		if err := r.UnmarshalText(line); err != nil {
+			return fmt.Errorf("line %d: parsing resource from text: %w", dec.line, err)
+		}
+		return nil
+	}
+	if err := dec.s.Err(); err != nil {
+		return fmt.Errorf("line %d: reading from stream: %w", dec.line+1, err)
+	}
+	return io.EOF
+}
@@ -0,0 +1,120 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+func TestEncoderDecoder(t *testing.T) {
+	f := func(resources []faststatus.Resource) bool {
+		var buf bytes.Buffer
+		enc := faststatus.NewEncoder(&buf)
+		for _, r := range resources {
+			if err := enc.Encode(r); err != nil {
+				return false
+			}
+		}
+		dec := faststatus.NewDecoder(&buf)
+		for _, want := range resources {
+			var got faststatus.Resource
+			if err := dec.Decode(&got); err != nil || !got.Equal(want) {
+				return false
+			}
+		}
+		return dec.Decode(new(faststatus.Resource)) == io.EOF
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEncoderLines(t *testing.T) {
+	r := faststatus.Resource{
+		ID:     faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		Status: faststatus.Busy,
+	}
+	var buf bytes.Buffer
+	enc := faststatus.NewEncoder(&buf)
+	for i := 0; i < 2; i++ {
+		if err := enc.Encode(r); err != nil {
+			t.Fatalf("Encode() = %+v, expected no error", err)
+		}
+	}
+	want := strings.Repeat(r.String()+"\n", 2)
+	if got := buf.String(); got != want {
+		t.Fatalf("encoded %q, expected %q", got, want)
+	}
+}
+
+func TestEncoderWriteError(t *testing.T) {
+	enc := faststatus.NewEncoder(errWriter{})
+	if err := enc.Encode(faststatus.NewResource()); err == nil {
+		t.Fatalf("Encode() = <nil>, expected error")
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	const (
+		first  = "01234567-89ab-cdef-0123-456789abcdef busy 2017-03-14T15:09:26Z"
+		second = "23456789-abcd-ef01-2345-6789abcdef01 free 2017-03-14T15:10:00Z"
+	)
+	testCases := []struct {
+		name      string
+		input     string
+		wantCount int
+		wantError string
+	}{
+		{"empty", "", 0, ""},
+		{"no trailing newline", first + "\n" + second, 2, ""},
+		{"comments and blank lines", "# rooms\n\n" + first + "\n  \n#" + second + "\n", 1, ""},
+		{"carriage returns", first + "\r\n" + second + "\r\n", 2, ""},
+		{"bad line", first + "\nnot a resource\n" + second + "\n", 2, "line 2:"},
+		{"too long", first + "\n" + strings.Repeat("a", 1<<17) + "\n", 1, "line 2:"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dec := faststatus.NewDecoder(strings.NewReader(tc.input))
+			var count int
+			for {
+				var r faststatus.Resource
+				err := dec.Decode(&r)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					if tc.wantError == "" || !strings.HasPrefix(err.Error(), tc.wantError) {
+						t.Fatalf("Decode() = %+v, expected error starting %q", err, tc.wantError)
+					}
+					tc.wantError = ""
+					if strings.Contains(err.Error(), "reading from stream") {
+						break
+					}
+					continue
+				}
+				count++
+			}
+			if tc.wantError != "" {
+				t.Fatalf("Decode() never failed, expected error starting %q", tc.wantError)
+			}
+			if count != tc.wantCount {
+				t.Fatalf("decoded %d resources, expected %d", count, tc.wantCount)
+			}
+		})
+	}
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, fmt.Errorf("an error")
+}
@@ -0,0 +1,108 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A FieldError describes why one field of a Resource is invalid.
+type FieldError struct {
+	// Field is the name of the field, like "Since".
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// A ValidationError lists every invalid field of a Resource. It matches
+// the errors of each of its fields with errors.Is and errors.As, so that,
+// for example, ConflictError and IsOutOfRange see through it.
+type ValidationError []*FieldError
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return "invalid resource: " + strings.Join(msgs, "; ")
+}
+
+func (e ValidationError) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fe := range e {
+		errs[i] = fe
+	}
+	return errs
+}
+
+// Validate returns a ValidationError listing every field that keeps the
+// Resource from being saved: a zero ID, an out-of-range Status, a zero
+// Since, invalid Labels, a Message that cannot be encoded, or an Until
+// that is not after Since. A Since too far in the future is left to
+// ValidateAt, since only the receiver knows its clock.
+func (r Resource) Validate() error {
+	if errs := r.validate(); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateAt is like Validate, but also lists a Since or HLC further ahead
+// of now than the SkewPolicy allows, with an error for which SkewError is
+// true. A Since that the policy would clamp is not listed.
+func (r Resource) ValidateAt(now time.Time, p SkewPolicy) error {
+	errs := r.validate()
+	if limit, ok := p.Limit(now); ok {
+		if !r.HLC.IsZero() && r.HLC.Wall > limit.UnixNano() {
+			errs = append(errs, &FieldError{Field: "HLC", Err: skewError{field: "HLC", t: time.Unix(0, r.HLC.Wall), max: p.Max}})
+		}
+		if r.Since.After(limit) && !p.Clamp {
+			errs = append(errs, &FieldError{Field: "Since", Err: skewError{field: "Since", t: r.Since, max: p.Max}})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (r Resource) validate() ValidationError {
+	var errs ValidationError
+	add := func(field string, err error) {
+		errs = append(errs, &FieldError{Field: field, Err: err})
+	}
+	if r.ID == (ID{}) {
+		add("ID", ErrZeroValue)
+	}
+	if r.Status > Unknown {
+		add("Status", errOutOfRange)
+	}
+	if r.Since.IsZero() {
+		add("Since", ErrZeroValue)
+	}
+	for _, k := range sortedKeys(r.Labels) {
+		switch {
+		case !validLabelKey(k):
+			add("Labels", fmt.Errorf("invalid key %q", k))
+		case !validLabelValue(r.Labels[k]):
+			add("Labels", fmt.Errorf("invalid value %q for key %q", r.Labels[k], k))
+		}
+	}
+	if err := checkMessage(r.Message); err != nil {
+		add("Message", err)
+	}
+	if err := checkUntil(r.Since, r.Until); err != nil {
+		add("Until", err)
+	}
+	return errs
+}
@@ -0,0 +1,156 @@
+// Copyright 2017 Jesse Allen. All rights reserved
+// Released under the MIT license found in the LICENSE file.
+
+package faststatus_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lazyengineering/faststatus"
+)
+
+func TestResourceValidate(t *testing.T) {
+	id := faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	since := time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+	testCases := []struct {
+		name       string
+		input      faststatus.Resource
+		wantFields []string
+	}{
+		{"valid",
+			faststatus.Resource{ID: id, Status: faststatus.Busy, Since: since, Labels: map[string]string{"example.com/floor": "3"}, Message: "in a 1:1", Until: since.Add(time.Hour)},
+			nil,
+		},
+		{"zero value",
+			faststatus.Resource{},
+			[]string{"ID", "Since"},
+		},
+		{"out of range status",
+			faststatus.Resource{ID: id, Status: faststatus.Unknown + 1, Since: since},
+			[]string{"Status"},
+		},
+		{"invalid labels",
+			faststatus.Resource{ID: id, Since: since, Labels: map[string]string{"": "x", "floor": "3/4", "ok": "yes"}},
+			[]string{"Labels", "Labels"},
+		},
+		{"every field",
+			faststatus.Resource{Status: faststatus.Unknown + 1, Labels: map[string]string{"a b": ""}, Message: "back\nsoon", Until: since},
+			[]string{"ID", "Status", "Since", "Labels", "Message"},
+		},
+		{"until before since",
+			faststatus.Resource{ID: id, Since: since, Message: strings.Repeat("x", faststatus.MaxMessageLen+1), Until: since.Add(-time.Hour)},
+			[]string{"Message", "Until"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.input.Validate()
+			if tc.wantFields == nil {
+				if err != nil {
+					t.Fatalf("%+v.Validate() = %+v, expected no error", tc.input, err)
+				}
+				return
+			}
+			var verr faststatus.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("%+v.Validate() = %+v, expected a ValidationError", tc.input, err)
+			}
+			var got []string
+			for _, fe := range verr {
+				got = append(got, fe.Field)
+			}
+			if strings.Join(got, ",") != strings.Join(tc.wantFields, ",") {
+				t.Fatalf("%+v.Validate() fields = %v, expected %v", tc.input, got, tc.wantFields)
+			}
+		})
+	}
+}
+
+func TestResourceValidateAt(t *testing.T) {
+	id := faststatus.ID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	now := time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+	future := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name       string
+		policy     faststatus.SkewPolicy
+		input      faststatus.Resource
+		wantFields []string
+	}{
+		{"no policy",
+			faststatus.SkewPolicy{},
+			faststatus.Resource{ID: id, Since: future, HLC: faststatus.HLC{Wall: future.UnixNano()}},
+			nil,
+		},
+		{"within max",
+			faststatus.SkewPolicy{Max: time.Minute},
+			faststatus.Resource{ID: id, Since: now.Add(time.Minute), HLC: faststatus.HLC{Wall: now.Add(time.Minute).UnixNano()}},
+			nil,
+		},
+		{"future since with zero id",
+			faststatus.SkewPolicy{Max: time.Minute},
+			faststatus.Resource{Since: future},
+			[]string{"ID", "Since"},
+		},
+		{"future hlc",
+			faststatus.SkewPolicy{Max: time.Minute},
+			faststatus.Resource{ID: id, Since: now, HLC: faststatus.HLC{Wall: future.UnixNano()}},
+			[]string{"HLC"},
+		},
+		{"clamped since",
+			faststatus.SkewPolicy{Max: time.Minute, Clamp: true},
+			faststatus.Resource{ID: id, Since: future, HLC: faststatus.HLC{Wall: future.UnixNano()}},
+			[]string{"HLC"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.input.ValidateAt(now, tc.policy)
+			if tc.wantFields == nil {
+				if err != nil {
+					t.Fatalf("%+v.ValidateAt(%s, %+v) = %+v, expected no error", tc.input, now, tc.policy, err)
+				}
+				return
+			}
+			var verr faststatus.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("%+v.ValidateAt(%s, %+v) = %+v, expected a ValidationError", tc.input, now, tc.policy, err)
+			}
+			var got []string
+			for _, fe := range verr {
+				got = append(got, fe.Field)
+			}
+			if strings.Join(got, ",") != strings.Join(tc.wantFields, ",") {
+				t.Fatalf("%+v.ValidateAt(%s, %+v) fields = %v, expected %v", tc.input, now, tc.policy, got, tc.wantFields)
+			}
+			if tc.input.ID != (faststatus.ID{}) && !faststatus.SkewError(err) {
+				t.Fatalf("SkewError(%+v) = false, expected true", err)
+			}
+		})
+	}
+}
+
+func TestValidationErrorMatches(t *testing.T) {
+	err := faststatus.Resource{Status: faststatus.Unknown + 1}.Validate()
+	if !errors.Is(err, faststatus.ErrZeroValue) {
+		t.Fatalf("errors.Is(%+v, ErrZeroValue) = false, expected true", err)
+	}
+	if !faststatus.IsOutOfRange(err) {
+		t.Fatalf("IsOutOfRange(%+v) = false, expected true", err)
+	}
+	if faststatus.ConflictError(err) {
+		t.Fatalf("ConflictError(%+v) = true, expected false", err)
+	}
+	want := "invalid resource: ID: zero value; Status: status error: Status not in valid range; Since: zero value"
+	if err.Error() != want {
+		t.Fatalf("Error() = %q, expected %q", err.Error(), want)
+	}
+	var fe *faststatus.FieldError
+	if !errors.As(err, &fe) || fe.Field != "ID" {
+		t.Fatalf("errors.As(%+v, *FieldError) = %+v, expected the ID field", err, fe)
+	}
+}